@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"film_library/config"
+	"film_library/pkg/storage"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up          apply all pending migrations
+  down [N]    roll back the last N applied migrations (default 1)
+  status      list migrations and when they were applied`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	viperInstance, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Cannot load config. Error: {%s}", err.Error())
+	}
+
+	cfg, err := config.ParseConfig(viperInstance)
+	if err != nil {
+		log.Fatalf("Cannot parse config. Error: {%s}", err.Error())
+	}
+
+	db, err := storage.InitPsqlDB(cfg)
+	if err != nil {
+		log.Fatalf("Cannot connect to database. Error: {%s}", err.Error())
+	}
+	defer db.Close()
+
+	migrator, err := storage.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Cannot load migrations. Error: {%s}", err.Error())
+	}
+
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatalf("Cannot apply migrations. Error: {%s}", err.Error())
+		}
+		log.Printf("Applied %d migrations", applied)
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			if steps, err = strconv.Atoi(os.Args[2]); err != nil || steps <= 0 {
+				log.Fatalf("N should be a positive number")
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Fatalf("Cannot roll back migrations. Error: {%s}", err.Error())
+		}
+		log.Printf("Rolled back %d migrations", reverted)
+	case "status":
+		data, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Cannot get migrations status. Error: {%s}", err.Error())
+		}
+		for _, mg := range data {
+			appliedAt := "pending"
+			if mg.AppliedAt != nil {
+				appliedAt = mg.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", mg.Version, mg.Name, appliedAt)
+		}
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+}
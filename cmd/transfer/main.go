@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"film_library/config"
+	"film_library/internal/transfer"
+	"film_library/internal/transfer/provider"
+	"film_library/internal/transfer/repository"
+	"film_library/internal/transfer/usecase"
+	"film_library/pkg/storage"
+	"flag"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+)
+
+const usage = `usage: transfer <command> [flags]
+
+commands:
+  import [-format csv|json|jsonl|zip] [-dry-run] [-batch N] FILE
+              import actors, films, relations, crew and genres; the format defaults to
+              the file extension, zip is a csv export, and the report is printed as JSON
+  export [-format jsonl|csv|json] [-o FILE]
+              export every actor, film, relation, crew job and genre to FILE or stdout;
+              csv is a zip of one file per kind
+  sync -provider imdb -dir DIR [-types movie,...] [-titles tt...,...] [-dry-run] [-batch N]
+  sync -provider http -name NAME -url URL [-dry-run] [-batch N]
+              add or update films, people and credits from a metadata
+              provider: the IMDb datasets in DIR or a JSON Lines service`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "import":
+		runImport(os.Args[2:])
+	case "export":
+		runExport(os.Args[2:])
+	case "sync":
+		runSync(os.Args[2:])
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+}
+
+func runImport(args []string) {
+	var (
+		flags  = flag.NewFlagSet("import", flag.ExitOnError)
+		params = &transfer.ImportParams{}
+	)
+	flags.StringVar(&params.Format, "format", "", "csv, json, jsonl or zip")
+	flags.BoolVar(&params.DryRun, "dry-run", false, "validate and write everything, then roll back")
+	flags.IntVar(&params.BatchSize, "batch", 0, "records committed in one transaction")
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+	path := flags.Arg(0)
+	if params.Format == "" {
+		params.Format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Cannot open file. Error: {%s}", err.Error())
+	}
+	defer file.Close()
+
+	db := connect()
+	defer db.Close()
+
+	transferUC := usecase.NewTransferUsecase(repository.NewPostgresRepository(db))
+
+	report, err := transferUC.Import(context.Background(), file, params)
+	if err != nil {
+		log.Fatalf("Cannot import. Error: {%s}", err.Error())
+	}
+
+	rawReport, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(rawReport))
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func runExport(args []string) {
+	var (
+		flags  = flag.NewFlagSet("export", flag.ExitOnError)
+		format = flags.String("format", "", "jsonl, csv or json; by the extension of -o, jsonl for stdout")
+		path   = flags.String("o", "", "output file, stdout by default")
+	)
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+	if *format == "" {
+		switch ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(*path)), "."); ext {
+		case "", "jsonl":
+			*format = "jsonl"
+		case "zip":
+			*format = "csv"
+		default:
+			*format = ext
+		}
+	}
+
+	out := os.Stdout
+	if *path != "" {
+		file, err := os.Create(*path)
+		if err != nil {
+			log.Fatalf("Cannot create file. Error: {%s}", err.Error())
+		}
+		defer file.Close()
+		out = file
+	}
+
+	db := connect()
+	defer db.Close()
+
+	transferUC := usecase.NewTransferUsecase(repository.NewPostgresRepository(db))
+
+	w := bufio.NewWriter(out)
+	if err := transferUC.Export(context.Background(), w, *format); err != nil {
+		log.Fatalf("Cannot export. Error: {%s}", err.Error())
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("Cannot write. Error: {%s}", err.Error())
+	}
+}
+
+func runSync(args []string) {
+	var (
+		flags    = flag.NewFlagSet("sync", flag.ExitOnError)
+		params   = &transfer.ImportParams{}
+		kind     = flags.String("provider", "", "imdb or http")
+		dir      = flags.String("dir", "", "imdb: directory of the datasets")
+		types    = flags.String("types", "movie", "imdb: comma separated title types")
+		titles   = flags.String("titles", "", "imdb: comma separated ids of the only titles synced")
+		name     = flags.String("name", "", "http: name the ids of the service are kept under")
+		endpoint = flags.String("url", "", "http: address of the records")
+	)
+	flags.BoolVar(&params.DryRun, "dry-run", false, "validate and write everything, then roll back")
+	flags.IntVar(&params.BatchSize, "batch", 0, "records committed in one transaction")
+	_ = flags.Parse(args)
+
+	var metadataProvider transfer.MetadataProvider
+	switch {
+	case *kind == "imdb" && *dir != "":
+		metadataProvider = provider.NewIMDbProvider(*dir, splitList(*types), splitList(*titles))
+	case *kind == "http" && *name != "" && *endpoint != "":
+		metadataProvider = provider.NewHTTPProvider(*name, *endpoint, &http.Client{})
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	db := connect()
+	defer db.Close()
+
+	transferUC := usecase.NewTransferUsecase(repository.NewPostgresRepository(db))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := transferUC.Sync(ctx, metadataProvider, params)
+	if err != nil {
+		log.Fatalf("Cannot sync. Error: {%s}", err.Error())
+	}
+
+	rawReport, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(rawReport))
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+
+	return strings.Split(list, ",")
+}
+
+func connect() *sqlx.DB {
+	viperInstance, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Cannot load config. Error: {%s}", err.Error())
+	}
+
+	cfg, err := config.ParseConfig(viperInstance)
+	if err != nil {
+		log.Fatalf("Cannot parse config. Error: {%s}", err.Error())
+	}
+
+	db, err := storage.InitPsqlDB(cfg)
+	if err != nil {
+		log.Fatalf("Cannot connect to database. Error: {%s}", err.Error())
+	}
+
+	return db
+}
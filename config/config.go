@@ -7,8 +7,12 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Postgres PostgresConfig
+	Server    ServerConfig
+	Postgres  PostgresConfig
+	Search    SearchConfig
+	Recommend RecommendConfig
+	Path      PathConfig
+	Trash     TrashConfig
 }
 
 type ServerConfig struct {
@@ -28,6 +32,32 @@ type PostgresConfig struct {
 	PgDriver string `json:"pgDriver"`
 }
 
+type SearchConfig struct {
+	// Language is the default text search configuration, e.g. "english" or "russian".
+	Language string `json:"language"`
+	// Threshold is the default word similarity [0;1] a name needs to match a fuzzy search.
+	Threshold float32 `json:"threshold"`
+}
+
+type RecommendConfig struct {
+	// RefreshInterval is how often the recommendation model is rebuilt from the user ratings.
+	RefreshInterval time.Duration `json:"refreshInterval"`
+	// Neighbors is the number of most similar films kept for every film.
+	Neighbors int `json:"neighbors"`
+}
+
+type PathConfig struct {
+	// Timeout bounds the search for a chain of shared films between two actors.
+	Timeout time.Duration `json:"timeout"`
+}
+
+type TrashConfig struct {
+	// Retention is how long deleted actors and films can be restored before they are purged; 0 keeps them.
+	Retention time.Duration `json:"retention"`
+	// PurgeInterval is how often the trash is checked for entries older than Retention.
+	PurgeInterval time.Duration `json:"purgeInterval"`
+}
+
 func LoadConfig() (*viper.Viper, error) {
 
 	viperInstance := viper.New()
@@ -35,6 +65,13 @@ func LoadConfig() (*viper.Viper, error) {
 	viperInstance.AddConfigPath("./config")
 	viperInstance.SetConfigName("config")
 	viperInstance.SetConfigType("yml")
+	viperInstance.SetDefault("Search.Language", "english")
+	viperInstance.SetDefault("Search.Threshold", 0.3)
+	viperInstance.SetDefault("Recommend.RefreshInterval", 10*time.Minute)
+	viperInstance.SetDefault("Recommend.Neighbors", 20)
+	viperInstance.SetDefault("Path.Timeout", 5*time.Second)
+	viperInstance.SetDefault("Trash.Retention", 30*24*time.Hour)
+	viperInstance.SetDefault("Trash.PurgeInterval", time.Hour)
 
 	err := viperInstance.ReadInConfig()
 	if err != nil {
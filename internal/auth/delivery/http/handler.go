@@ -2,11 +2,15 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"film_library/internal/auth"
+	"film_library/internal/cconstant"
 	"fmt"
+	"github.com/gorilla/mux"
 	"log"
 	"net/http"
 	"regexp"
+	"strconv"
 )
 
 type AuthHandler struct {
@@ -50,16 +54,16 @@ func (h *AuthHandler) SignUp(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	status := http.StatusOK
 	err := h.authUC.CreateUser(&data)
 	if err != nil {
 		resp.Status = "error"
 		resp.Error = err.Error()
-		rw.WriteHeader(http.StatusInternalServerError)
-	} else {
-		rw.WriteHeader(http.StatusOK)
+		status = http.StatusInternalServerError
 	}
 	rawResponse, _ := json.Marshal(resp)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
 	_, _ = rw.Write(rawResponse)
 }
 
@@ -101,8 +105,99 @@ func (h *AuthHandler) SignIn(rw http.ResponseWriter, r *http.Request) {
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	rw.WriteHeader(http.StatusOK)
 	rawResponse, _ := json.Marshal(auth.SignInResponse{Token: token})
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetAccounts
+// @Description  List the users with their roles
+// @Tags         Auth
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Success      200  {array}	auth.Account
+// @Failure      403  {object}	error
+// @Failure      500  {object}  error
+// @Router       /users [get]
+func (h *AuthHandler) GetAccounts(rw http.ResponseWriter, r *http.Request) {
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetAccounts. User with ID:%d", tokenData.Id)
+
+	accounts, err := h.authUC.GetAccounts()
+	if err != nil {
+		log.Printf("Request: GetAccounts. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rawResponse, _ := json.Marshal(accounts)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      SetRole
+// @Description  Assign a role to a user. It applies from the next sign in of the user; nobody can change their own role.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "user id"
+// @Param        input	body	auth.RoleParams  true  "viewer, editor, moderator or admin"
+// @Success      200  {object}	auth.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      403  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /users/{id}/role [put]
+func (h *AuthHandler) SetRole(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data auth.RoleParams
+		resp *auth.ResponseModel = &auth.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: SetRole. User with ID:%d", tokenData.Id)
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		log.Printf("Request: SetRole. Error: bad id %q", mux.Vars(r)["id"])
+		http.Error(rw, fmt.Sprintf("id should be a positive number"), http.StatusBadRequest)
+		return
+	}
+	if id == tokenData.Id {
+		log.Printf("Request: SetRole. Error: %s", "own role")
+		http.Error(rw, fmt.Sprintf("you can't change your own role"), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: SetRole. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	role, err := auth.ParseRole(data.Role)
+	if err != nil {
+		log.Printf("Request: SetRole. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authUC.SetRole(id, role); err != nil {
+		log.Printf("Request: SetRole. Error: %s", err.Error())
+		status := http.StatusInternalServerError
+		if errors.Is(err, auth.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(rw, err.Error(), status)
+		return
+	}
+	log.Printf("Request: SetRole. User with ID:%d is %s now", id, data.Role)
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
@@ -7,6 +7,7 @@ import (
 	mock_auth "film_library/internal/auth/mocks"
 	"fmt"
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
@@ -136,3 +137,115 @@ func TestSignIn(t *testing.T) {
 		})
 	}
 }
+
+func TestUsers(t *testing.T) {
+	type mockBehavior func(s *mock_auth.MockUsecase)
+
+	testTable := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		role               int
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "List",
+			method: http.MethodGet,
+			path:   "/api/users",
+			role:   auth.RoleAdmin,
+			mockBehavior: func(s *mock_auth.MockUsecase) {
+				s.EXPECT().GetAccounts().Return([]auth.Account{{Id: 1, Login: "root", Role: "admin", RoleId: 3}, {Id: 2, Login: "abc", Role: "viewer"}}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `[{"id":1,"login":"root","role":"admin"},{"id":2,"login":"abc","role":"viewer"}]`,
+		},
+		{
+			name:               "ListForbidden",
+			method:             http.MethodGet,
+			path:               "/api/users",
+			role:               auth.RoleModerator,
+			mockBehavior:       func(s *mock_auth.MockUsecase) {},
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       "You don't have permission for this operation.\n",
+		},
+		{
+			name:   "SetRole",
+			method: http.MethodPut,
+			path:   "/api/users/2/role",
+			body:   `{"role":"editor"}`,
+			role:   auth.RoleAdmin,
+			mockBehavior: func(s *mock_auth.MockUsecase) {
+				s.EXPECT().SetRole(2, auth.RoleEditor).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":""}`,
+		},
+		{
+			name:               "UnknownRole",
+			method:             http.MethodPut,
+			path:               "/api/users/2/role",
+			body:               `{"role":"owner"}`,
+			role:               auth.RoleAdmin,
+			mockBehavior:       func(s *mock_auth.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "role should be one of viewer, editor, moderator, admin\n",
+		},
+		{
+			name:               "OwnRole",
+			method:             http.MethodPut,
+			path:               "/api/users/1/role",
+			body:               `{"role":"viewer"}`,
+			role:               auth.RoleAdmin,
+			mockBehavior:       func(s *mock_auth.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "you can't change your own role\n",
+		},
+		{
+			name:   "NoUser",
+			method: http.MethodPut,
+			path:   "/api/users/9/role",
+			body:   `{"role":"moderator"}`,
+			role:   auth.RoleAdmin,
+			mockBehavior: func(s *mock_auth.MockUsecase) {
+				s.EXPECT().SetRole(9, auth.RoleModerator).Return(fmt.Errorf("no user 9: %w", auth.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "no user 9: not found\n",
+		},
+		{
+			name:               "SetRoleForbidden",
+			method:             http.MethodPut,
+			path:               "/api/users/2/role",
+			body:               `{"role":"admin"}`,
+			role:               auth.RoleEditor,
+			mockBehavior:       func(s *mock_auth.MockUsecase) {},
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       "You don't have permission for this operation.\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			mockAuth := mock_auth.NewMockUsecase(c)
+			mockAuth.EXPECT().ParseToken("token").Return(&auth.TokenData{Id: 1, Role: testCase.role}, nil).Times(1)
+			testCase.mockBehavior(mockAuth)
+
+			handler := NewAuthHandler(mockAuth)
+			rtr := mux.NewRouter()
+			MapRoutes(rtr, handler)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(testCase.method, testCase.path, bytes.NewBufferString(testCase.body))
+			r.Header.Set("Authorization", "Bearer token")
+			rtr.ServeHTTP(w, r)
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"film_library/internal/auth"
+	"film_library/internal/cconstant"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// Require makes a middleware that lets a request through only when the role of
+// its user grants every one of perms. It runs after the middleware that puts
+// the token data of the user into the context, whose role ParseToken reads from
+// the users table rather than the token; routes register it in MapRoutes:
+//
+//	edit := authHttp.Require(auth.PermCatalogWrite)
+//	api.HandleFunc("/film/add", edit(s.CreateFilm))
+func Require(perms ...auth.Permission) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(rw http.ResponseWriter, r *http.Request) {
+			tokenData, ok := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+			if !ok {
+				http.Error(rw, fmt.Sprintf("empty auth header"), http.StatusUnauthorized)
+				return
+			}
+
+			for _, perm := range perms {
+				if !auth.Can(tokenData.Role, perm) {
+					log.Printf("Request: %s %s. User with ID:%d. Error: no permission %s", r.Method, r.URL.Path, tokenData.Id, perm)
+					http.Error(rw, fmt.Sprintf("You don't have permission for this operation."), http.StatusForbidden)
+					return
+				}
+			}
+
+			next(rw, r)
+		}
+	}
+}
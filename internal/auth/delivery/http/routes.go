@@ -1,11 +1,20 @@
 package http
 
 import (
+	"film_library/internal/auth"
 	"github.com/gorilla/mux"
 	"net/http"
 )
 
+// MapRoutes registers the sign up and sign in routes and the user management of admins.
 func MapRoutes(rtr *mux.Router, s *AuthHandler) {
+	manage := Require(auth.PermUserManage)
+
 	rtr.HandleFunc("/auth/signUp", s.SignUp).Methods(http.MethodPost)
 	rtr.HandleFunc("/auth/signIn", s.SignIn).Methods(http.MethodPost)
+
+	api := rtr.PathPrefix("/api/users").Subrouter()
+	api.Use(s.userIdentity)
+	api.HandleFunc("", manage(s.GetAccounts)).Methods(http.MethodGet)
+	api.HandleFunc("/{id:[0-9]+}/role", manage(s.SetRole)).Methods(http.MethodPut)
 }
@@ -0,0 +1,5 @@
+package auth
+
+import "errors"
+
+var ErrNotFound = errors.New("not found")
@@ -48,6 +48,36 @@ func (mr *MockRepositoryMockRecorder) CreateUser(user interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockRepository)(nil).CreateUser), user)
 }
 
+// GetAccounts mocks base method.
+func (m *MockRepository) GetAccounts() ([]auth.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccounts")
+	ret0, _ := ret[0].([]auth.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccounts indicates an expected call of GetAccounts.
+func (mr *MockRepositoryMockRecorder) GetAccounts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccounts", reflect.TypeOf((*MockRepository)(nil).GetAccounts))
+}
+
+// GetRole mocks base method.
+func (m *MockRepository) GetRole(id int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRole", id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRole indicates an expected call of GetRole.
+func (mr *MockRepositoryMockRecorder) GetRole(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockRepository)(nil).GetRole), id)
+}
+
 // GetUser mocks base method.
 func (m *MockRepository) GetUser(params *auth.SignInParams) (*auth.User, error) {
 	m.ctrl.T.Helper()
@@ -62,3 +92,17 @@ func (mr *MockRepositoryMockRecorder) GetUser(params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockRepository)(nil).GetUser), params)
 }
+
+// SetRole mocks base method.
+func (m *MockRepository) SetRole(id, role int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRole", id, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRole indicates an expected call of SetRole.
+func (mr *MockRepositoryMockRecorder) SetRole(id, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRole", reflect.TypeOf((*MockRepository)(nil).SetRole), id, role)
+}
@@ -9,6 +9,18 @@ type User struct {
 	Role     int    `json:"-" db:"role"`
 }
 
+// Account is a user as an admin sees it, with the name of the role.
+type Account struct {
+	Id     int    `json:"id" db:"id"`
+	Login  string `json:"login" db:"login"`
+	Role   string `json:"role" db:"-"`
+	RoleId int    `json:"-" db:"role"`
+}
+
+type RoleParams struct {
+	Role string `json:"role"`
+}
+
 type SignInParams struct {
 	Login    string `json:"login"`
 	Password string `json:"password"`
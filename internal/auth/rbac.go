@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Roles are stored in auth.role and carried by the token. A new user is a viewer.
+const (
+	RoleViewer = iota
+	RoleEditor
+	RoleModerator
+	RoleAdmin
+)
+
+// RoleNames are the names of the roles, indexed by role.
+var RoleNames = []string{"viewer", "editor", "moderator", "admin"}
+
+// Permission is a right a route may require; the role of the user has to grant it.
+type Permission string
+
+const (
+	// PermCatalogWrite is to create, change, delete and revert actors and films,
+	// and to change their relations and the genres of films.
+	PermCatalogWrite Permission = "catalog:write"
+	// PermCatalogManage is to add, rename and delete genres, and to see the
+	// trash and restore from it.
+	PermCatalogManage Permission = "catalog:manage"
+	// PermTransfer is to import and export the whole catalogue.
+	PermTransfer Permission = "catalog:transfer"
+	// PermReviewModerate is to delete the reviews of other users.
+	PermReviewModerate Permission = "review:moderate"
+	// PermAuditRead is to read the audit log.
+	PermAuditRead Permission = "audit:read"
+	// PermUserManage is to list the users and assign their roles.
+	PermUserManage Permission = "user:manage"
+)
+
+var rolePermissions = map[int][]Permission{
+	RoleViewer:    {},
+	RoleEditor:    {PermCatalogWrite, PermTransfer},
+	RoleModerator: {PermCatalogWrite, PermTransfer, PermReviewModerate},
+	RoleAdmin:     {PermCatalogWrite, PermCatalogManage, PermTransfer, PermReviewModerate, PermAuditRead, PermUserManage},
+}
+
+// Can tells whether the role grants the permission; an unknown role grants nothing.
+func Can(role int, perm Permission) bool {
+	return slices.Contains(rolePermissions[role], perm)
+}
+
+// RoleName is the name of the role, "" for an unknown one.
+func RoleName(role int) string {
+	if role < 0 || role >= len(RoleNames) {
+		return ""
+	}
+
+	return RoleNames[role]
+}
+
+// ParseRole returns the role with the name.
+func ParseRole(name string) (int, error) {
+	role := slices.Index(RoleNames, name)
+	if role < 0 {
+		return 0, fmt.Errorf("role should be one of %s", strings.Join(RoleNames, ", "))
+	}
+
+	return role, nil
+}
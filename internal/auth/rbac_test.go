@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCan(t *testing.T) {
+	require.False(t, Can(RoleViewer, PermCatalogWrite))
+	require.True(t, Can(RoleEditor, PermCatalogWrite))
+	require.False(t, Can(RoleEditor, PermReviewModerate))
+	require.True(t, Can(RoleModerator, PermReviewModerate))
+	require.False(t, Can(RoleModerator, PermAuditRead))
+	require.False(t, Can(RoleModerator, PermCatalogManage))
+	for _, perm := range []Permission{PermCatalogWrite, PermCatalogManage, PermTransfer, PermReviewModerate, PermAuditRead, PermUserManage} {
+		require.True(t, Can(RoleAdmin, perm))
+		require.False(t, Can(-1, perm))
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	for role, name := range RoleNames {
+		parsed, err := ParseRole(name)
+		require.NoError(t, err)
+		require.Equal(t, role, parsed)
+		require.Equal(t, name, RoleName(role))
+	}
+
+	_, err := ParseRole("Admin")
+	require.EqualError(t, err, "role should be one of viewer, editor, moderator, admin")
+	require.Equal(t, "", RoleName(7))
+}
@@ -3,4 +3,7 @@ package auth
 type Repository interface {
 	CreateUser(user *User) error
 	GetUser(params *SignInParams) (*User, error)
+	GetAccounts() ([]Account, error)
+	GetRole(id int) (int, error)
+	SetRole(id, role int) error
 }
@@ -58,3 +58,72 @@ func (p *postgresRepository) GetUser(params *auth.SignInParams) (*auth.User, err
 	return &data[0], nil
 
 }
+
+// GetAccounts returns every user, the earliest registered first.
+func (p *postgresRepository) GetAccounts() ([]auth.Account, error) {
+	var (
+		data  = make([]auth.Account, 0)
+		query = `
+		SELECT id, login, role
+		FROM %[1]s
+		ORDER BY id
+		`
+	)
+
+	query = fmt.Sprintf(query, cconstant.AuthDB)
+
+	if err := p.db.Select(&data, query); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetRole returns the role the user has now.
+func (p *postgresRepository) GetRole(id int) (int, error) {
+	var (
+		data  []int
+		query = `
+		SELECT role
+		FROM %[1]s
+		WHERE id = $1
+		`
+	)
+
+	query = fmt.Sprintf(query, cconstant.AuthDB)
+
+	if err := p.db.Select(&data, query, id); err != nil {
+		return 0, err
+	}
+
+	if len(data) == 0 {
+		return 0, fmt.Errorf("no user %d: %w", id, auth.ErrNotFound)
+	}
+
+	return data[0], nil
+}
+
+// SetRole assigns the role to the user; it applies to the tokens issued before as well.
+func (p *postgresRepository) SetRole(id, role int) error {
+	var (
+		query = `
+		UPDATE %[1]s SET role = $1
+		WHERE id = $2
+		`
+
+		values = []any{role, id}
+	)
+
+	query = fmt.Sprintf(query, cconstant.AuthDB)
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("no user %d: %w", id, auth.ErrNotFound)
+	}
+
+	return nil
+}
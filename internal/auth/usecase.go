@@ -4,4 +4,6 @@ type Usecase interface {
 	CreateUser(user *User) error
 	GenerateToken(params *SignInParams) (string, error)
 	ParseToken(token string) (*TokenData, error)
+	GetAccounts() ([]Account, error)
+	SetRole(id, role int) error
 }
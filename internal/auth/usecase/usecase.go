@@ -6,15 +6,26 @@ import (
 	"film_library/internal/cconstant"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
+	"sync"
 	"time"
 )
 
 type AuthUsecase struct {
 	repo auth.Repository
+
+	// roles caches the current roles of users for cconstant.RoleCacheTTL,
+	// so a token is checked against the role stored, not the one it was issued with.
+	mu    sync.Mutex
+	roles map[int]cachedRole
+}
+
+type cachedRole struct {
+	role    int
+	expires time.Time
 }
 
 func NewAuthUsecase(repo auth.Repository) auth.Usecase {
-	return &AuthUsecase{repo: repo}
+	return &AuthUsecase{repo: repo, roles: make(map[int]cachedRole)}
 }
 
 func (u *AuthUsecase) CreateUser(user *auth.User) error {
@@ -57,7 +68,61 @@ func (u *AuthUsecase) ParseToken(accessToken string) (*auth.TokenData, error) {
 		return &auth.TokenData{}, fmt.Errorf("invalid claims type")
 	}
 
-	return &auth.TokenData{Id: claims.Id, Role: claims.Role}, nil
+	role, err := u.role(claims.Id)
+	if err != nil {
+		return &auth.TokenData{}, err
+	}
+
+	return &auth.TokenData{Id: claims.Id, Role: role}, nil
+}
+
+// role returns the current role of the user, from the cache while it is fresh.
+func (u *AuthUsecase) role(id int) (int, error) {
+	u.mu.Lock()
+	cached, ok := u.roles[id]
+	u.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.role, nil
+	}
+
+	role, err := u.repo.GetRole(id)
+	if err != nil {
+		return 0, err
+	}
+
+	u.mu.Lock()
+	if u.roles == nil {
+		u.roles = make(map[int]cachedRole)
+	}
+	u.roles[id] = cachedRole{role: role, expires: time.Now().Add(cconstant.RoleCacheTTL)}
+	u.mu.Unlock()
+
+	return role, nil
+}
+
+func (u *AuthUsecase) GetAccounts() ([]auth.Account, error) {
+	accounts, err := u.repo.GetAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range accounts {
+		accounts[i].Role = auth.RoleName(accounts[i].RoleId)
+	}
+
+	return accounts, nil
+}
+
+func (u *AuthUsecase) SetRole(id, role int) error {
+	if err := u.repo.SetRole(id, role); err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	delete(u.roles, id)
+	u.mu.Unlock()
+
+	return nil
 }
 
 func (u *AuthUsecase) generatePasswordHash(password string) string {
@@ -4,6 +4,7 @@ import (
 	"film_library/internal/auth"
 	mock_auth "film_library/internal/auth/mocks"
 	"film_library/internal/cconstant"
+	"fmt"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -12,14 +13,20 @@ import (
 )
 
 func TestParseToken(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
 
-	u := AuthUsecase{}
+	repo := mock_auth.NewMockRepository(ctr)
+	u := AuthUsecase{repo: repo}
 
 	var (
 		id   = 999
 		role = 1
 	)
 
+	// The role stored wins over the one the token was issued with.
+	repo.EXPECT().GetRole(id).Return(auth.RoleEditor, nil).Times(1)
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.CustomClaims{
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(cconstant.TokenTTL).Unix(),
@@ -34,7 +41,7 @@ func TestParseToken(t *testing.T) {
 	encodeData, err := u.ParseToken(accessToken)
 	require.NoError(t, err)
 	require.Equal(t, id, encodeData.Id)
-	require.Equal(t, role, encodeData.Role)
+	require.Equal(t, auth.RoleEditor, encodeData.Role)
 }
 
 func TestCreateUser(t *testing.T) {
@@ -60,6 +67,7 @@ func TestGenerateToken(t *testing.T) {
 	out := auth.User{Id: 1, Login: "123", Password: u.generatePasswordHash("123"), Role: 1}
 
 	repo.EXPECT().GetUser(&in).Return(&out, nil).Times(1)
+	repo.EXPECT().GetRole(1).Return(1, nil).Times(1)
 	useCase := NewAuthUsecase(repo)
 	accessToken, err := useCase.GenerateToken(&in)
 	require.NoError(t, err)
@@ -69,3 +77,60 @@ func TestGenerateToken(t *testing.T) {
 	require.Equal(t, data.Id, 1)
 	require.Equal(t, data.Role, 1)
 }
+
+func TestAccounts(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_auth.NewMockRepository(ctr)
+	repo.EXPECT().GetAccounts().Return([]auth.Account{{Id: 1, Login: "root", RoleId: auth.RoleAdmin}, {Id: 2, Login: "abc"}}, nil).Times(1)
+	repo.EXPECT().SetRole(2, auth.RoleModerator).Return(nil).Times(1)
+	useCase := NewAuthUsecase(repo)
+
+	accounts, err := useCase.GetAccounts()
+	require.NoError(t, err)
+	require.Equal(t, []auth.Account{{Id: 1, Login: "root", Role: "admin", RoleId: auth.RoleAdmin}, {Id: 2, Login: "abc", Role: "viewer"}}, accounts)
+
+	require.NoError(t, useCase.SetRole(2, auth.RoleModerator))
+}
+
+func TestRoleCache(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_auth.NewMockRepository(ctr)
+	useCase := NewAuthUsecase(repo)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.CustomClaims{
+		StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(cconstant.TokenTTL).Unix()},
+		Id:             2,
+		Role:           auth.RoleEditor,
+	})
+	accessToken, err := token.SignedString([]byte(cconstant.SignedKey))
+	require.NoError(t, err)
+
+	gomock.InOrder(
+		repo.EXPECT().GetRole(2).Return(auth.RoleEditor, nil).Times(1),
+		repo.EXPECT().SetRole(2, auth.RoleViewer).Return(nil).Times(1),
+		repo.EXPECT().GetRole(2).Return(auth.RoleViewer, nil).Times(1),
+	)
+
+	for range 2 {
+		data, err := useCase.ParseToken(accessToken)
+		require.NoError(t, err)
+		require.Equal(t, auth.RoleEditor, data.Role)
+	}
+
+	// A new role applies to the token at once.
+	require.NoError(t, useCase.SetRole(2, auth.RoleViewer))
+	data, err := useCase.ParseToken(accessToken)
+	require.NoError(t, err)
+	require.Equal(t, auth.RoleViewer, data.Role)
+
+	repo.EXPECT().GetRole(3).Return(0, fmt.Errorf("no user 3: %w", auth.ErrNotFound)).Times(1)
+	token.Claims = auth.CustomClaims{StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(cconstant.TokenTTL).Unix()}, Id: 3}
+	accessToken, err = token.SignedString([]byte(cconstant.SignedKey))
+	require.NoError(t, err)
+	_, err = useCase.ParseToken(accessToken)
+	require.ErrorIs(t, err, auth.ErrNotFound)
+}
@@ -3,23 +3,99 @@ package cconstant
 import "time"
 
 const (
-	ActorDB string = "filmdb.public.actor"
-	FilmDB  string = "filmdb.public.film"
-	AuthDB  string = "filmdb.public.auth"
+	PersonDB string = "filmdb.public.person"
+	FilmDB   string = "filmdb.public.film"
+	AuthDB   string = "filmdb.public.auth"
+
+	ActorFilmDB string = "filmdb.public.actor_film"
+
+	GenreDB     string = "filmdb.public.genre"
+	FilmGenreDB string = "filmdb.public.film_genre"
+
+	FilmCrewDB string = "filmdb.public.film_crew"
+
+	ReviewDB string = "filmdb.public.review"
+
+	WatchlistDB     string = "filmdb.public.watchlist"
+	WatchlistItemDB string = "filmdb.public.watchlist_item"
+
+	AuditDB    string = "filmdb.public.audit_log"
+	RevisionDB string = "filmdb.public.revision"
 )
 
 const (
 	Salt      = "xjifcmefdx2oxe3x"
 	SignedKey = "efcj34s3dr4cwdxxjuu34"
 	TokenTTL  = 6 * time.Hour
+
+	// RoleCacheTTL is how long the role of a user is trusted before it is read again.
+	RoleCacheTTL = 30 * time.Second
 )
 
 const (
 	AuthHeader   = "Authorization"
 	ContextValue = "tokenData"
+
+	// RequestIdHeader carries the id of a request; it is generated when the client sends none.
+	RequestIdHeader = "X-Request-Id"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+
+	MaxSearchQueryLen = 256
+
+	// MaxPathDepth is the longest chain of films GetActorPath looks for.
+	MaxPathDepth = 6
+
+	MinReviewRating = 1
+	MaxReviewRating = 10
+	MaxReviewLen    = 5000
+
+	// DefaultImportBatch is the number of imported records committed in one transaction.
+	DefaultImportBatch = 500
+	// MaxImportSize bounds the body of an import request.
+	MaxImportSize = 32 << 20
 )
 
 var (
-	FieldsActor = []string{"actor_name", "sex", "bdate"}
-	FieldsFilm  = []string{"film_name", "release_date", "rating", "description"}
+	FieldsActor  = []string{"actor_name", "sex", "bdate"}
+	FieldsFilm   = []string{"film_name", "release_date", "rating", "description"}
+	FieldsReview = []string{"created_at", "rating"}
+	SortOrders   = []string{"asc", "desc"}
+
+	// SearchLanguages are the text search configurations films can be searched with.
+	// Each one needs its own index, see migration 0005_full_text_search.
+	SearchLanguages = []string{"english", "russian", "simple"}
+
+	// CreditTypes are the allowed actor_film.credit_type values; new credits are "supporting".
+	CreditTypes = []string{"lead", "supporting", "cameo", "voice"}
+
+	// CrewJobs are the allowed film_crew.job values.
+	CrewJobs = []string{"director", "writer", "producer", "composer", "cinematographer"}
+
+	// WatchStatuses are the allowed watchlist_item.status values.
+	WatchStatuses = []string{"want_to_watch", "watched"}
+
+	// ImportFormats are the file formats of a bulk import: CSV with a header row,
+	// a JSON array, JSON Lines or a zip of CSV files as the csv export writes it.
+	// RecordKinds are the kinds of records they hold, in the order they are exported.
+	ImportFormats = []string{"csv", "json", "jsonl", "zip"}
+	RecordKinds   = []string{"actor", "film", "relation", "crew", "genre"}
+
+	// ExportFormats are the formats of a catalogue export; csv is a zip of one file per record kind.
+	ExportFormats = []string{"jsonl", "csv", "json"}
+
+	// ActorExpansions and FilmExpansions are the relations ?expand= can embed as full objects.
+	ActorExpansions = []string{"films"}
+	FilmExpansions  = []string{"actors"}
+
+	// SearchModes: "fts" is full-text search, "fuzzy" matches names by trigram similarity.
+	SearchModes = []string{"fts", "fuzzy"}
+
+	// AuditEntities are what the audit log records changes of; the relations
+	// are filed under their film. AuditOperations are the kinds of change.
+	AuditEntities   = []string{"actor", "film", "genre", "review", "actor_film", "film_crew", "film_genre", "trash"}
+	AuditOperations = []string{"create", "update", "delete", "restore", "revert", "purge"}
 )
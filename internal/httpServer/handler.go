@@ -1,12 +1,21 @@
 package httpServer
 
 import (
+	"context"
 	authHttp "film_library/internal/auth/delivery/http"
 	repository2 "film_library/internal/auth/repository"
 	usecase2 "film_library/internal/auth/usecase"
+	"film_library/internal/recommend"
 	serviceHttp "film_library/internal/service/delivery/http"
 	"film_library/internal/service/repository"
 	"film_library/internal/service/usecase"
+	transferHttp "film_library/internal/transfer/delivery/http"
+	transferRepository "film_library/internal/transfer/repository"
+	transferUsecase "film_library/internal/transfer/usecase"
+	watchlistHttp "film_library/internal/watchlist/delivery/http"
+	watchlistRepository "film_library/internal/watchlist/repository"
+	watchlistUsecase "film_library/internal/watchlist/usecase"
+	"film_library/pkg/requestid"
 	"film_library/pkg/storage"
 	"github.com/gorilla/mux"
 	"log"
@@ -19,23 +28,43 @@ func (s *Server) MapHandlers() error {
 		log.Printf(err.Error())
 		return err
 	}
-	if err = storage.CreateTables(db); err != nil {
+	migrator, err := storage.NewMigrator(db)
+	if err != nil {
+		log.Printf(err.Error())
+		return err
+	}
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
 		log.Printf(err.Error())
 		return err
 	}
+	log.Printf("Applied %d migrations", applied)
 
 	serviceRepo := repository.NewPostgresRepository(db)
 	authRepo := repository2.NewPostgresRepository(db)
+	watchlistRepo := watchlistRepository.NewPostgresRepository(db)
+	transferRepo := transferRepository.NewPostgresRepository(db)
+
+	recommender := recommend.NewRecommender(serviceRepo.GetRatings, s.cfg.Recommend.Neighbors)
+	go recommender.Run(context.Background(), s.cfg.Recommend.RefreshInterval)
 
-	serviceUC := usecase.NewServiceUsecase(serviceRepo)
+	serviceUC := usecase.NewServiceUsecase(s.cfg, serviceRepo, recommender)
+	go usecase.RunPurge(context.Background(), serviceUC, s.cfg.Trash.PurgeInterval)
 	authUC := usecase2.NewAuthUsecase(authRepo)
+	watchlistUC := watchlistUsecase.NewWatchlistUsecase(watchlistRepo)
+	transferUC := transferUsecase.NewTransferUsecase(transferRepo)
 
 	authR := authHttp.NewAuthHandler(authUC)
 	serviceR := serviceHttp.NewServiceHandler(serviceUC, authUC)
+	watchlistR := watchlistHttp.NewWatchlistHandler(watchlistUC, authUC)
+	transferR := transferHttp.NewTransferHandler(transferUC, authUC)
 
-	rtr := mux.NewRouter()
+	rtr := mux.NewRouter().UseEncodedPath()
+	rtr.Use(requestid.Middleware)
 	serviceHttp.MapRoutes(rtr, serviceR)
 	authHttp.MapRoutes(rtr, authR)
+	watchlistHttp.MapRoutes(rtr, watchlistR)
+	transferHttp.MapRoutes(rtr, transferR)
 	http.Handle("/", rtr)
 
 	return nil
@@ -0,0 +1,183 @@
+// Package recommend predicts how users would rate films they have not rated
+// yet with item-based collaborative filtering: two films are similar when the
+// same users rate both of them above or below their own average.
+package recommend
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// shrinkage damps the similarity of films only a few users rated both:
+	// it is multiplied by n/(n+shrinkage) for n common raters.
+	shrinkage = 5
+	// popularityPrior is how many average ratings a film starts with when
+	// films are ranked by popularity, so a single 10 does not top the list.
+	popularityPrior = 5
+)
+
+type Rating struct {
+	UserId int `db:"user_id"`
+	FilmId int `db:"film_id"`
+	Rating int `db:"rating"`
+}
+
+// Prediction is a film with the rating the user is expected to give it.
+type Prediction struct {
+	FilmId int
+	Score  float64
+}
+
+type neighbor struct {
+	filmId     int
+	similarity float64
+}
+
+// Model is built from a snapshot of all ratings and is read-only afterwards.
+type Model struct {
+	ratings   map[int]map[int]float64 // user -> film -> rating
+	means     map[int]float64         // user -> mean rating
+	neighbors map[int][]neighbor      // film -> most similar films
+	popular   []Prediction            // every rated film, the best first
+}
+
+// Build computes the film similarities and keeps the k most similar films for every film.
+func Build(ratings []Rating, k int) *Model {
+	m := &Model{
+		ratings:   make(map[int]map[int]float64),
+		means:     make(map[int]float64),
+		neighbors: make(map[int][]neighbor),
+	}
+
+	for _, r := range ratings {
+		if m.ratings[r.UserId] == nil {
+			m.ratings[r.UserId] = make(map[int]float64)
+		}
+		m.ratings[r.UserId][r.FilmId] = float64(r.Rating)
+	}
+
+	var (
+		dot     = make(map[[2]int]float64)
+		common  = make(map[[2]int]int)
+		norm    = make(map[int]float64)
+		sums    = make(map[int]float64)
+		counts  = make(map[int]int)
+		total   float64
+		centred = make(map[int]float64)
+	)
+
+	for userId, films := range m.ratings {
+		var sum float64
+		for _, r := range films {
+			sum += r
+		}
+		m.means[userId] = sum / float64(len(films))
+
+		clear(centred)
+		for filmId, r := range films {
+			centred[filmId] = r - m.means[userId]
+			norm[filmId] += centred[filmId] * centred[filmId]
+			sums[filmId] += r
+			counts[filmId]++
+			total += r
+		}
+
+		for i, ci := range centred {
+			for j, cj := range centred {
+				if i < j {
+					dot[[2]int{i, j}] += ci * cj
+					common[[2]int{i, j}]++
+				}
+			}
+		}
+	}
+
+	for pair, d := range dot {
+		if d <= 0 {
+			continue
+		}
+		n := float64(common[pair])
+		sim := d / math.Sqrt(norm[pair[0]]*norm[pair[1]]) * n / (n + shrinkage)
+		m.neighbors[pair[0]] = append(m.neighbors[pair[0]], neighbor{filmId: pair[1], similarity: sim})
+		m.neighbors[pair[1]] = append(m.neighbors[pair[1]], neighbor{filmId: pair[0], similarity: sim})
+	}
+
+	for filmId, list := range m.neighbors {
+		sort.Slice(list, func(a, b int) bool {
+			if list[a].similarity != list[b].similarity {
+				return list[a].similarity > list[b].similarity
+			}
+			return list[a].filmId < list[b].filmId
+		})
+		if len(list) > k {
+			list = list[:k]
+		}
+		m.neighbors[filmId] = list
+	}
+
+	if len(ratings) > 0 {
+		globalMean := total / float64(len(ratings))
+		for filmId, count := range counts {
+			score := (sums[filmId] + globalMean*popularityPrior) / float64(count+popularityPrior)
+			m.popular = append(m.popular, Prediction{FilmId: filmId, Score: score})
+		}
+		sortPredictions(m.popular)
+	}
+
+	return m
+}
+
+// Recommend returns up to limit films the user has not rated, the highest
+// predicted rating first. When the ratings of the user say too little, the
+// rest of the list is filled with the most popular films.
+func (m *Model) Recommend(userId, limit int) []Prediction {
+	var (
+		rated = m.ratings[userId]
+		num   = make(map[int]float64)
+		den   = make(map[int]float64)
+	)
+
+	for filmId, r := range rated {
+		for _, n := range m.neighbors[filmId] {
+			if _, ok := rated[n.filmId]; ok {
+				continue
+			}
+			num[n.filmId] += n.similarity * (r - m.means[userId])
+			den[n.filmId] += n.similarity
+		}
+	}
+
+	result := make([]Prediction, 0, len(num))
+	for filmId := range num {
+		result = append(result, Prediction{FilmId: filmId, Score: m.means[userId] + num[filmId]/den[filmId]})
+	}
+	sortPredictions(result)
+	if len(result) >= limit {
+		return result[:limit]
+	}
+
+	for _, p := range m.popular {
+		if len(result) == limit {
+			break
+		}
+		if _, ok := rated[p.FilmId]; ok {
+			continue
+		}
+		if _, ok := num[p.FilmId]; ok {
+			continue
+		}
+		result = append(result, p)
+	}
+
+	return result
+}
+
+func sortPredictions(p []Prediction) {
+	sort.Slice(p, func(a, b int) bool {
+		if p[a].Score != p[b].Score {
+			return p[a].Score > p[b].Score
+		}
+		return p[a].FilmId < p[b].FilmId
+	})
+}
@@ -0,0 +1,75 @@
+package recommend
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRecommend(t *testing.T) {
+	// Films 1 and 2 are liked by the same users, film 3 is liked by those who dislike them.
+	ratings := []Rating{
+		{UserId: 1, FilmId: 1, Rating: 9}, {UserId: 1, FilmId: 2, Rating: 9}, {UserId: 1, FilmId: 3, Rating: 2},
+		{UserId: 2, FilmId: 1, Rating: 8}, {UserId: 2, FilmId: 2, Rating: 10}, {UserId: 2, FilmId: 3, Rating: 3},
+		{UserId: 3, FilmId: 1, Rating: 2}, {UserId: 3, FilmId: 2, Rating: 3}, {UserId: 3, FilmId: 3, Rating: 9},
+		{UserId: 4, FilmId: 1, Rating: 10}, {UserId: 4, FilmId: 4, Rating: 6},
+	}
+	model := Build(ratings, 10)
+
+	// User 4 loved film 1, so film 2 is predicted above their mean and film 3 is not recommended by similarity.
+	got := model.Recommend(4, 1)
+	require.Len(t, got, 1)
+	require.Equal(t, 2, got[0].FilmId)
+	require.Greater(t, got[0].Score, model.means[4])
+
+	// The rest of the list comes from the popular films the user has not rated.
+	got = model.Recommend(4, 5)
+	require.Len(t, got, 2)
+	require.Equal(t, []int{2, 3}, []int{got[0].FilmId, got[1].FilmId})
+
+	// Unknown users get popular films only: film 1 has the most ratings.
+	got = model.Recommend(100, 2)
+	require.Len(t, got, 2)
+	require.Equal(t, []int{1, 2}, []int{got[0].FilmId, got[1].FilmId})
+}
+
+func TestBuildNeighbors(t *testing.T) {
+	var ratings []Rating
+	for user := 1; user <= 3; user++ {
+		for film := 1; film <= 5; film++ {
+			ratings = append(ratings, Rating{UserId: user, FilmId: film, Rating: (user*film)%10 + 1})
+		}
+	}
+
+	model := Build(ratings, 2)
+	for filmId, list := range model.neighbors {
+		require.LessOrEqual(t, len(list), 2, fmt.Sprintf("film %d", filmId))
+		for _, n := range list {
+			require.Greater(t, n.similarity, 0.0)
+			require.LessOrEqual(t, n.similarity, 1.0)
+		}
+	}
+
+	require.Empty(t, Build(nil, 2).Recommend(1, 5))
+}
+
+func TestRefresh(t *testing.T) {
+	var (
+		ratings = []Rating{{UserId: 1, FilmId: 1, Rating: 7}}
+		fail    bool
+	)
+	r := NewRecommender(func() ([]Rating, error) {
+		if fail {
+			return nil, fmt.Errorf("db is down")
+		}
+		return ratings, nil
+	}, 10)
+
+	require.Empty(t, r.Recommend(2, 5))
+	require.NoError(t, r.Refresh())
+	require.Equal(t, []Prediction{{FilmId: 1, Score: 7}}, r.Recommend(2, 5))
+
+	fail = true
+	require.Error(t, r.Refresh())
+	require.Len(t, r.Recommend(2, 5), 1)
+}
@@ -0,0 +1,66 @@
+package recommend
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Recommender serves recommendations from a model it rebuilds periodically
+// from all ratings, so requests never wait for the model to be computed.
+type Recommender struct {
+	load      func() ([]Rating, error)
+	neighbors int
+
+	mu    sync.RWMutex
+	model *Model
+}
+
+// NewRecommender returns a recommender with an empty model; load reads every
+// rating and neighbors is the number of similar films kept per film.
+func NewRecommender(load func() ([]Rating, error), neighbors int) *Recommender {
+	return &Recommender{load: load, neighbors: neighbors, model: Build(nil, neighbors)}
+}
+
+// Refresh rebuilds the model. The previous model is kept when loading fails.
+func (r *Recommender) Refresh() error {
+	ratings, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	model := Build(ratings, r.neighbors)
+
+	r.mu.Lock()
+	r.model = model
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Run refreshes the model right away and then every interval until ctx is done.
+func (r *Recommender) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Refresh(); err != nil {
+			log.Printf("Cannot refresh recommendations. Error: {%s}", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Recommender) Recommend(userId, limit int) []Prediction {
+	r.mu.RLock()
+	model := r.model
+	r.mu.RUnlock()
+
+	return model.Recommend(userId, limit)
+}
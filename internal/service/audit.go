@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Origin says who changes the catalogue and in which request; it travels in
+// the context of a mutation and is written to the audit log with the change.
+type Origin struct {
+	UserId    int
+	RequestId string
+}
+
+type originKey struct{}
+
+func WithOrigin(ctx context.Context, origin Origin) context.Context {
+	return context.WithValue(ctx, originKey{}, origin)
+}
+
+// OriginFrom returns the origin stored by WithOrigin, the zero Origin for
+// changes made outside of a request, e.g. by the trash purge.
+func OriginFrom(ctx context.Context) Origin {
+	origin, _ := ctx.Value(originKey{}).(Origin)
+	return origin
+}
+
+// AuditEntry is a change of the catalogue. Before and After hold only the
+// fields that changed: Before is null for a create, After for a delete.
+// Relations are filed under the film they belong to.
+type AuditEntry struct {
+	Id        int       `json:"id" db:"id"`
+	UserId    *int      `json:"user_id" db:"user_id"`
+	Entity    string    `json:"entity" db:"entity"`
+	EntityId  *int      `json:"entity_id" db:"entity_id"`
+	Operation string    `json:"operation" db:"operation"`
+	Before    RawJSON   `json:"before" db:"before"`
+	After     RawJSON   `json:"after" db:"after"`
+	RequestId string    `json:"request_id" db:"request_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewAuditEntry makes the entry of a change made by origin. Only the fields
+// that differ between before and after are kept, and the keys that identify
+// a relation.
+func NewAuditEntry(origin Origin, entity string, entityId int, operation string, before, after map[string]any, keys ...string) *AuditEntry {
+	before, after = diff(before, after, keys...)
+
+	entry := &AuditEntry{
+		Entity:    entity,
+		Operation: operation,
+		Before:    SnapshotJSON(before),
+		After:     SnapshotJSON(after),
+		RequestId: origin.RequestId,
+	}
+	if origin.UserId != 0 {
+		entry.UserId = &origin.UserId
+	}
+	if entityId != 0 {
+		entry.EntityId = &entityId
+	}
+
+	return entry
+}
+
+// diff drops the fields with the same value before and after a change, but
+// for keys. A create or a delete has one side only and is kept whole.
+func diff(before, after map[string]any, keys ...string) (map[string]any, map[string]any) {
+	if before == nil || after == nil {
+		return before, after
+	}
+
+	changedBefore := make(map[string]any)
+	changedAfter := make(map[string]any)
+	for field, value := range after {
+		if slices.Contains(keys, field) || !reflect.DeepEqual(before[field], value) {
+			changedBefore[field] = before[field]
+			changedAfter[field] = value
+		}
+	}
+
+	return changedBefore, changedAfter
+}
+
+// SnapshotJSON is the snapshot as it is stored, nil for none.
+func SnapshotJSON(snapshot map[string]any) RawJSON {
+	if snapshot == nil {
+		return nil
+	}
+
+	raw, _ := json.Marshal(snapshot)
+	return raw
+}
+
+// Snapshots hold the audited fields of an entity under their JSON names. An
+// audit entry keeps the ones a change differs in, a revision all of them.
+
+func ActorFields(a *Actor) map[string]any {
+	return map[string]any{"name": a.Name, "sex": a.Sex, "bdate": a.BDate}
+}
+
+func FilmFields(f *Film) map[string]any {
+	return map[string]any{"name": f.Name, "rdate": f.RDate, "rating": f.Rating, "desc": f.Desc}
+}
+
+func GenreFields(g *Genre) map[string]any {
+	return map[string]any{"name": g.Name}
+}
+
+func ReviewFields(r *Review) map[string]any {
+	return map[string]any{"film_id": r.FilmId, "user_id": r.UserId, "rating": r.Rating, "text": r.Text}
+}
+
+func CreditFields(c *Credit) map[string]any {
+	fields := map[string]any{"actor_id": c.ActorId, "character": c.Character, "type": c.Type, "order": nil}
+	if c.Order != nil {
+		fields["order"] = *c.Order
+	}
+
+	return fields
+}
+
+// Audit writes a change to the audit log on behalf of the origin in ctx.
+// repo is the one of the transaction making the change, so the entry is
+// committed with the change or not at all.
+func Audit(ctx context.Context, repo Repository, entity string, entityId int, operation string, before, after map[string]any, keys ...string) error {
+	entry := NewAuditEntry(OriginFrom(ctx), entity, entityId, operation, before, after, keys...)
+
+	return repo.AddAudit(ctx, entry)
+}
+
+// Revise stores after as the next revision of the entity and returns its
+// number, 0 when nothing was stored. before becomes revision 1 of an entity
+// changed for the first time. repo is the one of the transaction making the
+// change, which holds the row lock, so revisions are numbered in the order
+// the changes are applied.
+func Revise(ctx context.Context, repo Repository, entity string, id int, before, after map[string]any) (int, error) {
+	if after == nil || reflect.DeepEqual(before, after) {
+		return 0, nil
+	}
+
+	rev := &Revision{Entity: entity, EntityId: id, Data: SnapshotJSON(after)}
+	if origin := OriginFrom(ctx); origin.UserId != 0 {
+		rev.UserId = &origin.UserId
+	}
+
+	return repo.AddRevision(ctx, rev, SnapshotJSON(before))
+}
+
+// The lookups return nil when the entity is gone, so the change itself reports it.
+
+// Locked takes the row lock of the entity before reading its snapshot, so the
+// snapshot is what the change in the same transaction starts from.
+func Locked(repo Repository, entity string, id int, snapshot func(repo Repository, id int) (map[string]any, error)) (map[string]any, error) {
+	if err := repo.Lock(entity, id); err != nil {
+		return nil, err
+	}
+
+	return snapshot(repo, id)
+}
+
+func ActorSnapshot(repo Repository, id int) (map[string]any, error) {
+	actors, err := repo.GetActorsByIds([]int{id})
+	if err != nil || len(actors) == 0 {
+		return nil, err
+	}
+
+	return ActorFields(&actors[0]), nil
+}
+
+func FilmSnapshot(repo Repository, id int) (map[string]any, error) {
+	films, err := repo.GetFilmsByIds([]int{id})
+	if err != nil || len(films) == 0 {
+		return nil, err
+	}
+
+	return FilmFields(&films[0]), nil
+}
+
+func GenreSnapshot(repo Repository, id int) (map[string]any, error) {
+	genre, err := repo.GetGenre(id)
+	if err != nil {
+		return nil, ignoreNotFound(err)
+	}
+
+	return GenreFields(genre), nil
+}
+
+func ReviewSnapshot(repo Repository, id int) (map[string]any, error) {
+	review, err := repo.GetReview(id)
+	if err != nil {
+		return nil, ignoreNotFound(err)
+	}
+
+	return ReviewFields(review), nil
+}
+
+func CreditSnapshot(repo Repository, actorId, filmId int) (map[string]any, error) {
+	credit, err := repo.GetCredit(actorId, filmId)
+	if err != nil {
+		return nil, ignoreNotFound(err)
+	}
+
+	return CreditFields(credit), nil
+}
+
+func ignoreNotFound(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// AuditFilter narrows the audit trail; every field that is set must match.
+type AuditFilter struct {
+	Entity    string     `json:"entity"`
+	EntityId  int        `json:"entity_id"`
+	UserId    int        `json:"user_id"`
+	Operation string     `json:"operation"`
+	From      *time.Time `json:"from"`
+	To        *time.Time `json:"to"`
+}
+
+type AuditPage struct {
+	Items      []AuditEntry `json:"items"`
+	NextCursor string       `json:"next_cursor"`
+	Total      int          `json:"total"`
+}
+
+// RawJSON is a JSON document stored in a jsonb column; an empty one is null.
+type RawJSON []byte
+
+func (j *RawJSON) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append((*j)[:0], v...)
+	case string:
+		*j = RawJSON(v)
+	default:
+		return fmt.Errorf("cannot scan %T into RawJSON", src)
+	}
+
+	return nil
+}
+
+func (j RawJSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+
+	return j, nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Cursor points at the last row of a page: the sort column and order it was
+// taken from, the value of that column and the row id that breaks ties between equal values.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o,omitempty"`
+	Key   string `json:"k"`
+	Id    int    `json:"id"`
+}
+
+// EncodeCursor returns the opaque token handed to clients as next_cursor.
+func EncodeCursor(c Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a token produced by EncodeCursor. An empty token means the first page.
+func DecodeCursor(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var c Cursor
+	if err = json.Unmarshal(raw, &c); err != nil || c.Sort == "" || c.Id <= 0 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	in := Cursor{Sort: "rating", Key: "7.7", Id: 42}
+
+	out, err := DecodeCursor(EncodeCursor(in))
+	require.NoError(t, err)
+	require.Equal(t, &in, out)
+
+	out, err = DecodeCursor("")
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	for _, token := range []string{"not base64!", "bm90IGpzb24", EncodeCursor(Cursor{Sort: "rating"})} {
+		_, err = DecodeCursor(token)
+		require.EqualError(t, err, "invalid cursor")
+	}
+}
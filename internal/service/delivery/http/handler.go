@@ -1,18 +1,28 @@
 package http
 
 import (
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"film_library/internal/auth"
 	"film_library/internal/cconstant"
 	"film_library/internal/service"
 	"fmt"
+	"github.com/gorilla/mux"
+	"golang.org/x/text/unicode/norm"
 	"log"
 	"net/http"
-	"regexp"
+	"net/url"
+	"reflect"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+var errBadRequest = errors.New("bad request")
+
 type ServiceHandler struct {
 	serviceUC service.Usecase
 	authUC    auth.Usecase
@@ -27,7 +37,7 @@ func NewServiceHandler(serviceUC service.Usecase, authUC auth.Usecase) *ServiceH
 
 // @Summary      CreateActor
 // @Description  Add actor
-// @Tags         actor
+// @Tags         actor, person
 // @Accept       json
 // @Produce      json
 // @Param 		 Authorization 	header 	string true  "Authorization"
@@ -36,6 +46,7 @@ func NewServiceHandler(serviceUC service.Usecase, authUC auth.Usecase) *ServiceH
 // @Failure      400  {object}	error
 // @Failure      500  {object}  error
 // @Router       /actor/add [post]
+// @Router       /person/add [post]
 func (s *ServiceHandler) CreateActor(rw http.ResponseWriter, r *http.Request) {
 	var (
 		data service.Actor
@@ -43,11 +54,6 @@ func (s *ServiceHandler) CreateActor(rw http.ResponseWriter, r *http.Request) {
 	)
 
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
-	if tokenData.Role == 0 {
-		log.Printf("Request: CreateActor. Error: %s", "Don't have permission")
-		http.Error(rw, fmt.Sprintf("You don't have permission for this operation."), http.StatusForbidden)
-		return
-	}
 	log.Printf("Request: CreateActor. User with ID:%d", tokenData.Id)
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
@@ -62,112 +68,169 @@ func (s *ServiceHandler) CreateActor(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := s.serviceUC.CreateActor(&data)
+	id, err := s.serviceUC.CreateActor(r.Context(), &data)
 	if err != nil {
 		log.Printf("Request: CreateActor. Error: %s", err)
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
-	rw.WriteHeader(http.StatusOK)
+	resp.Id = id
+
 	rawResponse, _ := json.Marshal(resp)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
 // @Summary      GetActor
 // @Description  Get actor
-// @Tags         actor
+// @Tags         actor, person
 // @Accept       json
 // @Produce      json
 // @Param 		 Authorization 	header 	string true  "Authorization"
 // @Param        q	query string  false  "actor name"
+// @Param        id	path  int     false  "actor id"
+// @Param        expand	query string  false  "Embed the films as full objects" Enums(films)
+// @Param        If-None-Match	header string  false  "ETag of a cached response, 304 while it is current"
 // @Success      200  {object}	service.Actor
+// @Success      304
 // @Failure      400  {object}	error
 // @Failure      500  {object}  error
+// @Failure      404  {object}	error
+// @Failure      409  {object}	error
 // @Router       /actor/get/{actor_name} [get]
+// @Router       /actor/{id} [get]
+// @Router       /person/{id} [get]
 func (s *ServiceHandler) GetActor(rw http.ResponseWriter, r *http.Request) {
 
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
 	log.Printf("Request: GetActor. User with ID:%d", tokenData.Id)
 
-	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
-	name = strings.ReplaceAll(name, "+", " ")
+	expand, err := expandParam(r, cconstant.ActorExpansions)
+	if err != nil {
+		log.Printf("Request: GetActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	actor, err := s.serviceUC.GetActor(name)
+	id, err := s.actorId(r)
 	if err != nil {
 		log.Printf("Request: GetActor. Error: %s", err.Error())
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
 
-	rw.WriteHeader(http.StatusOK)
+	actor, err := s.serviceUC.GetActor(id)
+	if err != nil {
+		log.Printf("Request: GetActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	if slices.Contains(expand, "films") {
+		if actor.FilmDetails, err = s.allActorFilms(id); err != nil {
+			log.Printf("Request: GetActor. Error: %s", err.Error())
+			http.Error(rw, err.Error(), errorStatus(err))
+			return
+		}
+	}
+
 	rawResponse, _ := json.Marshal(actor)
+	tag := etag(actor.Version, rawResponse)
+	rw.Header().Set("ETag", tag)
+	if noneMatch(r, tag) {
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
 // @Summary      GetActors
 // @Description  Get actors
-// @Tags         actor
+// @Tags         actor, person
 // @Accept       json
 // @Produce      json
 // @Param 		 Authorization 	header 	string true  "Authorization"
-// @Param        Sort 			header  string true	 "Sort"
-// @Success      200  {array}	service.Actor
+// @Param        Sort 			header  string false "Sort"
+// @Param        sort 			query   string false "Sort"
+// @Param        limit 			query   int    false "Page size, 20 by default"
+// @Param        order 			query   string false "asc or desc, asc by default"
+// @Param        cursor 		query   string false "next_cursor of the previous page"
+// @Param        job 			query   string false "Only people with this crew job" Enums(director, writer, producer, composer, cinematographer)
+// @Success      200  {object}	service.ActorsPage
 // @Failure      400  {object}	error
 // @Failure      500  {object}  error
 // @Router       /actor/get_all [get]
+// @Router       /person/get_all [get]
 func (s *ServiceHandler) GetActors(rw http.ResponseWriter, r *http.Request) {
 
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
 	log.Printf("Request: GetActors. User with ID:%d", tokenData.Id)
 
-	sort := r.Header.Get("Sort")
-	if idx := slices.IndexFunc(cconstant.FieldsActor, func(c string) bool { return c == sort }); idx == -1 {
-		sort = "actor_name"
+	params, err := detailsParams(r, cconstant.FieldsActor, "actor_name")
+	if err != nil {
+		log.Printf("Request: GetActors. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if params.Job = r.URL.Query().Get("job"); params.Job != "" && !slices.Contains(cconstant.CrewJobs, params.Job) {
+		log.Printf("Request: GetActors. Error: unknown job %q", params.Job)
+		http.Error(rw, fmt.Sprintf("job should be one of %s", strings.Join(cconstant.CrewJobs, ", ")), http.StatusBadRequest)
+		return
 	}
 
-	actor, err := s.serviceUC.GetActors(&service.DetailsParams{Sort: sort})
+	actor, err := s.serviceUC.GetActors(params)
 	if err != nil {
 		log.Printf("Request: GetActors. Error: %s", err.Error())
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
 
-	rw.WriteHeader(http.StatusOK)
 	rawResponse, _ := json.Marshal(actor)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
 // @Summary      UpdateActor
-// @Description  Update actor
-// @Tags         actor
+// @Description  Update actor with a JSON Merge Patch: only the given fields change, all of them required
+// @Tags         actor, person
 // @Accept       json
+// @Accept       application/merge-patch+json
 // @Produce      json
 // @Param 		 Authorization 	header 	string true  "Authorization"
 // @Param        q		query 	string  	   false  "actor name"
+// @Param        id	path 	int  		   false  "actor id"
 // @Param        input	body	service.Actor  true   "actor data"
+// @Param        If-Match	header string  false  "ETag from GET, the change is made only while it is current"
 // @Success      200  {object}	service.ResponseModel
 // @Failure      400  {object}	error
 // @Failure      500  {object}  error
+// @Failure      404  {object}	error
+// @Failure      409  {object}	error
+// @Failure      412  {object}	error
 // @Router       /actor/update/{actor_name} [patch]
+// @Router       /actor/{id} [patch]
+// @Router       /person/{id} [patch]
 func (s *ServiceHandler) UpdateActor(rw http.ResponseWriter, r *http.Request) {
 	var (
-		data service.Actor
+		data service.ActorPatch
 		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
 	)
 
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
-	if tokenData.Role == 0 {
-		log.Printf("Request: UpdateActor. Error: %s", "Don't have permission")
-		http.Error(rw, fmt.Sprintf("You don't have permission for this operation."), http.StatusForbidden)
-		return
-	}
 	log.Printf("Request: UpdateActor. User with ID:%d", tokenData.Id)
 
-	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
-	name = strings.ReplaceAll(name, "+", " ")
+	id, err := s.actorId(r)
+	if err != nil {
+		log.Printf("Request: UpdateActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		log.Printf("Request: UpdateActor. Error: %s", err.Error())
@@ -175,85 +238,268 @@ func (s *ServiceHandler) UpdateActor(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := s.serviceUC.UpdateActor(name, &data)
+	if err := service.ValidateActorPatch(&data); err != nil {
+		log.Printf("Request: UpdateActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if data.Version, err = ifMatch(r); err != nil {
+		log.Printf("Request: UpdateActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	err = s.serviceUC.UpdateActor(r.Context(), id, &data)
 	if err != nil {
-		log.Printf("Request: UpdateActor. Error: %s", resp.Error)
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		log.Printf("Request: UpdateActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
-	rw.WriteHeader(http.StatusOK)
 	rawResponse, _ := json.Marshal(resp)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
 // @Summary      DeleteActor
-// @Description  Delete actor
-// @Tags         actor
+// @Description  Move actor to the trash, from where it can be restored until it is purged
+// @Tags         actor, person
 // @Accept       json
 // @Produce      json
 // @Param 		 Authorization 	header 	string true  "Authorization"
 // @Param        q		query 	string  	   false  "actor name"
+// @Param        id	path 	int  		   false  "actor id"
+// @Param        If-Match	header string  false  "ETag from GET, the change is made only while it is current"
 // @Success      200  {object}	service.ResponseModel
 // @Failure      400  {object}	error
 // @Failure      500  {object}  error
+// @Failure      404  {object}	error
+// @Failure      409  {object}	error
+// @Failure      412  {object}	error
 // @Router       /actor/delete/{actor_name} [delete]
+// @Router       /actor/{id} [delete]
+// @Router       /person/{id} [delete]
 func (s *ServiceHandler) DeleteActor(rw http.ResponseWriter, r *http.Request) {
 	var (
 		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
 	)
 
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
-	if tokenData.Role == 0 {
-		log.Printf("Request: DeleteActor. Error: %s", "Don't have permission")
-		http.Error(rw, fmt.Sprintf("You don't have permission for this operation."), http.StatusForbidden)
+	log.Printf("Request: DeleteActor. User with ID:%d", tokenData.Id)
+
+	id, err := s.actorId(r)
+	if err != nil {
+		log.Printf("Request: DeleteActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
-	log.Printf("Request: DeleteActor. User with ID:%d", tokenData.Id)
 
-	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
-	name = strings.ReplaceAll(name, "+", " ")
+	version, err := ifMatch(r)
+	if err != nil {
+		log.Printf("Request: DeleteActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
 
-	err := s.serviceUC.DeleteActor(name)
+	err = s.serviceUC.DeleteActor(r.Context(), id, tokenData.Id, version)
 	if err != nil {
-		log.Printf("Request: DeleteActor. Error: %s", resp.Error)
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		log.Printf("Request: DeleteActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
 	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      RestoreActor
+// @Description  Take a deleted actor out of the trash together with its relations.
+// @Description  409 when another actor with the same name has been added meanwhile. Admins only.
+// @Tags         actor, person
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path 	int  true  "actor id"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      403  {object}	error
+// @Failure      404  {object}	error
+// @Failure      409  {object}	error
+// @Failure      500  {object}  error
+// @Router       /actor/{id}/restore [post]
+// @Router       /person/{id}/restore [post]
+func (s *ServiceHandler) RestoreActor(rw http.ResponseWriter, r *http.Request) {
+	var (
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: RestoreActor. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: RestoreActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	if err = s.serviceUC.RestoreActor(r.Context(), id); err != nil {
+		log.Printf("Request: RestoreActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
 	rawResponse, _ := json.Marshal(resp)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
 // @Summary      SearchActor
-// @Description  Search actor
-// @Tags         actor
+// @Description  Full-text search over the actor name, best matches first.
+// @Description  mode=fuzzy matches the actor name by trigram similarity instead and tolerates typos.
+// @Description  When full-text search finds nothing, did_you_mean holds the closest actor name.
+// @Tags         actor, person
 // @Accept       json
 // @Produce      json
 // @Param 		 Authorization 	header 	string true  "Authorization"
-// @Param        q				query 	string false "pattern"
-// @Success      200  {array}	string
+// @Param        q				query 	string true  "search query, supports \"quoted phrases\", or and -exclusion"
+// @Param        mode			query 	string false "search mode" Enums(fts, fuzzy)
+// @Param        threshold		query 	number false "min similarity (0;1] for fuzzy search and suggestions"
+// @Param        limit			query 	int    false "max results [1;100]"
+// @Success      200  {object}	service.ActorSearchResult
 // @Failure      400  {object}	error
 // @Failure      500  {object}  error
+// @Router       /actor/search [get]
 // @Router       /actor/search/{actor_name} [get]
+// @Router       /person/search [get]
 func (s *ServiceHandler) SearchActor(rw http.ResponseWriter, r *http.Request) {
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
 	log.Printf("Request: SearchActor. User with ID:%d", tokenData.Id)
 
-	pattern := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
-	pattern = strings.ReplaceAll(pattern, "+", " ")
+	params, err := searchParams(r, "actor_name")
+	if err != nil {
+		log.Printf("Request: SearchActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	films, err := s.serviceUC.SearchActor(pattern)
+	resp, err := s.serviceUC.SearchActor(params)
 	if err != nil {
 		log.Printf("Request: SearchActor. Error: %s", err.Error())
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetActorPath
+// @Description  Get the shortest chain actor -> film -> actor ... connecting two actors
+// @Tags         actor
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        from 			query   int    true  "id of the first actor"
+// @Param        to 			query   int    true  "id of the second actor"
+// @Param        max_depth 		query   int    false "Longest chain in films, 6 by default and at most"
+// @Success      200  {object}	service.ActorPath
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Failure      503  {object}  error
+// @Router       /actor/path [get]
+func (s *ServiceHandler) GetActorPath(rw http.ResponseWriter, r *http.Request) {
+	var (
+		query  = r.URL.Query()
+		params service.PathParams
+		err    error
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetActorPath. User with ID:%d", tokenData.Id)
+
+	if params.From, err = parseId(query.Get("from")); err == nil {
+		params.To, err = parseId(query.Get("to"))
+	}
+	if err != nil {
+		log.Printf("Request: GetActorPath. Error: %s", err.Error())
+		http.Error(rw, "from and to should be actor ids", http.StatusBadRequest)
+		return
+	}
+
+	if rawDepth := query.Get("max_depth"); rawDepth != "" {
+		params.MaxDepth, err = strconv.Atoi(rawDepth)
+		if err != nil || params.MaxDepth <= 0 || params.MaxDepth > cconstant.MaxPathDepth {
+			log.Printf("Request: GetActorPath. Error: bad max_depth %q", rawDepth)
+			http.Error(rw, fmt.Sprintf("max_depth should be [1;%d]", cconstant.MaxPathDepth), http.StatusBadRequest)
+			return
+		}
+	}
+
+	resp, err := s.serviceUC.GetActorPath(r.Context(), &params)
+	if err != nil {
+		log.Printf("Request: GetActorPath. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
 
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
 	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetActorFilms
+// @Description  Get the films of the actor as full objects
+// @Tags         actor, film
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "actor id"
+// @Param        sort 			query   string false "Sort, release_date by default" Enums(film_name, release_date, rating, description)
+// @Param        limit 			query   int    false "Page size, 20 by default"
+// @Param        order 			query   string false "asc or desc, rating is desc by default, other fields asc"
+// @Param        cursor 		query   string false "next_cursor of the previous page"
+// @Success      200  {object}	service.FilmsPage
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /actor/{id}/films [get]
+func (s *ServiceHandler) GetActorFilms(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetActorFilms. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: GetActorFilms. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	params, err := detailsParams(r, cconstant.FieldsFilm, "release_date")
+	if err != nil {
+		log.Printf("Request: GetActorFilms. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	films, err := s.serviceUC.GetActorFilms(id, params)
+	if err != nil {
+		log.Printf("Request: GetActorFilms. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
 	rawResponse, _ := json.Marshal(films)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
@@ -275,11 +521,6 @@ func (s *ServiceHandler) CreateFilm(rw http.ResponseWriter, r *http.Request) {
 	)
 
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
-	if tokenData.Role == 0 {
-		log.Printf("Request: CreateFilm. Error: %s", "Don't have permission")
-		http.Error(rw, fmt.Sprintf("You don't have permission for this operation."), http.StatusForbidden)
-		return
-	}
 	log.Printf("Request: CreateFilm. User with ID:%d", tokenData.Id)
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
@@ -289,51 +530,87 @@ func (s *ServiceHandler) CreateFilm(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := s.validateFilm(&data); err != nil {
-		log.Printf("Request: CreateFilm. Error: %s", "Uncorrect data")
+		log.Printf("Request: CreateFilm. Error: %s", err.Error())
 		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	err := s.serviceUC.CreateFilm(&data)
+	id, err := s.serviceUC.CreateFilm(r.Context(), &data)
 	if err != nil {
-		log.Printf("Request: CreateFilm. Error: %s", resp.Error)
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		log.Printf("Request: CreateFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
-	rw.WriteHeader(http.StatusOK)
+	resp.Id = id
+
 	rawResponse, _ := json.Marshal(resp)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
 // @Summary      GetFilm
-// @Description  Get film
+// @Description  Get film with its cast in billing order, crew and the aggregate of user ratings
 // @Tags         film
 // @Accept       json
 // @Produce      json
 // @Param 		 Authorization 	header 	string true  "Authorization"
 // @Param        q	query string  false  "film name"
+// @Param        id	path  int     false  "film id"
+// @Param        expand	query string  false  "Embed the cast as full objects" Enums(actors)
+// @Param        If-None-Match	header string  false  "ETag of a cached response, 304 while it is current"
 // @Success      200  {object}	service.Film
+// @Success      304
 // @Failure      400  {object}	error
 // @Failure      500  {object}  error
-// @Router       /film/get/{actor_name} [get]
+// @Failure      404  {object}	error
+// @Failure      409  {object}	error
+// @Router       /film/get/{film_name} [get]
+// @Router       /film/{id} [get]
 func (s *ServiceHandler) GetFilm(rw http.ResponseWriter, r *http.Request) {
 
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
 	log.Printf("Request: GetFilm. User with ID:%d", tokenData.Id)
 
-	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
-	name = strings.ReplaceAll(name, "+", " ")
+	expand, err := expandParam(r, cconstant.FilmExpansions)
+	if err != nil {
+		log.Printf("Request: GetFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := s.filmId(r)
+	if err != nil {
+		log.Printf("Request: GetFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
 
-	actor, err := s.serviceUC.GetFilm(name)
+	film, err := s.serviceUC.GetFilm(id)
 	if err != nil {
-		log.Printf("GetFilm: CreateFilm. Error: %s", err.Error())
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		log.Printf("Request: GetFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	if slices.Contains(expand, "actors") {
+		if film.ActorDetails, err = s.allFilmActors(id); err != nil {
+			log.Printf("Request: GetFilm. Error: %s", err.Error())
+			http.Error(rw, err.Error(), errorStatus(err))
+			return
+		}
+	}
+
+	rawResponse, _ := json.Marshal(film)
+	tag := etag(film.Version, rawResponse)
+	rw.Header().Set("ETag", tag)
+	if noneMatch(r, tag) {
+		rw.WriteHeader(http.StatusNotModified)
 		return
 	}
 
-	rw.WriteHeader(http.StatusOK)
-	rawResponse, _ := json.Marshal(actor)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
@@ -343,8 +620,21 @@ func (s *ServiceHandler) GetFilm(rw http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Param 		 Authorization 	header 	string true  "Authorization"
-// @Param        Sort 			header  string true	 "Sort"
-// @Success      200  {array}	service.Film
+// @Param        Sort 			header  string false "Sort"
+// @Param        sort 			query   string false "Sort"
+// @Param        limit 			query   int    false "Page size, 20 by default"
+// @Param        order 			query   string false "asc or desc, rating is desc by default, other fields asc"
+// @Param        cursor 		query   string false "next_cursor of the previous page"
+// @Param        released_from 	query   string false "Released on or after, 2000-01-01 format"
+// @Param        released_to 	query   string false "Released on or before, 2000-01-01 format"
+// @Param        rating_from 	query   number false "Minimal rating"
+// @Param        rating_to 		query   number false "Maximal rating"
+// @Param        actor_id 		query   []int  false "Films with every one of these actors" collectionFormat(multi)
+// @Param        actor 			query   []string false "Films with every one of these actor names" collectionFormat(multi)
+// @Param        genre_id 		query   []int  false "Films in every one of these genres" collectionFormat(multi)
+// @Param        genre 			query   []string false "Films in every one of these genre names" collectionFormat(multi)
+// @Param        keyword 		query   string false "Keyword in description"
+// @Success      200  {object}	service.FilmsPage
 // @Failure      400  {object}	error
 // @Failure      500  {object}  error
 // @Router       /film/get_all [get]
@@ -353,53 +643,66 @@ func (s *ServiceHandler) GetFilms(rw http.ResponseWriter, r *http.Request) {
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
 	log.Printf("Request: GetFilms. User with ID:%d", tokenData.Id)
 
-	sort := r.Header.Get("Sort")
+	params, err := detailsParams(r, cconstant.FieldsFilm, "rating")
+	if err != nil {
+		log.Printf("Request: GetFilms. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	if idx := slices.IndexFunc(cconstant.FieldsFilm, func(c string) bool { return c == sort }); idx == -1 {
-		sort = "rating"
+	if params.Filter, err = filmFilter(r); err != nil {
+		log.Printf("Request: GetFilms. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	films, err := s.serviceUC.GetFilms(&service.DetailsParams{Sort: sort})
+	films, err := s.serviceUC.GetFilms(params)
 	if err != nil {
 		log.Printf("Request: GetFilms. Error: %s", err.Error())
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
 
-	rw.WriteHeader(http.StatusOK)
 	rawResponse, _ := json.Marshal(films)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
 // @Summary      UpdateFilm
-// @Description  Update film
+// @Description  Update film with a JSON Merge Patch: only the given fields change, "desc": null clears the description
 // @Tags         film
 // @Accept       json
+// @Accept       application/merge-patch+json
 // @Produce      json
 // @Param 		 Authorization 	header 	string true  "Authorization"
 // @Param        q		query 	string  	   false  "film name"
+// @Param        id	path 	int  		   false  "film id"
 // @Param        input	body	service.Film   true   "new film data"
+// @Param        If-Match	header string  false  "ETag from GET, the change is made only while it is current"
 // @Success      200  {object}	service.ResponseModel
 // @Failure      400  {object}	error
 // @Failure      500  {object}  error
+// @Failure      404  {object}	error
+// @Failure      409  {object}	error
+// @Failure      412  {object}	error
 // @Router       /film/update/{film_name} [patch]
+// @Router       /film/{id} [patch]
 func (s *ServiceHandler) UpdateFilm(rw http.ResponseWriter, r *http.Request) {
 	var (
-		data service.Film
+		data service.FilmPatch
 		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
 	)
 
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
-	if tokenData.Role == 0 {
-		log.Printf("Request: UpdateFilm. Error: %s", "Don't have permission")
-		http.Error(rw, fmt.Sprintf("You don't have permission for this operation."), http.StatusForbidden)
-		return
-	}
 	log.Printf("Request: UpdateFilm. User with ID:%d", tokenData.Id)
 
-	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
-	name = strings.ReplaceAll(name, "+", " ")
+	id, err := s.filmId(r)
+	if err != nil {
+		log.Printf("Request: UpdateFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
 		log.Printf("Request: UpdateFilm. Error: %s", err.Error())
@@ -407,134 +710,335 @@ func (s *ServiceHandler) UpdateFilm(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := s.serviceUC.UpdateFilm(name, &data)
+	if err := service.ValidateFilmPatch(&data); err != nil {
+		log.Printf("Request: UpdateFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if data.Version, err = ifMatch(r); err != nil {
+		log.Printf("Request: UpdateFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	err = s.serviceUC.UpdateFilm(r.Context(), id, &data)
 	if err != nil {
-		log.Printf("Request: UpdateFilm. Error: %s", resp.Error)
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		log.Printf("Request: UpdateFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
-	rw.WriteHeader(http.StatusOK)
 	rawResponse, _ := json.Marshal(resp)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
 // @Summary      DeleteFilm
-// @Description  Delete film
+// @Description  Move film to the trash, from where it can be restored until it is purged
 // @Tags         film
 // @Accept       json
 // @Produce      json
 // @Param 		 Authorization 	header 	string true   "Authorization"
 // @Param        q				query 	string false  "film name"
+// @Param        id				path 	int    false  "film id"
+// @Param        If-Match	header string  false  "ETag from GET, the change is made only while it is current"
 // @Success      200  {object}	service.ResponseModel
 // @Failure      400  {object}	error
 // @Failure      500  {object}  error
+// @Failure      404  {object}	error
+// @Failure      409  {object}	error
+// @Failure      412  {object}	error
 // @Router       /film/delete/{film_name} [delete]
+// @Router       /film/{id} [delete]
 func (s *ServiceHandler) DeleteFilm(rw http.ResponseWriter, r *http.Request) {
 	var (
 		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
 	)
 
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
-	if tokenData.Role == 0 {
-		log.Printf("Request: DeleteFilm. Error: %s", "Don't have permission")
-		http.Error(rw, fmt.Sprintf("You don't have permission for this operation."), http.StatusForbidden)
+	log.Printf("Request: DeleteFilm. User with ID:%d", tokenData.Id)
+
+	id, err := s.filmId(r)
+	if err != nil {
+		log.Printf("Request: DeleteFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
-	log.Printf("Request: DeleteFilm. User with ID:%d", tokenData.Id)
 
-	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
-	name = strings.ReplaceAll(name, "+", " ")
+	version, err := ifMatch(r)
+	if err != nil {
+		log.Printf("Request: DeleteFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
 
-	err := s.serviceUC.DeleteFilm(name)
+	err = s.serviceUC.DeleteFilm(r.Context(), id, tokenData.Id, version)
 	if err != nil {
-		log.Printf("Request: DeleteFilm. Error: %s", resp.Error)
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		log.Printf("Request: DeleteFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
-	rw.WriteHeader(http.StatusOK)
 	rawResponse, _ := json.Marshal(resp)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
-// @Summary      SearchFilms
-// @Description  Search films
+// @Summary      RestoreFilm
+// @Description  Take a deleted film out of the trash together with its relations.
+// @Description  409 when another film with the same name has been added meanwhile. Admins only.
 // @Tags         film
 // @Accept       json
 // @Produce      json
 // @Param 		 Authorization 	header 	string true  "Authorization"
-// @Param        q				query 	string false "pattern"
-// @Success      200  {array}	string
+// @Param        id	path 	int  true  "film id"
+// @Success      200  {object}	service.ResponseModel
 // @Failure      400  {object}	error
+// @Failure      403  {object}	error
+// @Failure      404  {object}	error
+// @Failure      409  {object}	error
 // @Failure      500  {object}  error
-// @Router       /film/search/{film_name} [get]
-func (s *ServiceHandler) SearchFilms(rw http.ResponseWriter, r *http.Request) {
-	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
-	log.Printf("Request: SearchFilms. User with ID:%d", tokenData.Id)
+// @Router       /film/{id}/restore [post]
+func (s *ServiceHandler) RestoreFilm(rw http.ResponseWriter, r *http.Request) {
+	var (
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
 
-	pattern := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
-	pattern = strings.ReplaceAll(pattern, "+", " ")
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: RestoreFilm. User with ID:%d", tokenData.Id)
 
-	films, err := s.serviceUC.SearchFilms(pattern)
+	id, err := parseId(mux.Vars(r)["id"])
 	if err != nil {
-		log.Printf("Request: DeleteFilm. Error: %s", err.Error())
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		log.Printf("Request: RestoreFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
 
-	rw.WriteHeader(http.StatusOK)
-	rawResponse, _ := json.Marshal(films)
+	if err = s.serviceUC.RestoreFilm(r.Context(), id); err != nil {
+		log.Printf("Request: RestoreFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
-// @Summary      AddFilmsByActor
-// @Description  Add films by actor
-// @Tags         relation
+// @Summary      SearchFilms
+// @Description  Full-text search over the film name and description, best matches first.
+// @Description  mode=fuzzy matches the film name by trigram similarity instead and tolerates typos.
+// @Description  When full-text search finds nothing, did_you_mean holds the closest film name.
+// @Tags         film
 // @Accept       json
 // @Produce      json
 // @Param 		 Authorization 	header 	string true  "Authorization"
-// @Param        input	body	service.AddFilmsByActorParams  true   "relation data"
-// @Success      200  {object}	service.ResponseModel
+// @Param        q				query 	string true  "search query, supports \"quoted phrases\", or and -exclusion"
+// @Param        lang			query 	string false "text search configuration" Enums(english, russian, simple)
+// @Param        mode			query 	string false "search mode" Enums(fts, fuzzy)
+// @Param        threshold		query 	number false "min similarity (0;1] for fuzzy search and suggestions"
+// @Param        limit			query 	int    false "max results [1;100]"
+// @Success      200  {object}	service.FilmSearchResult
 // @Failure      400  {object}	error
 // @Failure      500  {object}  error
-// @Router       /relation/films_by_actor [post]
-func (s *ServiceHandler) AddFilmsByActor(rw http.ResponseWriter, r *http.Request) {
-	var (
-		data service.AddFilmsByActorParams
-		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
-	)
-
+// @Router       /film/search [get]
+// @Router       /film/search/{film_name} [get]
+func (s *ServiceHandler) SearchFilms(rw http.ResponseWriter, r *http.Request) {
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
-	if tokenData.Role == 0 {
-		log.Printf("Request: AddFilmsByActor. Error: %s", "Don't have permission")
-		http.Error(rw, fmt.Sprintf("You don't have permission for this operation."), http.StatusForbidden)
-		return
-	}
-	log.Printf("Request: AddFilmsByActor. User with ID:%d", tokenData.Id)
+	log.Printf("Request: SearchFilms. User with ID:%d", tokenData.Id)
 
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-		log.Printf("Request: AddFilmsByActor. Error: %s", err.Error())
+	params, err := searchParams(r, "film_name")
+	if err != nil {
+		log.Printf("Request: SearchFilms. Error: %s", err.Error())
 		http.Error(rw, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if len(data.Films) == 0 {
-		log.Printf("Request: AddFilmsByActor. Error: %s", "Uncorrect data")
-		http.Error(rw, fmt.Sprintf("len data should be > 0"), http.StatusBadRequest)
+	resp, err := s.serviceUC.SearchFilms(params)
+	if err != nil {
+		log.Printf("Request: SearchFilms. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetRelatedFilms
+// @Description  Get films sharing actors or genres with the film, ranked by shared actors, then shared genres, then rating proximity
+// @Tags         film
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "film id"
+// @Param        limit 			query   int    false "Number of films, 20 by default"
+// @Success      200  {array}	service.RelatedFilm
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /film/{id}/similar [get]
+func (s *ServiceHandler) GetRelatedFilms(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetRelatedFilms. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: GetRelatedFilms. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	limit, err := limitParam(r)
+	if err != nil {
+		log.Printf("Request: GetRelatedFilms. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.serviceUC.GetRelatedFilms(id, limit)
+	if err != nil {
+		log.Printf("Request: GetRelatedFilms. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetFilmActors
+// @Description  Get the cast of the film as full objects
+// @Tags         film, actor
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "film id"
+// @Param        sort 			query   string false "Sort, actor_name by default" Enums(actor_name, sex, bdate)
+// @Param        limit 			query   int    false "Page size, 20 by default"
+// @Param        order 			query   string false "asc or desc, asc by default"
+// @Param        cursor 		query   string false "next_cursor of the previous page"
+// @Success      200  {object}	service.ActorsPage
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /film/{id}/actors [get]
+func (s *ServiceHandler) GetFilmActors(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetFilmActors. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: GetFilmActors. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	params, err := detailsParams(r, cconstant.FieldsActor, "actor_name")
+	if err != nil {
+		log.Printf("Request: GetFilmActors. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actors, err := s.serviceUC.GetFilmActors(id, params)
+	if err != nil {
+		log.Printf("Request: GetFilmActors. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(actors)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetRecommendations
+// @Description  Get films you have not rated, ranked by the rating you are expected to give them. Users who rate films alike rate other films alike; without ratings you get the best rated films
+// @Tags         film
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        limit 			query   int    false "Number of films, 20 by default"
+// @Success      200  {array}	service.Recommendation
+// @Failure      400  {object}	error
+// @Failure      500  {object}  error
+// @Router       /me/recommendations [get]
+func (s *ServiceHandler) GetRecommendations(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetRecommendations. User with ID:%d", tokenData.Id)
+
+	limit, err := limitParam(r)
+	if err != nil {
+		log.Printf("Request: GetRecommendations. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	err := s.serviceUC.AddFilmsByActor(&data)
+	resp, err := s.serviceUC.GetRecommendations(tokenData.Id, limit)
 	if err != nil {
-		log.Printf("Request: AddFilmsByActor. Error: %s", resp.Error)
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		log.Printf("Request: GetRecommendations. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
 	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      AddFilmsByActor
+// @Description  Add films by actor
+// @Tags         relation
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        input	body	service.AddFilmsByActorParams  true   "relation data"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      500  {object}  error
+// @Router       /relation/films_by_actor [post]
+func (s *ServiceHandler) AddFilmsByActor(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data service.AddFilmsByActorParams
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: AddFilmsByActor. User with ID:%d", tokenData.Id)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: AddFilmsByActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(data.Films)+len(data.FilmIds) == 0 {
+		log.Printf("Request: AddFilmsByActor. Error: %s", "Uncorrect data")
+		http.Error(rw, fmt.Sprintf("len data should be > 0"), http.StatusBadRequest)
+		return
+	}
+
+	err := s.serviceUC.AddFilmsByActor(r.Context(), &data)
+	if err != nil {
+		log.Printf("Request: AddFilmsByActor. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
 	rawResponse, _ := json.Marshal(resp)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
@@ -556,11 +1060,6 @@ func (s *ServiceHandler) AddActorsByFilm(rw http.ResponseWriter, r *http.Request
 	)
 
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
-	if tokenData.Role == 0 {
-		log.Printf("Request: AddActorsByFilm. Error: %s", "Don't have permission")
-		http.Error(rw, fmt.Sprintf("You don't have permission for this operation."), http.StatusForbidden)
-		return
-	}
 	log.Printf("Request: AddActorsByFilm. User with ID:%d", tokenData.Id)
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
@@ -569,21 +1068,21 @@ func (s *ServiceHandler) AddActorsByFilm(rw http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if len(data.Actors) == 0 {
+	if len(data.Actors)+len(data.ActorIds) == 0 {
 		log.Printf("Request: AddActorsByFilm. Error: %s", "Uncorrect data")
 		http.Error(rw, fmt.Sprintf("len data should be > 0"), http.StatusBadRequest)
 		return
 	}
 
-	err := s.serviceUC.AddActorsByFilm(&data)
+	err := s.serviceUC.AddActorsByFilm(r.Context(), &data)
 	if err != nil {
-		log.Printf("Request: AddActorsByFilm. Error: %s", resp.Error)
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		log.Printf("Request: AddActorsByFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
-	rw.WriteHeader(http.StatusOK)
 	rawResponse, _ := json.Marshal(resp)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
@@ -605,11 +1104,6 @@ func (s *ServiceHandler) DeleteActorFilm(rw http.ResponseWriter, r *http.Request
 	)
 
 	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
-	if tokenData.Role == 0 {
-		log.Printf("Request: DeleteActorFilm. Error: %s", "Don't have permission")
-		http.Error(rw, fmt.Sprintf("You don't have permission for this operation."), http.StatusForbidden)
-		return
-	}
 	log.Printf("Request: DeleteActorFilm. User with ID:%d", tokenData.Id)
 
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
@@ -618,50 +1112,1397 @@ func (s *ServiceHandler) DeleteActorFilm(rw http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err := s.serviceUC.DeleteActorFilm(&data)
+	if (data.ActorId == 0 && data.Actor == "") || (data.FilmId == 0 && data.Film == "") {
+		log.Printf("Request: DeleteActorFilm. Error: %s", "Uncorrect data")
+		http.Error(rw, fmt.Sprintf("actor and film should be set by id or name"), http.StatusBadRequest)
+		return
+	}
+
+	err := s.serviceUC.DeleteActorFilm(r.Context(), &data)
 	if err != nil {
-		log.Printf("Request: DeleteActorFilm. Error: %s", resp.Error)
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		log.Printf("Request: DeleteActorFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
 		return
 	}
-	rw.WriteHeader(http.StatusOK)
 	rawResponse, _ := json.Marshal(resp)
 	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
 	_, _ = rw.Write(rawResponse)
 }
 
-//---------------------------------------------------------------------------------------------------------------------
+// @Summary      UpdateCredit
+// @Description  Change the character, credit type (lead, supporting, cameo, voice) or billing order
+// @Description  of an actor in a film. Only the fields present in the body are changed,
+// @Description  an empty character clears it.
+// @Tags         relation
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        input	body	service.UpdateCreditParams  true   "credit data"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /relation/credit [patch]
+func (s *ServiceHandler) UpdateCredit(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data service.UpdateCreditParams
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
 
-func (s *ServiceHandler) validateActor(data *service.Actor) error {
-	if len(data.Name) == 0 || len(data.Name) > 100 {
-		return fmt.Errorf("size Name should be [1;100]")
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: UpdateCredit. User with ID:%d", tokenData.Id)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: UpdateCredit. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
 	}
-	if data.Sex != "f" && data.Sex != "m" {
-		return fmt.Errorf("sex should be 'm' - male or 'f' - famale")
+
+	if err := s.validateCredit(&data); err != nil {
+		log.Printf("Request: UpdateCredit. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
 	}
-	patternDate, _ := regexp.Compile("[1-2][0-9][0-9][0-9]-[0-1][0-9]-[0-3][0-9]")
-	if !patternDate.MatchString(data.BDate) {
-		return fmt.Errorf("bdate should be '2000-01-01' format")
+
+	err := s.serviceUC.UpdateCredit(r.Context(), &data)
+	if err != nil {
+		log.Printf("Request: UpdateCredit. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
 	}
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
 
-	return nil
+// @Summary      AddCrew
+// @Description  Add a person to the film crew as director, writer, producer, composer or cinematographer
+// @Tags         relation
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        input	body	service.CrewParams  true   "crew data"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /relation/crew [post]
+func (s *ServiceHandler) AddCrew(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data service.CrewParams
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: AddCrew. User with ID:%d", tokenData.Id)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: AddCrew. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.validateCrew(&data); err != nil {
+		log.Printf("Request: AddCrew. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := s.serviceUC.AddCrew(r.Context(), &data)
+	if err != nil {
+		log.Printf("Request: AddCrew. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
 }
 
-func (s *ServiceHandler) validateFilm(data *service.Film) error {
+// @Summary      DeleteCrew
+// @Description  Remove a crew job of a person from the film
+// @Tags         relation
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        input	body	service.CrewParams  true   "crew data"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /relation/crew [delete]
+func (s *ServiceHandler) DeleteCrew(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data service.CrewParams
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: DeleteCrew. User with ID:%d", tokenData.Id)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: DeleteCrew. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.validateCrew(&data); err != nil {
+		log.Printf("Request: DeleteCrew. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	if len(data.Name) == 0 || len(data.Name) > 150 {
-		return fmt.Errorf("size Name should be [1;150]")
+	err := s.serviceUC.DeleteCrew(r.Context(), &data)
+	if err != nil {
+		log.Printf("Request: DeleteCrew. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
 	}
-	if len(data.Desc) > 1000 {
-		return fmt.Errorf("size Name should be < 1000 symbols")
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+//---------------------------------------------------- Genre ----------------------------------------------------------
+
+// @Summary      CreateGenre
+// @Description  Add genre. Admins only.
+// @Tags         genre
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        input	body	service.Genre  true  "genre data, only name is used"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      403  {object}	error
+// @Failure      409  {object}	error
+// @Failure      500  {object}  error
+// @Router       /genre/add [post]
+func (s *ServiceHandler) CreateGenre(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data service.Genre
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: CreateGenre. User with ID:%d", tokenData.Id)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: CreateGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
 	}
-	if data.Rating <= 0 || data.Rating > 10 {
-		return fmt.Errorf("rating should be (0;10]")
+
+	if err := s.validateGenre(&data); err != nil {
+		log.Printf("Request: CreateGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
 	}
-	patternDate, _ := regexp.Compile("[1-2][0-9][0-9][0-9]-[0-1][0-9]-[0-3][0-9]")
-	if !patternDate.MatchString(data.RDate) {
-		return fmt.Errorf("rdate should be '2000-01-01' format")
+
+	id, err := s.serviceUC.CreateGenre(r.Context(), &data)
+	if err != nil {
+		log.Printf("Request: CreateGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
 	}
+	resp.Id = id
 
-	return nil
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetGenre
+// @Description  Get genre with the number of its films
+// @Tags         genre
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "genre id"
+// @Success      200  {object}	service.Genre
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /genre/{id} [get]
+func (s *ServiceHandler) GetGenre(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetGenre. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: GetGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	resp, err := s.serviceUC.GetGenre(id)
+	if err != nil {
+		log.Printf("Request: GetGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetGenres
+// @Description  Get every genre with the number of its films, the largest genres first
+// @Tags         genre
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Success      200  {array}	service.Genre
+// @Failure      500  {object}  error
+// @Router       /genre/get_all [get]
+func (s *ServiceHandler) GetGenres(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetGenres. User with ID:%d", tokenData.Id)
+
+	resp, err := s.serviceUC.GetGenres()
+	if err != nil {
+		log.Printf("Request: GetGenres. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      UpdateGenre
+// @Description  Rename genre. Admins only.
+// @Tags         genre
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "genre id"
+// @Param        input	body	service.Genre  true  "genre data, only name is used"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      403  {object}	error
+// @Failure      404  {object}	error
+// @Failure      409  {object}	error
+// @Failure      500  {object}  error
+// @Router       /genre/{id} [patch]
+func (s *ServiceHandler) UpdateGenre(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data service.Genre
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: UpdateGenre. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: UpdateGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: UpdateGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.validateGenre(&data); err != nil {
+		log.Printf("Request: UpdateGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.serviceUC.UpdateGenre(r.Context(), id, &data)
+	if err != nil {
+		log.Printf("Request: UpdateGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      DeleteGenre
+// @Description  Delete genre, films keep the other genres. Admins only.
+// @Tags         genre
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "genre id"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      403  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /genre/{id} [delete]
+func (s *ServiceHandler) DeleteGenre(rw http.ResponseWriter, r *http.Request) {
+	var resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: DeleteGenre. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: DeleteGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	err = s.serviceUC.DeleteGenre(r.Context(), id)
+	if err != nil {
+		log.Printf("Request: DeleteGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      AddGenresByFilm
+// @Description  Add genres to film
+// @Tags         relation
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        input	body	service.AddGenresByFilmParams  true   "relation data"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /relation/genres_by_film [post]
+func (s *ServiceHandler) AddGenresByFilm(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data service.AddGenresByFilmParams
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: AddGenresByFilm. User with ID:%d", tokenData.Id)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: AddGenresByFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if (data.FilmId == 0 && data.Film == "") || len(data.Genres)+len(data.GenreIds) == 0 {
+		log.Printf("Request: AddGenresByFilm. Error: %s", "Uncorrect data")
+		http.Error(rw, fmt.Sprintf("film and at least one genre should be set by id or name"), http.StatusBadRequest)
+		return
+	}
+
+	err := s.serviceUC.AddGenresByFilm(r.Context(), &data)
+	if err != nil {
+		log.Printf("Request: AddGenresByFilm. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      DeleteFilmGenre
+// @Description  Remove genre from film
+// @Tags         relation
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        input	body	service.DeleteFilmGenreParams  true   "relation data"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /relation/genre [delete]
+func (s *ServiceHandler) DeleteFilmGenre(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data service.DeleteFilmGenreParams
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: DeleteFilmGenre. User with ID:%d", tokenData.Id)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: DeleteFilmGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if (data.FilmId == 0 && data.Film == "") || (data.GenreId == 0 && data.Genre == "") {
+		log.Printf("Request: DeleteFilmGenre. Error: %s", "Uncorrect data")
+		http.Error(rw, fmt.Sprintf("film and genre should be set by id or name"), http.StatusBadRequest)
+		return
+	}
+
+	err := s.serviceUC.DeleteFilmGenre(r.Context(), &data)
+	if err != nil {
+		log.Printf("Request: DeleteFilmGenre. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+//---------------------------------------------------- Review ---------------------------------------------------------
+
+// @Summary      CreateReview
+// @Description  Rate the film from 1 to 10, optionally with a text review. Every user can review a film once
+// @Tags         review
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "film id"
+// @Param        input	body	service.Review  true  "review data, only rating and text are used"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      409  {object}	error
+// @Failure      500  {object}  error
+// @Router       /film/{id}/reviews [post]
+func (s *ServiceHandler) CreateReview(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data service.Review
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: CreateReview. User with ID:%d", tokenData.Id)
+
+	filmId, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: CreateReview. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: CreateReview. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.validateReview(&data); err != nil {
+		log.Printf("Request: CreateReview. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data.FilmId, data.UserId = filmId, tokenData.Id
+
+	id, err := s.serviceUC.CreateReview(r.Context(), &data)
+	if err != nil {
+		log.Printf("Request: CreateReview. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+	resp.Id = id
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetReviews
+// @Description  Get reviews of the film, the newest first
+// @Tags         review
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "film id"
+// @Param        sort 			query   string false "created_at or rating" Enums(created_at, rating)
+// @Param        limit 			query   int    false "Page size, 20 by default"
+// @Param        order 			query   string false "asc or desc, desc by default"
+// @Param        cursor 		query   string false "next_cursor of the previous page"
+// @Success      200  {object}	service.ReviewsPage
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /film/{id}/reviews [get]
+func (s *ServiceHandler) GetReviews(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetReviews. User with ID:%d", tokenData.Id)
+
+	filmId, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: GetReviews. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	params, err := detailsParams(r, cconstant.FieldsReview, "created_at")
+	if err != nil {
+		log.Printf("Request: GetReviews. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.serviceUC.GetReviews(filmId, params)
+	if err != nil {
+		log.Printf("Request: GetReviews. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      UpdateReview
+// @Description  Change the rating and text of your review
+// @Tags         review
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "review id"
+// @Param        input	body	service.Review  true  "review data, only rating and text are used"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /review/{id} [patch]
+func (s *ServiceHandler) UpdateReview(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data service.Review
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: UpdateReview. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: UpdateReview. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: UpdateReview. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.validateReview(&data); err != nil {
+		log.Printf("Request: UpdateReview. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data.UserId = tokenData.Id
+
+	err = s.serviceUC.UpdateReview(r.Context(), id, &data)
+	if err != nil {
+		log.Printf("Request: UpdateReview. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      DeleteReview
+// @Description  Delete your review; a moderator can delete any review
+// @Tags         review
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "review id"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /review/{id} [delete]
+func (s *ServiceHandler) DeleteReview(rw http.ResponseWriter, r *http.Request) {
+	var resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: DeleteReview. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: DeleteReview. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	// A moderator deletes the review whoever wrote it.
+	userId := tokenData.Id
+	if auth.Can(tokenData.Role, auth.PermReviewModerate) {
+		userId = 0
+	}
+
+	err = s.serviceUC.DeleteReview(r.Context(), id, userId)
+	if err != nil {
+		log.Printf("Request: DeleteReview. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetTrash
+// @Description  List the deleted actors or films, the latest deleted first. Deleted entries are
+// @Description  purged once they have been in the trash longer than the retention period. Admins only.
+// @Tags         trash
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        kind	path  string  true  "actors or films" Enums(actors, films)
+// @Param        limit 			query   int    false "Page size, 20 by default"
+// @Param        order 			query   string false "asc or desc, desc by default"
+// @Param        cursor 		query   string false "next_cursor of the previous page"
+// @Success      200  {object}	service.TrashPage
+// @Failure      400  {object}	error
+// @Failure      403  {object}	error
+// @Failure      500  {object}  error
+// @Router       /trash/{kind} [get]
+func (s *ServiceHandler) GetTrash(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetTrash. User with ID:%d", tokenData.Id)
+
+	params, err := detailsParams(r, nil, "deleted_at")
+	if err != nil {
+		log.Printf("Request: GetTrash. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.serviceUC.GetTrash(mux.Vars(r)["kind"], params)
+	if err != nil {
+		log.Printf("Request: GetTrash. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetRevisions
+// @Description  Revisions of an actor or a film, the latest first. Every change of the fields makes a numbered
+// @Description  revision holding the fields after it; revision 1 is the state before the first change.
+// @Tags         revision
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "actor or film id"
+// @Param        limit 			query   int    false "Page size, 20 by default"
+// @Param        order 			query   string false "asc or desc, desc by default"
+// @Param        cursor 		query   string false "next_cursor of the previous page"
+// @Success      200  {object}	service.RevisionsPage
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /actor/{id}/revisions [get]
+// @Router       /person/{id}/revisions [get]
+// @Router       /film/{id}/revisions [get]
+func (s *ServiceHandler) GetRevisions(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetRevisions. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: GetRevisions. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	params, err := detailsParams(r, nil, "number")
+	if err != nil {
+		log.Printf("Request: GetRevisions. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.serviceUC.GetRevisions(revisionEntity(r), id, params)
+	if err != nil {
+		log.Printf("Request: GetRevisions. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      DiffRevisions
+// @Description  Fields that differ between two revisions of an actor or a film, with their values in both.
+// @Tags         revision
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "actor or film id"
+// @Param        from 			query   int    true  "Revision number"
+// @Param        to 			query   int    true  "Revision number"
+// @Success      200  {object}	service.RevisionDiff
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /actor/{id}/revisions/diff [get]
+// @Router       /person/{id}/revisions/diff [get]
+// @Router       /film/{id}/revisions/diff [get]
+func (s *ServiceHandler) DiffRevisions(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: DiffRevisions. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: DiffRevisions. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	from, errFrom := parseId(r.URL.Query().Get("from"))
+	to, errTo := parseId(r.URL.Query().Get("to"))
+	if errFrom != nil || errTo != nil {
+		log.Printf("Request: DiffRevisions. Error: %s", "bad revision numbers")
+		http.Error(rw, "from and to should be revision numbers", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.serviceUC.DiffRevisions(revisionEntity(r), id, from, to)
+	if err != nil {
+		log.Printf("Request: DiffRevisions. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      RevertRevision
+// @Description  Give an actor or a film the fields of an earlier revision. The revert makes a new revision,
+// @Description  its number is returned as id. 409 when the name is taken by another entry meanwhile.
+// @Tags         revision
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "actor or film id"
+// @Param        number	path  int     true  "revision number"
+// @Param        If-Match	header string  false  "ETag from GET, the revert is made only while it is current"
+// @Success      200  {object}	service.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      403  {object}	error
+// @Failure      404  {object}	error
+// @Failure      409  {object}	error
+// @Failure      412  {object}	error
+// @Failure      500  {object}  error
+// @Router       /actor/{id}/revisions/{number}/revert [post]
+// @Router       /person/{id}/revisions/{number}/revert [post]
+// @Router       /film/{id}/revisions/{number}/revert [post]
+func (s *ServiceHandler) RevertRevision(rw http.ResponseWriter, r *http.Request) {
+	var (
+		resp *service.ResponseModel = &service.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: RevertRevision. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: RevertRevision. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	number, err := parseId(mux.Vars(r)["number"])
+	if err != nil {
+		log.Printf("Request: RevertRevision. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		log.Printf("Request: RevertRevision. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	if revisionEntity(r) == "actor" {
+		resp.Id, err = s.serviceUC.RevertActor(r.Context(), id, number, version)
+	} else {
+		resp.Id, err = s.serviceUC.RevertFilm(r.Context(), id, number, version)
+	}
+	if err != nil {
+		log.Printf("Request: RevertRevision. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetAudit
+// @Description  Lists the changes of the catalogue, the latest first: who made them, in which request and the
+// @Description  fields they changed. Relations are filed under their film. Admins only.
+// @Tags         audit
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        entity 		query   string false "Changed entity" Enums(actor, film, genre, review, actor_film, film_crew, film_genre, trash)
+// @Param        entity_id 		query   int    false "Id of the changed entity, of the film for relations"
+// @Param        user_id 		query   int    false "User who made the change"
+// @Param        operation 		query   string false "Kind of change" Enums(create, update, delete, restore, revert, purge)
+// @Param        from 			query   string false "Changes made at or after, RFC 3339"
+// @Param        to 			query   string false "Changes made before, RFC 3339"
+// @Param        limit 			query   int    false "Page size, 20 by default"
+// @Param        order 			query   string false "asc or desc, desc by default"
+// @Param        cursor 		query   string false "next_cursor of the previous page"
+// @Success      200  {object}	service.AuditPage
+// @Failure      400  {object}	error
+// @Failure      403  {object}	error
+// @Failure      500  {object}  error
+// @Router       /audit [get]
+func (s *ServiceHandler) GetAudit(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetAudit. User with ID:%d", tokenData.Id)
+
+	filter, err := auditFilter(r)
+	if err != nil {
+		log.Printf("Request: GetAudit. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params, err := detailsParams(r, nil, "created_at")
+	if err != nil {
+		log.Printf("Request: GetAudit. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.serviceUC.GetAudit(filter, params)
+	if err != nil {
+		log.Printf("Request: GetAudit. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+//---------------------------------------------------------------------------------------------------------------------
+
+// actorId returns the id of the actor addressed by the request: the {id} path
+// variable when present, otherwise the actor looked up by the {actor_name} variable.
+func (s *ServiceHandler) actorId(r *http.Request) (int, error) {
+	if id, ok := mux.Vars(r)["id"]; ok {
+		return parseId(id)
+	}
+
+	name, err := pathName(r, "actor_name")
+	if err != nil {
+		return 0, err
+	}
+
+	return s.serviceUC.GetActorId(name)
+}
+
+// filmId returns the id of the film addressed by the request: the {id} path
+// variable when present, otherwise the film looked up by the {film_name} variable.
+func (s *ServiceHandler) filmId(r *http.Request) (int, error) {
+	if id, ok := mux.Vars(r)["id"]; ok {
+		return parseId(id)
+	}
+
+	name, err := pathName(r, "film_name")
+	if err != nil {
+		return 0, err
+	}
+
+	return s.serviceUC.GetFilmId(name)
+}
+
+// revisionEntity names the entity of the {entity} path variable; people keep
+// their revisions as actors.
+func revisionEntity(r *http.Request) string {
+	if entity := mux.Vars(r)["entity"]; entity != "person" {
+		return entity
+	}
+
+	return "actor"
+}
+
+// pathName decodes a name passed as the key path variable. The router matches
+// the escaped path, so the variable is still percent-encoded: "%2F" is a slash
+// inside the name and "+" stands for a space, as older clients send it.
+// A literal plus has to be sent as "%2B".
+func pathName(r *http.Request, key string) (string, error) {
+	raw, ok := mux.Vars(r)[key]
+	if !ok {
+		return "", fmt.Errorf("%s is required: %w", key, errBadRequest)
+	}
+
+	name, err := url.PathUnescape(strings.ReplaceAll(raw, "+", " "))
+	if err != nil || !utf8.ValidString(name) {
+		return "", fmt.Errorf("%s should be percent-encoded UTF-8: %w", key, errBadRequest)
+	}
+
+	return norm.NFC.String(name), nil
+}
+
+// detailsParams reads the sort field and page parameters of a list request.
+// The sort field comes from the Sort header or the sort query parameter and
+// falls back to defaultSort when it is not one of fields.
+func detailsParams(r *http.Request, fields []string, defaultSort string) (*service.DetailsParams, error) {
+	var (
+		query  = r.URL.Query()
+		params = &service.DetailsParams{Sort: r.Header.Get("Sort"), Limit: cconstant.DefaultPageLimit}
+		err    error
+	)
+
+	if sort := query.Get("sort"); sort != "" {
+		params.Sort = sort
+	}
+	if idx := slices.IndexFunc(fields, func(c string) bool { return c == params.Sort }); idx == -1 {
+		params.Sort = defaultSort
+	}
+
+	if params.Order = strings.ToLower(query.Get("order")); params.Order != "" && !slices.Contains(cconstant.SortOrders, params.Order) {
+		return nil, fmt.Errorf("order should be 'asc' or 'desc'")
+	}
+
+	if params.Limit, err = limitParam(r); err != nil {
+		return nil, err
+	}
+
+	if params.Cursor, err = service.DecodeCursor(query.Get("cursor")); err != nil {
+		return nil, err
+	}
+	if params.Cursor != nil && (params.Cursor.Sort != params.Sort || params.Cursor.Order != params.Order) {
+		return nil, fmt.Errorf("cursor was issued for sort=%s order=%s", params.Cursor.Sort, params.Cursor.Order)
+	}
+
+	return params, nil
+}
+
+// limitParam reads the limit query parameter, DefaultPageLimit when it is not set.
+func limitParam(r *http.Request) (int, error) {
+	rawLimit := r.URL.Query().Get("limit")
+	if rawLimit == "" {
+		return cconstant.DefaultPageLimit, nil
+	}
+
+	limit, err := strconv.Atoi(rawLimit)
+	if err != nil || limit <= 0 || limit > cconstant.MaxPageLimit {
+		return 0, fmt.Errorf("limit should be [1;%d]", cconstant.MaxPageLimit)
+	}
+
+	return limit, nil
+}
+
+// expandParam reads the comma-separated ?expand= relations, each of which has to be one of allowed.
+func expandParam(r *http.Request, allowed []string) ([]string, error) {
+	raw := r.URL.Query().Get("expand")
+	if raw == "" {
+		return nil, nil
+	}
+
+	expand := strings.Split(raw, ",")
+	for _, e := range expand {
+		if !slices.Contains(allowed, e) {
+			return nil, fmt.Errorf("expand should be one of %s", strings.Join(allowed, ", "))
+		}
+	}
+
+	return expand, nil
+}
+
+// allActorFilms reads every film of the actor for ?expand=films, page by page.
+func (s *ServiceHandler) allActorFilms(id int) ([]service.Film, error) {
+	var (
+		films  = make([]service.Film, 0)
+		cursor *service.Cursor
+	)
+
+	for {
+		page, err := s.serviceUC.GetActorFilms(id, &service.DetailsParams{Sort: "release_date", Limit: cconstant.MaxPageLimit, Cursor: cursor})
+		if err != nil {
+			return nil, err
+		}
+		films = append(films, page.Items...)
+
+		if page.NextCursor == "" {
+			return films, nil
+		}
+		if cursor, err = service.DecodeCursor(page.NextCursor); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// allFilmActors reads the whole cast of the film for ?expand=actors, page by page.
+func (s *ServiceHandler) allFilmActors(id int) ([]service.Actor, error) {
+	var (
+		actors = make([]service.Actor, 0)
+		cursor *service.Cursor
+	)
+
+	for {
+		page, err := s.serviceUC.GetFilmActors(id, &service.DetailsParams{Sort: "actor_name", Limit: cconstant.MaxPageLimit, Cursor: cursor})
+		if err != nil {
+			return nil, err
+		}
+		actors = append(actors, page.Items...)
+
+		if page.NextCursor == "" {
+			return actors, nil
+		}
+		if cursor, err = service.DecodeCursor(page.NextCursor); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// filmFilter reads the film list filters from the query parameters. It returns nil when none is set.
+func filmFilter(r *http.Request) (*service.FilmFilter, error) {
+	var (
+		query  = r.URL.Query()
+		filter = &service.FilmFilter{
+			ReleasedFrom: query.Get("released_from"),
+			ReleasedTo:   query.Get("released_to"),
+			Actors:       query["actor"],
+			Genres:       query["genre"],
+			Keyword:      strings.TrimSpace(query.Get("keyword")),
+		}
+	)
+
+	for _, date := range []string{filter.ReleasedFrom, filter.ReleasedTo} {
+		if _, err := time.Parse(time.DateOnly, date); date != "" && err != nil {
+			return nil, fmt.Errorf("released_from and released_to should be '2000-01-01' format")
+		}
+	}
+	if filter.ReleasedFrom != "" && filter.ReleasedTo != "" && filter.ReleasedFrom > filter.ReleasedTo {
+		return nil, fmt.Errorf("released_from should not be after released_to")
+	}
+
+	for _, bound := range []struct {
+		name string
+		dst  **float32
+	}{{"rating_from", &filter.RatingFrom}, {"rating_to", &filter.RatingTo}} {
+		raw := query.Get(bound.name)
+		if raw == "" {
+			continue
+		}
+		rating, err := strconv.ParseFloat(raw, 32)
+		if err != nil || rating < 0 || rating > 10 {
+			return nil, fmt.Errorf("%s should be [0;10]", bound.name)
+		}
+		value := float32(rating)
+		*bound.dst = &value
+	}
+	if filter.RatingFrom != nil && filter.RatingTo != nil && *filter.RatingFrom > *filter.RatingTo {
+		return nil, fmt.Errorf("rating_from should not be greater than rating_to")
+	}
+
+	for _, raw := range query["actor_id"] {
+		id, err := parseId(raw)
+		if err != nil {
+			return nil, fmt.Errorf("actor_id should be a positive number")
+		}
+		filter.ActorIds = append(filter.ActorIds, id)
+	}
+
+	for _, raw := range query["genre_id"] {
+		id, err := parseId(raw)
+		if err != nil {
+			return nil, fmt.Errorf("genre_id should be a positive number")
+		}
+		filter.GenreIds = append(filter.GenreIds, id)
+	}
+
+	if reflect.ValueOf(*filter).IsZero() {
+		return nil, nil
+	}
+
+	return filter, nil
+}
+
+// auditFilter reads the audit trail filters from the query parameters.
+func auditFilter(r *http.Request) (*service.AuditFilter, error) {
+	var (
+		query  = r.URL.Query()
+		filter = &service.AuditFilter{Entity: query.Get("entity"), Operation: query.Get("operation")}
+	)
+
+	if filter.Entity != "" && !slices.Contains(cconstant.AuditEntities, filter.Entity) {
+		return nil, fmt.Errorf("entity should be one of %s", strings.Join(cconstant.AuditEntities, ", "))
+	}
+	if filter.Operation != "" && !slices.Contains(cconstant.AuditOperations, filter.Operation) {
+		return nil, fmt.Errorf("operation should be one of %s", strings.Join(cconstant.AuditOperations, ", "))
+	}
+
+	for _, id := range []struct {
+		name string
+		dst  *int
+	}{{"entity_id", &filter.EntityId}, {"user_id", &filter.UserId}} {
+		raw := query.Get(id.name)
+		if raw == "" {
+			continue
+		}
+		value, err := parseId(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s should be a positive number", id.name)
+		}
+		*id.dst = value
+	}
+
+	for _, bound := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		raw := query.Get(bound.name)
+		if raw == "" {
+			continue
+		}
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s should be '2006-01-02T15:04:05Z' format", bound.name)
+		}
+		*bound.dst = &value
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, fmt.Errorf("from should not be after to")
+	}
+
+	return filter, nil
+}
+
+// searchParams reads a search request. The query comes from the q parameter or,
+// for the older /search/{name} routes, from the nameKey path variable.
+func searchParams(r *http.Request, nameKey string) (*service.SearchParams, error) {
+	var (
+		query  = r.URL.Query()
+		params = &service.SearchParams{Query: strings.TrimSpace(query.Get("q")), Limit: cconstant.DefaultPageLimit}
+		err    error
+	)
+
+	if _, ok := mux.Vars(r)[nameKey]; ok && params.Query == "" {
+		pattern, err := pathName(r, nameKey)
+		if err != nil {
+			return nil, err
+		}
+		params.Query = strings.TrimSpace(pattern)
+	}
+	if params.Query == "" || utf8.RuneCountInString(params.Query) > cconstant.MaxSearchQueryLen {
+		return nil, fmt.Errorf("size q should be [1;%d]", cconstant.MaxSearchQueryLen)
+	}
+
+	if params.Mode = strings.ToLower(query.Get("mode")); params.Mode != "" && !slices.Contains(cconstant.SearchModes, params.Mode) {
+		return nil, fmt.Errorf("mode should be one of %s", strings.Join(cconstant.SearchModes, ", "))
+	}
+
+	if rawThreshold := query.Get("threshold"); rawThreshold != "" {
+		threshold, err := strconv.ParseFloat(rawThreshold, 32)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			return nil, fmt.Errorf("threshold should be (0;1]")
+		}
+		params.Threshold = float32(threshold)
+	}
+
+	if params.Language = strings.ToLower(query.Get("lang")); params.Language != "" && !slices.Contains(cconstant.SearchLanguages, params.Language) {
+		return nil, fmt.Errorf("lang should be one of %s", strings.Join(cconstant.SearchLanguages, ", "))
+	}
+
+	if params.Limit, err = limitParam(r); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func parseId(raw string) (int, error) {
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("id should be a positive number: %w", errBadRequest)
+	}
+
+	return id, nil
+}
+
+// etag tags a representation of an actor or a film with its version, which
+// If-Match is checked against, and a hash of the body, so that If-None-Match
+// also notices changes of the relations and expansions.
+func etag(version int, body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`"%d-%x"`, version, sum[:8])
+}
+
+// noneMatch tells whether the If-None-Match header lists tag or is "*".
+// The comparison is weak, as RFC 9110 asks for.
+func noneMatch(r *http.Request, tag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ifMatch reads the version a change is based on from the If-Match header,
+// 0 when there is none or it is "*". A weak tag never matches a strong
+// comparison, so it fails the precondition.
+func ifMatch(r *http.Request) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	if strings.HasPrefix(header, "W/") {
+		return 0, fmt.Errorf("If-Match should be a strong ETag: %w", service.ErrVersionMismatch)
+	}
+
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' || strings.Count(header, `"`) != 2 {
+		return 0, fmt.Errorf("If-Match should be one ETag: %w", errBadRequest)
+	}
+
+	prefix, _, _ := strings.Cut(header[1:len(header)-1], "-")
+	version, err := strconv.Atoi(prefix)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("If-Match should be an ETag of this API: %w", errBadRequest)
+	}
+
+	return version, nil
+}
+
+// errorStatus picks the response code for an error returned by the usecase.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, errBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrAlreadyExists), errors.Is(err, service.ErrAmbiguousName):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrTimeout):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, service.ErrVersionMismatch):
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// validateActor checks a new actor with the rules shared with the bulk import.
+func (s *ServiceHandler) validateActor(data *service.Actor) error {
+	return service.ValidateActor(data)
+}
+
+// validateFilm checks a new film with the rules shared with the bulk import.
+func (s *ServiceHandler) validateFilm(data *service.Film) error {
+	return service.ValidateFilm(data)
+}
+
+func (s *ServiceHandler) validateCredit(data *service.UpdateCreditParams) error {
+	if (data.ActorId == 0 && data.Actor == "") || (data.FilmId == 0 && data.Film == "") {
+		return fmt.Errorf("actor and film should be set by id or name")
+	}
+	if data.Character == nil && data.Type == nil && data.Order == nil {
+		return fmt.Errorf("character, type or order should be set")
+	}
+	if data.Character != nil {
+		*data.Character = service.NormalizeName(*data.Character)
+		if err := service.ValidName(*data.Character, 150); *data.Character != "" && err != nil {
+			return fmt.Errorf("character: %w", err)
+		}
+	}
+	if data.Type != nil && !slices.Contains(cconstant.CreditTypes, *data.Type) {
+		return fmt.Errorf("type should be one of %s", strings.Join(cconstant.CreditTypes, ", "))
+	}
+	if data.Order != nil && *data.Order <= 0 {
+		return fmt.Errorf("order should be a positive number")
+	}
+
+	return nil
+}
+
+func (s *ServiceHandler) validateCrew(data *service.CrewParams) error {
+	if (data.PersonId == 0 && data.Person == "") || (data.FilmId == 0 && data.Film == "") {
+		return fmt.Errorf("person and film should be set by id or name")
+	}
+	if !slices.Contains(cconstant.CrewJobs, data.Job) {
+		return fmt.Errorf("job should be one of %s", strings.Join(cconstant.CrewJobs, ", "))
+	}
+
+	return nil
+}
+
+// validateReview checks the rating and text of a review; the text is trimmed
+// and may span several lines.
+func (s *ServiceHandler) validateReview(data *service.Review) error {
+	if data.Rating < cconstant.MinReviewRating || data.Rating > cconstant.MaxReviewRating {
+		return fmt.Errorf("rating should be [%d;%d]", cconstant.MinReviewRating, cconstant.MaxReviewRating)
+	}
+	data.Text = strings.TrimSpace(data.Text)
+	if !utf8.ValidString(data.Text) || utf8.RuneCountInString(data.Text) > cconstant.MaxReviewLen {
+		return fmt.Errorf("text should be at most %d characters of UTF-8", cconstant.MaxReviewLen)
+	}
+
+	return nil
+}
+
+// validateGenre checks a genre name; it is normalized like in validateActor.
+func (s *ServiceHandler) validateGenre(data *service.Genre) error {
+	data.Name = service.NormalizeName(data.Name)
+
+	return service.ValidName(data.Name, 50)
 }
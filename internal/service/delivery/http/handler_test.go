@@ -6,14 +6,19 @@ import (
 	"encoding/json"
 	"film_library/internal/auth"
 	mock_auth "film_library/internal/auth/mocks"
+	"film_library/internal/cconstant"
 	"film_library/internal/service"
 	mock_service "film_library/internal/service/mocks"
 	"fmt"
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidateActor(t *testing.T) {
@@ -121,7 +126,7 @@ func TestValidateFilm(t *testing.T) {
 
 func TestCreateActor(t *testing.T) {
 	type mockBehavior func(s *mock_service.MockUsecase, user service.Actor)
-	var resp *auth.ResponseModel = &auth.ResponseModel{Status: "OK"}
+	var resp *service.ResponseModel = &service.ResponseModel{Status: "OK", Id: 1}
 	ans, _ := json.Marshal(resp)
 
 	testTable := []struct {
@@ -141,7 +146,7 @@ func TestCreateActor(t *testing.T) {
 				BDate: "1999-10-10",
 			},
 			mockBehavior: func(s *mock_service.MockUsecase, actor service.Actor) {
-				s.EXPECT().CreateActor(&actor).Return(nil).Times(1)
+				s.EXPECT().CreateActor(gomock.Any(), &actor).Return(1, nil).Times(1)
 			},
 			expectedStatusCode:  http.StatusOK,
 			expectedRequestBody: ans,
@@ -155,7 +160,7 @@ func TestCreateActor(t *testing.T) {
 				BDate: "1999-10-10",
 			},
 			mockBehavior: func(s *mock_service.MockUsecase, actor service.Actor) {
-				s.EXPECT().CreateActor(&actor).Return(fmt.Errorf("error")).Times(1)
+				s.EXPECT().CreateActor(gomock.Any(), &actor).Return(0, fmt.Errorf("error")).Times(1)
 			},
 			expectedStatusCode:  http.StatusInternalServerError,
 			expectedRequestBody: []byte("error\n"),
@@ -211,7 +216,8 @@ func TestGetActor(t *testing.T) {
 				BDate: "1999-10-10",
 			},
 			mockBehavior: func(s *mock_service.MockUsecase, name string, actor service.Actor) {
-				s.EXPECT().GetActor(name).Return(&actor, nil).Times(1)
+				s.EXPECT().GetActorId(name).Return(1, nil).Times(1)
+				s.EXPECT().GetActor(1).Return(&actor, nil).Times(1)
 			},
 			expectedStatusCode:  http.StatusOK,
 			expectedRequestBody: ans,
@@ -221,7 +227,8 @@ func TestGetActor(t *testing.T) {
 			inputBody: ``,
 			inputUser: service.Actor{},
 			mockBehavior: func(s *mock_service.MockUsecase, name string, actor service.Actor) {
-				s.EXPECT().GetActor(name).Return(nil, fmt.Errorf("error")).Times(1)
+				s.EXPECT().GetActorId(name).Return(1, nil).Times(1)
+				s.EXPECT().GetActor(1).Return(nil, fmt.Errorf("error")).Times(1)
 			},
 			expectedStatusCode:  http.StatusInternalServerError,
 			expectedRequestBody: []byte("error\n"),
@@ -242,7 +249,7 @@ func TestGetActor(t *testing.T) {
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/actor/get/Sasha", bytes.NewBufferString(""))
 			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 1})
-			r = r.WithContext(ctx)
+			r = mux.SetURLVars(r.WithContext(ctx), map[string]string{"actor_name": "Sasha"})
 			handler.GetActor(w, r)
 
 			require.Equal(t, testCase.expectedStatusCode, w.Code)
@@ -253,13 +260,13 @@ func TestGetActor(t *testing.T) {
 
 func TestGetActors(t *testing.T) {
 	type mockBehavior func(s *mock_service.MockUsecase, details service.DetailsParams, actor []service.Actor)
-	var resp []service.Actor = []service.Actor{{
+	var resp *service.ActorsPage = &service.ActorsPage{Items: []service.Actor{{
 		Name:  "Sasha",
 		Sex:   "m",
 		BDate: "1999-10-10"},
-	}
+	}, Total: 1}
 	ans, _ := json.Marshal(resp)
-	var details service.DetailsParams = service.DetailsParams{Sort: "actor_name"}
+	var details service.DetailsParams = service.DetailsParams{Sort: "actor_name", Limit: 20}
 
 	testTable := []struct {
 		name                string
@@ -278,7 +285,7 @@ func TestGetActors(t *testing.T) {
 				BDate: "1999-10-10",
 			},
 			mockBehavior: func(s *mock_service.MockUsecase, details service.DetailsParams, actors []service.Actor) {
-				s.EXPECT().GetActors(&details).Return(actors, nil).Times(1)
+				s.EXPECT().GetActors(&details).Return(&service.ActorsPage{Items: actors, Total: 1}, nil).Times(1)
 			},
 			expectedStatusCode:  http.StatusOK,
 			expectedRequestBody: ans,
@@ -288,7 +295,7 @@ func TestGetActors(t *testing.T) {
 			inputBody: ``,
 			inputUser: service.Actor{},
 			mockBehavior: func(s *mock_service.MockUsecase, details service.DetailsParams, actors []service.Actor) {
-				s.EXPECT().GetActors(&details).Return(actors, fmt.Errorf("error")).Times(1)
+				s.EXPECT().GetActors(&details).Return(nil, fmt.Errorf("error")).Times(1)
 			},
 			expectedStatusCode:  http.StatusInternalServerError,
 			expectedRequestBody: []byte("error\n"),
@@ -318,15 +325,170 @@ func TestGetActors(t *testing.T) {
 	}
 }
 
+func TestDetailsParams(t *testing.T) {
+	cursor := service.EncodeCursor(service.Cursor{Sort: "bdate", Key: "1999-10-10", Id: 5})
+
+	cases := []struct {
+		name   string
+		target string
+		header string
+		out    *service.DetailsParams
+		expErr string
+	}{
+		{
+			name:   "Default",
+			target: "/actor/get_all",
+			out:    &service.DetailsParams{Sort: "actor_name", Limit: 20},
+		},
+		{
+			name:   "SortHeader",
+			target: "/actor/get_all?limit=5",
+			header: "sex",
+			out:    &service.DetailsParams{Sort: "sex", Limit: 5},
+		},
+		{
+			name:   "UnknownSort",
+			target: "/actor/get_all?sort=password",
+			out:    &service.DetailsParams{Sort: "actor_name", Limit: 20},
+		},
+		{
+			name:   "Cursor",
+			target: "/actor/get_all?sort=bdate&cursor=" + cursor,
+			out:    &service.DetailsParams{Sort: "bdate", Limit: 20, Cursor: &service.Cursor{Sort: "bdate", Key: "1999-10-10", Id: 5}},
+		},
+		{
+			name:   "CursorOtherSort",
+			target: "/actor/get_all?cursor=" + cursor,
+			expErr: "cursor was issued for sort=bdate order=",
+		},
+		{
+			name:   "CursorOtherOrder",
+			target: "/actor/get_all?sort=bdate&order=desc&cursor=" + cursor,
+			expErr: "cursor was issued for sort=bdate order=",
+		},
+		{
+			name:   "Order",
+			target: "/actor/get_all?sort=sex&order=DESC",
+			out:    &service.DetailsParams{Sort: "sex", Order: "desc", Limit: 20},
+		},
+		{
+			name:   "BadOrder",
+			target: "/actor/get_all?order=up",
+			expErr: "order should be 'asc' or 'desc'",
+		},
+		{
+			name:   "BadCursor",
+			target: "/actor/get_all?cursor=abc",
+			expErr: "invalid cursor",
+		},
+		{
+			name:   "BigLimit",
+			target: "/actor/get_all?limit=1000",
+			expErr: "limit should be [1;100]",
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tCase.target, nil)
+			r.Header.Set("Sort", tCase.header)
+
+			params, err := detailsParams(r, cconstant.FieldsActor, "actor_name")
+			if tCase.expErr != "" {
+				require.EqualError(t, err, tCase.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tCase.out, params)
+		})
+	}
+}
+
+func TestFilmFilter(t *testing.T) {
+	var (
+		from float32 = 5
+		to   float32 = 8.5
+	)
+
+	cases := []struct {
+		name   string
+		target string
+		out    *service.FilmFilter
+		expErr string
+	}{
+		{
+			name:   "NoFilter",
+			target: "/film/get_all?sort=rating",
+		},
+		{
+			name:   "All",
+			target: "/film/get_all?released_from=1990-01-01&released_to=2000-12-31&rating_from=5&rating_to=8.5&actor_id=3&actor_id=4&actor=Tom+Hanks&genre_id=2&genre=Drama&keyword=%20war%20",
+			out: &service.FilmFilter{
+				ReleasedFrom: "1990-01-01",
+				ReleasedTo:   "2000-12-31",
+				RatingFrom:   &from,
+				RatingTo:     &to,
+				ActorIds:     []int{3, 4},
+				Actors:       []string{"Tom Hanks"},
+				GenreIds:     []int{2},
+				Genres:       []string{"Drama"},
+				Keyword:      "war",
+			},
+		},
+		{
+			name:   "BadDate",
+			target: "/film/get_all?released_from=01-01-1990",
+			expErr: "released_from and released_to should be '2000-01-01' format",
+		},
+		{
+			name:   "DateRange",
+			target: "/film/get_all?released_from=2000-01-01&released_to=1990-01-01",
+			expErr: "released_from should not be after released_to",
+		},
+		{
+			name:   "BadRating",
+			target: "/film/get_all?rating_to=11",
+			expErr: "rating_to should be [0;10]",
+		},
+		{
+			name:   "RatingRange",
+			target: "/film/get_all?rating_from=9&rating_to=1",
+			expErr: "rating_from should not be greater than rating_to",
+		},
+		{
+			name:   "BadGenreId",
+			target: "/film/get_all?genre_id=0",
+			expErr: "genre_id should be a positive number",
+		},
+		{
+			name:   "BadActorId",
+			target: "/film/get_all?actor_id=abc",
+			expErr: "actor_id should be a positive number",
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			filter, err := filmFilter(httptest.NewRequest("GET", tCase.target, nil))
+			if tCase.expErr != "" {
+				require.EqualError(t, err, tCase.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tCase.out, filter)
+		})
+	}
+}
+
 func TestUpdateActor(t *testing.T) {
-	type mockBehavior func(s *mock_service.MockUsecase, name string, user service.Actor)
+	type mockBehavior func(s *mock_service.MockUsecase, name string, user service.ActorPatch)
 	var resp *auth.ResponseModel = &auth.ResponseModel{Status: "OK"}
 	ans, _ := json.Marshal(resp)
 
 	testTable := []struct {
 		name                string
 		inputBody           string
-		inputUser           service.Actor
+		inputUser           service.ActorPatch
 		mockBehavior        mockBehavior
 		expectedStatusCode  int
 		expectedRequestBody []byte
@@ -334,13 +496,14 @@ func TestUpdateActor(t *testing.T) {
 		{
 			name:      "OK",
 			inputBody: `{"name":"Sasha", "sex":"m", "bdate":"1999-10-10"}`,
-			inputUser: service.Actor{
-				Name:  "Sasha",
-				Sex:   "m",
-				BDate: "1999-10-10",
+			inputUser: service.ActorPatch{
+				Name:  service.Patched("Sasha"),
+				Sex:   service.Patched("m"),
+				BDate: service.Patched("1999-10-10"),
 			},
-			mockBehavior: func(s *mock_service.MockUsecase, name string, actor service.Actor) {
-				s.EXPECT().UpdateActor(name, &actor).Return(nil).Times(1)
+			mockBehavior: func(s *mock_service.MockUsecase, name string, actor service.ActorPatch) {
+				s.EXPECT().GetActorId(name).Return(1, nil).Times(1)
+				s.EXPECT().UpdateActor(gomock.Any(), 1, &actor).Return(nil).Times(1)
 			},
 			expectedStatusCode:  http.StatusOK,
 			expectedRequestBody: ans,
@@ -348,13 +511,14 @@ func TestUpdateActor(t *testing.T) {
 		{
 			name:      "Error",
 			inputBody: `{"Name":"Sasha", "Sex":"m", "BDate":"1999-10-10"}`,
-			inputUser: service.Actor{
-				Name:  "Sasha",
-				Sex:   "m",
-				BDate: "1999-10-10",
+			inputUser: service.ActorPatch{
+				Name:  service.Patched("Sasha"),
+				Sex:   service.Patched("m"),
+				BDate: service.Patched("1999-10-10"),
 			},
-			mockBehavior: func(s *mock_service.MockUsecase, name string, actor service.Actor) {
-				s.EXPECT().UpdateActor(name, &actor).Return(fmt.Errorf("error")).Times(1)
+			mockBehavior: func(s *mock_service.MockUsecase, name string, actor service.ActorPatch) {
+				s.EXPECT().GetActorId(name).Return(1, nil).Times(1)
+				s.EXPECT().UpdateActor(gomock.Any(), 1, &actor).Return(fmt.Errorf("error")).Times(1)
 			},
 			expectedStatusCode:  http.StatusInternalServerError,
 			expectedRequestBody: []byte("error\n"),
@@ -375,7 +539,7 @@ func TestUpdateActor(t *testing.T) {
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("POST", "/api/actor/update/Sasha", bytes.NewBufferString(testCase.inputBody))
 			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 1})
-			r = r.WithContext(ctx)
+			r = mux.SetURLVars(r.WithContext(ctx), map[string]string{"actor_name": "Sasha"})
 			handler.UpdateActor(w, r)
 
 			require.Equal(t, testCase.expectedStatusCode, w.Code)
@@ -406,7 +570,8 @@ func TestDeleteActor(t *testing.T) {
 				BDate: "1999-10-10",
 			},
 			mockBehavior: func(s *mock_service.MockUsecase, name string) {
-				s.EXPECT().DeleteActor(name).Return(nil).Times(1)
+				s.EXPECT().GetActorId(name).Return(1, nil).Times(1)
+				s.EXPECT().DeleteActor(gomock.Any(), 1, 1, 0).Return(nil).Times(1)
 			},
 			expectedStatusCode:  http.StatusOK,
 			expectedRequestBody: ans,
@@ -416,7 +581,8 @@ func TestDeleteActor(t *testing.T) {
 			inputBody: ``,
 			inputUser: service.Actor{},
 			mockBehavior: func(s *mock_service.MockUsecase, name string) {
-				s.EXPECT().DeleteActor(name).Return(fmt.Errorf("error")).Times(1)
+				s.EXPECT().GetActorId(name).Return(1, nil).Times(1)
+				s.EXPECT().DeleteActor(gomock.Any(), 1, 1, 0).Return(fmt.Errorf("error")).Times(1)
 			},
 			expectedStatusCode:  http.StatusInternalServerError,
 			expectedRequestBody: []byte("error\n"),
@@ -437,7 +603,7 @@ func TestDeleteActor(t *testing.T) {
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/actor/delete/Sasha", bytes.NewBufferString(""))
 			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 1})
-			r = r.WithContext(ctx)
+			r = mux.SetURLVars(r.WithContext(ctx), map[string]string{"actor_name": "Sasha"})
 			handler.DeleteActor(w, r)
 
 			require.Equal(t, testCase.expectedStatusCode, w.Code)
@@ -447,38 +613,75 @@ func TestDeleteActor(t *testing.T) {
 }
 
 func TestSearchActor(t *testing.T) {
-	type mockBehavior func(s *mock_service.MockUsecase, pattern string, actor []string)
-	var resp []string = []string{"Sasha"}
+	type mockBehavior func(s *mock_service.MockUsecase, params *service.SearchParams)
+	var resp = &service.ActorSearchResult{Items: []service.ActorHit{{
+		Actor:    service.Actor{Id: 1, Name: "Sasha Petrov", Sex: "m", BDate: "1999-10-10"},
+		Rank:     0.6,
+		Headline: "<b>Sasha</b> Petrov",
+	}}}
 	ans, _ := json.Marshal(resp)
 
 	testTable := []struct {
 		name                string
-		inputBody           string
-		inputUser           service.Actor
+		target              string
 		mockBehavior        mockBehavior
 		expectedStatusCode  int
 		expectedRequestBody []byte
 	}{
 		{
-			name:      "OK",
-			inputBody: `{"Name":"Sasha", "Sex":"m", "BDate":"1999-10-10"}`,
-			inputUser: service.Actor{
-				Name:  "Sasha",
-				Sex:   "m",
-				BDate: "1999-10-10",
+			name:   "OK",
+			target: "/actor/search?q=sasha",
+			mockBehavior: func(s *mock_service.MockUsecase, params *service.SearchParams) {
+				s.EXPECT().SearchActor(params).Return(resp, nil).Times(1)
 			},
-			mockBehavior: func(s *mock_service.MockUsecase, pattern string, actors []string) {
-				s.EXPECT().SearchActor(pattern).Return(actors, nil).Times(1)
+			expectedStatusCode:  http.StatusOK,
+			expectedRequestBody: ans,
+		},
+		{
+			name:   "PathPattern",
+			target: "/actor/search/sasha",
+			mockBehavior: func(s *mock_service.MockUsecase, params *service.SearchParams) {
+				s.EXPECT().SearchActor(params).Return(resp, nil).Times(1)
 			},
 			expectedStatusCode:  http.StatusOK,
 			expectedRequestBody: ans,
 		},
 		{
-			name:      "Error",
-			inputBody: ``,
-			inputUser: service.Actor{},
-			mockBehavior: func(s *mock_service.MockUsecase, pattern string, actors []string) {
-				s.EXPECT().SearchActor(pattern).Return(actors, fmt.Errorf("error")).Times(1)
+			name:   "Fuzzy",
+			target: "/actor/search?q=sasha&mode=fuzzy&threshold=0.5",
+			mockBehavior: func(s *mock_service.MockUsecase, params *service.SearchParams) {
+				params.Mode, params.Threshold = "fuzzy", 0.5
+				s.EXPECT().SearchActor(params).Return(resp, nil).Times(1)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedRequestBody: ans,
+		},
+		{
+			name:                "BadMode",
+			target:              "/actor/search?q=sasha&mode=regex",
+			mockBehavior:        func(s *mock_service.MockUsecase, params *service.SearchParams) {},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedRequestBody: []byte("mode should be one of fts, fuzzy\n"),
+		},
+		{
+			name:                "BadThreshold",
+			target:              "/actor/search?q=sasha&mode=fuzzy&threshold=1.5",
+			mockBehavior:        func(s *mock_service.MockUsecase, params *service.SearchParams) {},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedRequestBody: []byte("threshold should be (0;1]\n"),
+		},
+		{
+			name:                "NoQuery",
+			target:              "/actor/search",
+			mockBehavior:        func(s *mock_service.MockUsecase, params *service.SearchParams) {},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedRequestBody: []byte("size q should be [1;256]\n"),
+		},
+		{
+			name:   "Error",
+			target: "/actor/search?q=sasha",
+			mockBehavior: func(s *mock_service.MockUsecase, params *service.SearchParams) {
+				s.EXPECT().SearchActor(params).Return(nil, fmt.Errorf("error")).Times(1)
 			},
 			expectedStatusCode:  http.StatusInternalServerError,
 			expectedRequestBody: []byte("error\n"),
@@ -492,15 +695,19 @@ func TestSearchActor(t *testing.T) {
 
 			mockService := mock_service.NewMockUsecase(c)
 			mockAuth := mock_auth.NewMockUsecase(c)
-			testCase.mockBehavior(mockService, "asha", []string{testCase.inputUser.Name})
+			testCase.mockBehavior(mockService, &service.SearchParams{Query: "sasha", Limit: 20})
 
 			handler := NewServiceHandler(mockService, mockAuth)
 
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest("GET", "/actor/search/asha", bytes.NewBufferString(""))
+			r := httptest.NewRequest("GET", testCase.target, nil)
 			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 1})
 			r = r.WithContext(ctx)
-			handler.SearchActor(w, r)
+
+			rtr := mux.NewRouter().UseEncodedPath()
+			rtr.HandleFunc("/actor/search", handler.SearchActor)
+			rtr.HandleFunc("/actor/search/{actor_name}", handler.SearchActor)
+			rtr.ServeHTTP(w, r)
 
 			require.Equal(t, testCase.expectedStatusCode, w.Code)
 			require.Equal(t, testCase.expectedRequestBody, w.Body.Bytes())
@@ -508,9 +715,78 @@ func TestSearchActor(t *testing.T) {
 	}
 }
 
+func TestGetActorPath(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
+
+	testTable := []struct {
+		name               string
+		path               string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: "OK",
+			path: "/actor/path?from=1&to=2&max_depth=3",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetActorPath(gomock.Any(), &service.PathParams{From: 1, To: 2, MaxDepth: 3}).Return(&service.ActorPath{Degrees: 1, Chain: []service.PathLink{
+					{ActorId: 1, Actor: "Tom Hanks", FilmId: 5, Film: "Forrest Gump"},
+					{ActorId: 2, Actor: "Robin Wright"},
+				}}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"degrees":1,"chain":[{"actor_id":1,"actor":"Tom Hanks","film_id":5,"film":"Forrest Gump"},{"actor_id":2,"actor":"Robin Wright"}]}`,
+		},
+		{
+			name:               "NoTo",
+			path:               "/actor/path?from=1",
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "from and to should be actor ids\n",
+		},
+		{
+			name:               "TooDeep",
+			path:               "/actor/path?from=1&to=2&max_depth=7",
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "max_depth should be [1;6]\n",
+		},
+		{
+			name: "Timeout",
+			path: "/actor/path?from=1&to=2",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetActorPath(gomock.Any(), &service.PathParams{From: 1, To: 2}).Return(nil, fmt.Errorf("path search stopped after 5s: %w", service.ErrTimeout)).Times(1)
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       "path search stopped after 5s: took too long\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
+
+			handler := NewServiceHandler(mockService, mockAuth)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1})
+			handler.GetActorPath(w, r.WithContext(ctx))
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
+
 func TestCreateFilm(t *testing.T) {
 	type mockBehavior func(s *mock_service.MockUsecase, film service.Film)
-	var resp *auth.ResponseModel = &auth.ResponseModel{Status: "OK"}
+	var resp *service.ResponseModel = &service.ResponseModel{Status: "OK", Id: 1}
 	ans, _ := json.Marshal(resp)
 
 	testTable := []struct {
@@ -531,7 +807,7 @@ func TestCreateFilm(t *testing.T) {
 				Desc:   "nice film",
 			},
 			mockBehavior: func(s *mock_service.MockUsecase, film service.Film) {
-				s.EXPECT().CreateFilm(&film).Return(nil).Times(1)
+				s.EXPECT().CreateFilm(gomock.Any(), &film).Return(1, nil).Times(1)
 			},
 			expectedStatusCode:  http.StatusOK,
 			expectedRequestBody: ans,
@@ -546,7 +822,7 @@ func TestCreateFilm(t *testing.T) {
 				Desc:   "nice film",
 			},
 			mockBehavior: func(s *mock_service.MockUsecase, film service.Film) {
-				s.EXPECT().CreateFilm(&film).Return(fmt.Errorf("error")).Times(1)
+				s.EXPECT().CreateFilm(gomock.Any(), &film).Return(0, fmt.Errorf("error")).Times(1)
 			},
 			expectedStatusCode:  http.StatusInternalServerError,
 			expectedRequestBody: []byte("error\n"),
@@ -604,7 +880,8 @@ func TestGetFilm(t *testing.T) {
 				Desc:   "nice film",
 			},
 			mockBehavior: func(s *mock_service.MockUsecase, name string, film service.Film) {
-				s.EXPECT().GetFilm(name).Return(&film, nil).Times(1)
+				s.EXPECT().GetFilmId(name).Return(1, nil).Times(1)
+				s.EXPECT().GetFilm(1).Return(&film, nil).Times(1)
 			},
 			expectedStatusCode:  http.StatusOK,
 			expectedRequestBody: ans,
@@ -614,7 +891,8 @@ func TestGetFilm(t *testing.T) {
 			inputBody: ``,
 			inputUser: service.Film{},
 			mockBehavior: func(s *mock_service.MockUsecase, name string, actor service.Film) {
-				s.EXPECT().GetFilm(name).Return(nil, fmt.Errorf("error")).Times(1)
+				s.EXPECT().GetFilmId(name).Return(1, nil).Times(1)
+				s.EXPECT().GetFilm(1).Return(nil, fmt.Errorf("error")).Times(1)
 			},
 			expectedStatusCode:  http.StatusInternalServerError,
 			expectedRequestBody: []byte("error\n"),
@@ -635,7 +913,7 @@ func TestGetFilm(t *testing.T) {
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/film/get/Sasha", bytes.NewBufferString(""))
 			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 1})
-			r = r.WithContext(ctx)
+			r = mux.SetURLVars(r.WithContext(ctx), map[string]string{"film_name": "Sasha"})
 			handler.GetFilm(w, r)
 
 			require.Equal(t, testCase.expectedStatusCode, w.Code)
@@ -644,16 +922,174 @@ func TestGetFilm(t *testing.T) {
 	}
 }
 
+func TestConditionalRequests(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
+
+	film := service.Film{Id: 7, Name: "Hamlet", RDate: "1996-12-25", Rating: 7.7, Version: 3}
+	body, _ := json.Marshal(film)
+	tag := etag(3, body)
+
+	testTable := []struct {
+		name               string
+		method             string
+		path               string
+		header             string
+		value              string
+		body               string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedETag       string
+	}{
+		{
+			name:   "Get",
+			method: http.MethodGet,
+			path:   "/film/7",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetFilm(7).Return(&film, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedETag:       tag,
+		},
+		{
+			name:   "NotModified",
+			method: http.MethodGet,
+			path:   "/film/7",
+			header: "If-None-Match",
+			value:  `"2-00", W/` + tag,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetFilm(7).Return(&film, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusNotModified,
+			expectedETag:       tag,
+		},
+		{
+			name:   "Modified",
+			method: http.MethodGet,
+			path:   "/film/7",
+			header: "If-None-Match",
+			value:  `"2-00"`,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetFilm(7).Return(&film, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedETag:       tag,
+		},
+		{
+			name:   "UpdateCurrent",
+			method: http.MethodPatch,
+			path:   "/film/7",
+			header: "If-Match",
+			value:  tag,
+			body:   `{"rating": 8.1}`,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().UpdateFilm(gomock.Any(), 7, &service.FilmPatch{Rating: service.Patched[float32](8.1), Version: 3}).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:   "UpdateStale",
+			method: http.MethodPatch,
+			path:   "/film/7",
+			header: "If-Match",
+			value:  `"2-00"`,
+			body:   `{"rating": 8.1}`,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().UpdateFilm(gomock.Any(), 7, &service.FilmPatch{Rating: service.Patched[float32](8.1), Version: 2}).
+					Return(fmt.Errorf("film is no longer version 2: %w", service.ErrVersionMismatch)).Times(1)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:               "UpdateWeak",
+			method:             http.MethodPatch,
+			path:               "/film/7",
+			header:             "If-Match",
+			value:              "W/" + tag,
+			body:               `{"rating": 8.1}`,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:               "UpdateMalformed",
+			method:             http.MethodPatch,
+			path:               "/film/7",
+			header:             "If-Match",
+			value:              `"3-00", "4-00"`,
+			body:               `{"rating": 8.1}`,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "UpdateAny",
+			method: http.MethodPatch,
+			path:   "/film/7",
+			header: "If-Match",
+			value:  "*",
+			body:   `{"rating": 8.1, "version": 2}`,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().UpdateFilm(gomock.Any(), 7, &service.FilmPatch{Rating: service.Patched[float32](8.1)}).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:   "DeleteCurrent",
+			method: http.MethodDelete,
+			path:   "/actor/5",
+			header: "If-Match",
+			value:  `"4-1a2b"`,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().DeleteActor(gomock.Any(), 5, 1, 4).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
+
+			handler := NewServiceHandler(mockService, mockAuth)
+			rtr := mux.NewRouter()
+			rtr.HandleFunc("/film/{id:[0-9]+}", handler.GetFilm).Methods(http.MethodGet)
+			rtr.HandleFunc("/film/{id:[0-9]+}", handler.UpdateFilm).Methods(http.MethodPatch)
+			rtr.HandleFunc("/actor/{id:[0-9]+}", handler.DeleteActor).Methods(http.MethodDelete)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(testCase.method, testCase.path, bytes.NewBufferString(testCase.body))
+			if testCase.header != "" {
+				r.Header.Set(testCase.header, testCase.value)
+			}
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 1})
+			rtr.ServeHTTP(w, r.WithContext(ctx))
+
+			// Result holds the headers as they were when the status was written.
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedETag, w.Result().Header.Get("ETag"))
+			if w.Code == http.StatusOK {
+				require.Equal(t, "application/json", w.Result().Header.Get("Content-Type"))
+			}
+			if w.Code == http.StatusNotModified {
+				require.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
 func TestGetFilms(t *testing.T) {
 	type mockBehavior func(s *mock_service.MockUsecase, details service.DetailsParams, actor []service.Film)
-	var resp []service.Film = []service.Film{{
+	var resp *service.FilmsPage = &service.FilmsPage{Items: []service.Film{{
 		Name:   "Sasha",
 		Rating: 5.5,
 		RDate:  "1999-10-10",
 		Desc:   "nice film"},
-	}
+	}, Total: 1}
 	ans, _ := json.Marshal(resp)
-	var details service.DetailsParams = service.DetailsParams{Sort: "rating"}
+	var details service.DetailsParams = service.DetailsParams{Sort: "rating", Limit: 20}
 
 	testTable := []struct {
 		name                string
@@ -673,7 +1109,7 @@ func TestGetFilms(t *testing.T) {
 				Desc:   "nice film",
 			},
 			mockBehavior: func(s *mock_service.MockUsecase, details service.DetailsParams, actors []service.Film) {
-				s.EXPECT().GetFilms(&details).Return(actors, nil).Times(1)
+				s.EXPECT().GetFilms(&details).Return(&service.FilmsPage{Items: actors, Total: 1}, nil).Times(1)
 			},
 			expectedStatusCode:  http.StatusOK,
 			expectedRequestBody: ans,
@@ -683,7 +1119,7 @@ func TestGetFilms(t *testing.T) {
 			inputBody: ``,
 			inputUser: service.Film{},
 			mockBehavior: func(s *mock_service.MockUsecase, details service.DetailsParams, actors []service.Film) {
-				s.EXPECT().GetFilms(&details).Return(actors, fmt.Errorf("error")).Times(1)
+				s.EXPECT().GetFilms(&details).Return(nil, fmt.Errorf("error")).Times(1)
 			},
 			expectedStatusCode:  http.StatusInternalServerError,
 			expectedRequestBody: []byte("error\n"),
@@ -714,14 +1150,14 @@ func TestGetFilms(t *testing.T) {
 }
 
 func TestUpdateFilm(t *testing.T) {
-	type mockBehavior func(s *mock_service.MockUsecase, name string, user service.Film)
+	type mockBehavior func(s *mock_service.MockUsecase, name string, user service.FilmPatch)
 	var resp *auth.ResponseModel = &auth.ResponseModel{Status: "OK"}
 	ans, _ := json.Marshal(resp)
 
 	testTable := []struct {
 		name                string
 		inputBody           string
-		inputUser           service.Film
+		inputUser           service.FilmPatch
 		mockBehavior        mockBehavior
 		expectedStatusCode  int
 		expectedRequestBody []byte
@@ -729,14 +1165,15 @@ func TestUpdateFilm(t *testing.T) {
 		{
 			name:      "OK",
 			inputBody: `{"name":"Sasha", "rating":5.5, "RDate":"1999-10-10", "Desc":"nice film"}`,
-			inputUser: service.Film{
-				Name:   "Sasha",
-				Rating: 5.5,
-				RDate:  "1999-10-10",
-				Desc:   "nice film",
+			inputUser: service.FilmPatch{
+				Name:   service.Patched("Sasha"),
+				Rating: service.Patched[float32](5.5),
+				RDate:  service.Patched("1999-10-10"),
+				Desc:   service.Patched("nice film"),
 			},
-			mockBehavior: func(s *mock_service.MockUsecase, name string, actor service.Film) {
-				s.EXPECT().UpdateFilm(name, &actor).Return(nil).Times(1)
+			mockBehavior: func(s *mock_service.MockUsecase, name string, actor service.FilmPatch) {
+				s.EXPECT().GetFilmId(name).Return(1, nil).Times(1)
+				s.EXPECT().UpdateFilm(gomock.Any(), 1, &actor).Return(nil).Times(1)
 			},
 			expectedStatusCode:  http.StatusOK,
 			expectedRequestBody: ans,
@@ -744,14 +1181,15 @@ func TestUpdateFilm(t *testing.T) {
 		{
 			name:      "Error",
 			inputBody: `{"name":"Sasha", "rating":5.5, "RDate":"1999-10-10", "Desc":"nice film"}`,
-			inputUser: service.Film{
-				Name:   "Sasha",
-				Rating: 5.5,
-				RDate:  "1999-10-10",
-				Desc:   "nice film",
+			inputUser: service.FilmPatch{
+				Name:   service.Patched("Sasha"),
+				Rating: service.Patched[float32](5.5),
+				RDate:  service.Patched("1999-10-10"),
+				Desc:   service.Patched("nice film"),
 			},
-			mockBehavior: func(s *mock_service.MockUsecase, name string, actor service.Film) {
-				s.EXPECT().UpdateFilm(name, &actor).Return(fmt.Errorf("error")).Times(1)
+			mockBehavior: func(s *mock_service.MockUsecase, name string, actor service.FilmPatch) {
+				s.EXPECT().GetFilmId(name).Return(1, nil).Times(1)
+				s.EXPECT().UpdateFilm(gomock.Any(), 1, &actor).Return(fmt.Errorf("error")).Times(1)
 			},
 			expectedStatusCode:  http.StatusInternalServerError,
 			expectedRequestBody: []byte("error\n"),
@@ -770,9 +1208,9 @@ func TestUpdateFilm(t *testing.T) {
 			handler := NewServiceHandler(mockService, mockAuth)
 
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest("POST", "/api/actor/update/Sasha", bytes.NewBufferString(testCase.inputBody))
+			r := httptest.NewRequest("POST", "/api/film/update/Sasha", bytes.NewBufferString(testCase.inputBody))
 			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 1})
-			r = r.WithContext(ctx)
+			r = mux.SetURLVars(r.WithContext(ctx), map[string]string{"film_name": "Sasha"})
 			handler.UpdateFilm(w, r)
 
 			require.Equal(t, testCase.expectedStatusCode, w.Code)
@@ -781,6 +1219,82 @@ func TestUpdateFilm(t *testing.T) {
 	}
 }
 
+func TestMergePatch(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
+
+	testTable := []struct {
+		name               string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:      "ClearDesc",
+			inputBody: `{"desc": null, "rating": 10}`,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().UpdateFilm(gomock.Any(), 7, &service.FilmPatch{
+					Rating: service.Patched[float32](10),
+					Desc:   service.PatchField[string]{Set: true, Null: true},
+				}).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":""}`,
+		},
+		{
+			name:               "Empty",
+			inputBody:          `{}`,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "patch should set at least one of name, rdate, rating, desc\n",
+		},
+		{
+			name:               "ClearName",
+			inputBody:          `{"name": null}`,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "name can't be cleared\n",
+		},
+		{
+			name:               "Invalid",
+			inputBody:          `{"rating": 11}`,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "rating should be (0;10]\n",
+		},
+		{
+			name:               "NotObject",
+			inputBody:          `[{"op": "replace", "path": "/rating", "value": 9}]`,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "json: cannot unmarshal array into Go value of type service.FilmPatch\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
+
+			handler := NewServiceHandler(mockService, mockAuth)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PATCH", "/api/film/7", bytes.NewBufferString(testCase.inputBody))
+			r.Header.Set("Content-Type", "application/merge-patch+json")
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 1})
+			r = mux.SetURLVars(r.WithContext(ctx), map[string]string{"id": "7"})
+			handler.UpdateFilm(w, r)
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
+
 func TestDeleteFilm(t *testing.T) {
 	type mockBehavior func(s *mock_service.MockUsecase, name string)
 	var resp *auth.ResponseModel = &auth.ResponseModel{Status: "OK"}
@@ -804,7 +1318,8 @@ func TestDeleteFilm(t *testing.T) {
 				Desc:   "nice film",
 			},
 			mockBehavior: func(s *mock_service.MockUsecase, name string) {
-				s.EXPECT().DeleteFilm(name).Return(nil).Times(1)
+				s.EXPECT().GetFilmId(name).Return(1, nil).Times(1)
+				s.EXPECT().DeleteFilm(gomock.Any(), 1, 1, 0).Return(nil).Times(1)
 			},
 			expectedStatusCode:  http.StatusOK,
 			expectedRequestBody: ans,
@@ -814,7 +1329,8 @@ func TestDeleteFilm(t *testing.T) {
 			inputBody: ``,
 			inputUser: service.Film{},
 			mockBehavior: func(s *mock_service.MockUsecase, name string) {
-				s.EXPECT().DeleteFilm(name).Return(fmt.Errorf("error")).Times(1)
+				s.EXPECT().GetFilmId(name).Return(1, nil).Times(1)
+				s.EXPECT().DeleteFilm(gomock.Any(), 1, 1, 0).Return(fmt.Errorf("error")).Times(1)
 			},
 			expectedStatusCode:  http.StatusInternalServerError,
 			expectedRequestBody: []byte("error\n"),
@@ -835,7 +1351,7 @@ func TestDeleteFilm(t *testing.T) {
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", "/film/delete/Sasha", bytes.NewBufferString(""))
 			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 1})
-			r = r.WithContext(ctx)
+			r = mux.SetURLVars(r.WithContext(ctx), map[string]string{"film_name": "Sasha"})
 			handler.DeleteFilm(w, r)
 
 			require.Equal(t, testCase.expectedStatusCode, w.Code)
@@ -845,44 +1361,64 @@ func TestDeleteFilm(t *testing.T) {
 }
 
 func TestSearchFilm(t *testing.T) {
-	type mockBehavior func(s *mock_service.MockUsecase, pattern string, film []string)
-	var resp []string = []string{"Sasha"}
+	type mockBehavior func(s *mock_service.MockUsecase)
+	var resp = &service.FilmSearchResult{Items: []service.FilmHit{{
+		Film:     service.Film{Id: 2, Name: "War and Peace", RDate: "1966-03-14", Rating: 8, Desc: "Napoleonic wars", Actors: []string{}},
+		Rank:     0.9,
+		Headline: "<b>War</b> and Peace. Napoleonic <b>wars</b>",
+	}}}
 	ans, _ := json.Marshal(resp)
 
 	testTable := []struct {
 		name                string
-		inputBody           string
-		inputUser           service.Actor
+		target              string
 		mockBehavior        mockBehavior
 		expectedStatusCode  int
 		expectedRequestBody []byte
 	}{
 		{
-			name:      "OK",
-			inputBody: `{"Name":"Sasha", "Sex":"m", "BDate":"1999-10-10"}`,
-			inputUser: service.Actor{
-				Name:  "Sasha",
-				Sex:   "m",
-				BDate: "1999-10-10",
-			},
-			mockBehavior: func(s *mock_service.MockUsecase, pattern string, actors []string) {
-				s.EXPECT().SearchFilms(pattern).Return(actors, nil).Times(1)
+			name:   "OK",
+			target: "/film/search?q=war&lang=russian&limit=5",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().SearchFilms(&service.SearchParams{Query: "war", Language: "russian", Limit: 5}).Return(resp, nil).Times(1)
 			},
 			expectedStatusCode:  http.StatusOK,
 			expectedRequestBody: ans,
 		},
 		{
-			name:      "Error",
-			inputBody: ``,
-			inputUser: service.Actor{},
-			mockBehavior: func(s *mock_service.MockUsecase, pattern string, actors []string) {
-				s.EXPECT().SearchFilms(pattern).Return(actors, fmt.Errorf("error")).Times(1)
+			name:   "Empty",
+			target: "/film/search/nothing",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().SearchFilms(&service.SearchParams{Query: "nothing", Limit: 20}).Return(&service.FilmSearchResult{Items: []service.FilmHit{}}, nil).Times(1)
 			},
-			expectedStatusCode:  http.StatusInternalServerError,
-			expectedRequestBody: []byte("error\n"),
+			expectedStatusCode:  http.StatusOK,
+			expectedRequestBody: []byte(`{"items":[]}`),
 		},
-	}
-
+		{
+			name:                "BadLanguage",
+			target:              "/film/search?q=war&lang=klingon",
+			mockBehavior:        func(s *mock_service.MockUsecase) {},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedRequestBody: []byte("lang should be one of english, russian, simple\n"),
+		},
+		{
+			name:                "BadLimit",
+			target:              "/film/search?q=war&limit=0",
+			mockBehavior:        func(s *mock_service.MockUsecase) {},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedRequestBody: []byte("limit should be [1;100]\n"),
+		},
+		{
+			name:   "Error",
+			target: "/film/search?q=war",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().SearchFilms(&service.SearchParams{Query: "war", Limit: 20}).Return(nil, fmt.Errorf("error")).Times(1)
+			},
+			expectedStatusCode:  http.StatusInternalServerError,
+			expectedRequestBody: []byte("error\n"),
+		},
+	}
+
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
 			c := gomock.NewController(t)
@@ -890,15 +1426,19 @@ func TestSearchFilm(t *testing.T) {
 
 			mockService := mock_service.NewMockUsecase(c)
 			mockAuth := mock_auth.NewMockUsecase(c)
-			testCase.mockBehavior(mockService, "asha", []string{testCase.inputUser.Name})
+			testCase.mockBehavior(mockService)
 
 			handler := NewServiceHandler(mockService, mockAuth)
 
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest("GET", "/film/search/asha", bytes.NewBufferString(""))
+			r := httptest.NewRequest("GET", testCase.target, nil)
 			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 1})
 			r = r.WithContext(ctx)
-			handler.SearchFilms(w, r)
+
+			rtr := mux.NewRouter().UseEncodedPath()
+			rtr.HandleFunc("/film/search", handler.SearchFilms)
+			rtr.HandleFunc("/film/search/{film_name}", handler.SearchFilms)
+			rtr.ServeHTTP(w, r)
 
 			require.Equal(t, testCase.expectedStatusCode, w.Code)
 			require.Equal(t, testCase.expectedRequestBody, w.Body.Bytes())
@@ -906,80 +1446,1241 @@ func TestSearchFilm(t *testing.T) {
 	}
 }
 
-func TestRole(t *testing.T) {
-	t.Run("UpdateErrRole", func(t *testing.T) {
-		c := gomock.NewController(t)
-		defer c.Finish()
+func TestGetFilmById(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
+	var (
+		first               = 1
+		resp  *service.Film = &service.Film{
+			Id:     7,
+			Name:   "Hamlet",
+			Rating: 8.1,
+			RDate:  "1996-12-25",
+			Actors: []string{"Kate Winslet", "Kenneth Branagh"},
+			Cast: []service.Credit{
+				{ActorId: 1, Actor: "Kenneth Branagh", Character: "Hamlet", Type: "lead", Order: &first},
+				{ActorId: 2, Actor: "Kate Winslet", Character: "Ophelia", Type: "supporting"},
+			},
+		}
+	)
+	ans, _ := json.Marshal(resp)
+	require.Contains(t, string(ans), `"cast":[{"actor_id":1,"actor":"Kenneth Branagh","character":"Hamlet","type":"lead","order":1},`+
+		`{"actor_id":2,"actor":"Kate Winslet","character":"Ophelia","type":"supporting","order":null}]`)
+
+	testTable := []struct {
+		name               string
+		vars               map[string]string
+		path               string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       []byte
+	}{
+		{
+			name: "OK",
+			vars: map[string]string{"id": "7"},
+			path: "/api/film/7",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetFilm(7).Return(resp, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       ans,
+		},
+		{
+			name: "NotFound",
+			vars: map[string]string{"id": "8"},
+			path: "/api/film/8",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetFilm(8).Return(nil, fmt.Errorf("no film: %w", service.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       []byte("no film: not found\n"),
+		},
+		{
+			name:               "BadId",
+			vars:               map[string]string{"id": "0"},
+			path:               "/api/film/0",
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       []byte("id should be a positive number: bad request\n"),
+		},
+		{
+			name: "AmbiguousName",
+			path: "/api/film/get/Hamlet",
+			vars: map[string]string{"film_name": "Hamlet"},
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetFilmId("Hamlet").Return(0, fmt.Errorf("film \"Hamlet\" has ids [3 7]: %w", service.ErrAmbiguousName)).Times(1)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       []byte("film \"Hamlet\" has ids [3 7]: name matches several entries, use id instead\n"),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
+
+			handler := NewServiceHandler(mockService, mockAuth)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", testCase.path, bytes.NewBufferString(""))
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 0})
+			r = mux.SetURLVars(r.WithContext(ctx), testCase.vars)
+			handler.GetFilm(w, r)
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.Bytes())
+		})
+	}
+}
+
+func TestNamePath(t *testing.T) {
+	cases := []struct {
+		name   string
+		path   string
+		film   string
+		status int
+	}{
+		{name: "Ascii", path: "/film/get/Hamlet", film: "Hamlet", status: http.StatusOK},
+		{name: "PlusIsSpace", path: "/film/get/Forrest+Gump", film: "Forrest Gump", status: http.StatusOK},
+		{name: "Accent", path: "/film/get/Am%C3%A9lie", film: "Amélie", status: http.StatusOK},
+		{name: "Decomposed", path: "/film/get/Ame%CC%81lie", film: "Amélie", status: http.StatusOK},
+		{name: "Punctuation", path: "/film/get/Se7en%3A%20Director%27s%20Cut", film: "Se7en: Director's Cut", status: http.StatusOK},
+		{name: "Cyrillic", path: "/film/get/%D0%A1%D1%82%D0%B0%D0%BB%D0%BA%D0%B5%D1%80", film: "Сталкер", status: http.StatusOK},
+		{name: "Slash", path: "/film/get/AC%2FDC", film: "AC/DC", status: http.StatusOK},
+		{name: "LiteralPlus", path: "/film/get/C%2B%2B", film: "C++", status: http.StatusOK},
+		{name: "NotUTF8", path: "/film/get/%FF", status: http.StatusBadRequest},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			if tCase.film != "" {
+				mockService.EXPECT().GetFilmId(tCase.film).Return(1, nil).Times(1)
+				mockService.EXPECT().GetFilm(1).Return(&service.Film{Id: 1, Name: tCase.film}, nil).Times(1)
+			}
+
+			handler := NewServiceHandler(mockService, mock_auth.NewMockUsecase(c))
+			rtr := mux.NewRouter().UseEncodedPath()
+			rtr.HandleFunc("/film/get/{film_name}", handler.GetFilm)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/film/get/x", nil)
+			r.URL.RawPath = tCase.path
+			r.URL.Path, _ = url.PathUnescape(tCase.path)
+			r = r.WithContext(context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1}))
+			rtr.ServeHTTP(w, r)
+
+			require.Equal(t, tCase.status, w.Code)
+		})
+	}
+}
+
+func TestValidName(t *testing.T) {
+	require.NoError(t, service.ValidName("Сталкер", 7))
+	require.NoError(t, service.ValidName("Se7en: Director's Cut", 150))
+	require.EqualError(t, service.ValidName("Сталкер", 6), "size Name should be [1;6]")
+	require.EqualError(t, service.ValidName("Tab\tName", 100), "name should not contain control characters")
+	require.EqualError(t, service.ValidName("\xff", 100), "name should be UTF-8 text")
+
+	actor := &service.Actor{Name: " Ame\u0301lie ", Sex: "f", BDate: "2001-04-25"}
+	require.NoError(t, (&ServiceHandler{}).validateActor(actor))
+	require.Equal(t, "Amélie", actor.Name)
+}
+
+func TestUpdateCredit(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
+	var (
+		character = "Forrest Gump"
+		lead      = "lead"
+		first     = 1
+	)
+
+	testTable := []struct {
+		name               string
+		body               string
+		role               int
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: "OK",
+			body: `{"film":"Forrest Gump","actor_id":3,"character":" Forrest Gump ","type":"lead","order":1}`,
+			role: 1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().UpdateCredit(gomock.Any(), &service.UpdateCreditParams{Film: "Forrest Gump", ActorId: 3, Character: &character, Type: &lead, Order: &first}).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":""}`,
+		},
+		{
+			name:               "NoActor",
+			body:               `{"film_id":1,"order":1}`,
+			role:               1,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "actor and film should be set by id or name\n",
+		},
+		{
+			name:               "NothingToChange",
+			body:               `{"film_id":1,"actor_id":3}`,
+			role:               1,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "character, type or order should be set\n",
+		},
+		{
+			name:               "BadType",
+			body:               `{"film_id":1,"actor_id":3,"type":"extra"}`,
+			role:               1,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "type should be one of lead, supporting, cameo, voice\n",
+		},
+		{
+			name:               "BadOrder",
+			body:               `{"film_id":1,"actor_id":3,"order":0}`,
+			role:               1,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "order should be a positive number\n",
+		},
+		{
+			name: "NoCredit",
+			body: `{"film_id":1,"actor_id":3,"order":2}`,
+			role: 1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().UpdateCredit(gomock.Any(), gomock.Any()).Return(fmt.Errorf("couldn't find relation: %w", service.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "couldn't find relation: not found\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
+
+			handler := NewServiceHandler(mockService, mockAuth)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PATCH", "/relation/credit", bytes.NewBufferString(testCase.body))
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: testCase.role})
+			handler.UpdateCredit(w, r.WithContext(ctx))
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestCrew(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
+
+	testTable := []struct {
+		name               string
+		method             string
+		target             string
+		body               string
+		role               int
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "Add",
+			method: "POST",
+			target: "/relation/crew",
+			body:   `{"film":"Forrest Gump","person_id":3,"job":"director"}`,
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().AddCrew(gomock.Any(), &service.CrewParams{Film: "Forrest Gump", PersonId: 3, Job: "director"}).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":""}`,
+		},
+		{
+			name:   "Delete",
+			method: "DELETE",
+			target: "/relation/crew",
+			body:   `{"film_id":1,"person":"Robert Zemeckis","job":"writer"}`,
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().DeleteCrew(gomock.Any(), &service.CrewParams{FilmId: 1, Person: "Robert Zemeckis", Job: "writer"}).Return(fmt.Errorf("couldn't find relation: %w", service.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "couldn't find relation: not found\n",
+		},
+		{
+			name:               "NoPerson",
+			method:             "POST",
+			target:             "/relation/crew",
+			body:               `{"film_id":1,"job":"director"}`,
+			role:               1,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "person and film should be set by id or name\n",
+		},
+		{
+			name:               "BadJob",
+			method:             "POST",
+			target:             "/relation/crew",
+			body:               `{"film_id":1,"person_id":3,"job":"actor"}`,
+			role:               1,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "job should be one of director, writer, producer, composer, cinematographer\n",
+		},
+		{
+			name:   "FilterByJob",
+			method: "GET",
+			target: "/person/get_all?job=composer",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetActors(&service.DetailsParams{Sort: "actor_name", Limit: 20, Job: "composer"}).Return(&service.ActorsPage{Items: []service.Actor{}}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"items":[],"next_cursor":"","total":0}`,
+		},
+		{
+			name:               "FilterByBadJob",
+			method:             "GET",
+			target:             "/person/get_all?job=actor",
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "job should be one of director, writer, producer, composer, cinematographer\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
+
+			handler := NewServiceHandler(mockService, mockAuth)
+			router := mux.NewRouter().UseEncodedPath()
+			router.HandleFunc("/relation/crew", handler.AddCrew).Methods(http.MethodPost)
+			router.HandleFunc("/relation/crew", handler.DeleteCrew).Methods(http.MethodDelete)
+			router.HandleFunc("/person/get_all", handler.GetActors).Methods(http.MethodGet)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(testCase.method, testCase.target, bytes.NewBufferString(testCase.body))
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: testCase.role})
+			router.ServeHTTP(w, r.WithContext(ctx))
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestGenre(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
+
+	testTable := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		role               int
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			path:   "/genre/add",
+			body:   `{"name":" Drama "}`,
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().CreateGenre(gomock.Any(), &service.Genre{Name: "Drama"}).Return(4, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":"","id":4}`,
+		},
+		{
+			name:               "CreateNoName",
+			method:             http.MethodPost,
+			path:               "/genre/add",
+			body:               `{"name":""}`,
+			role:               1,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "size Name should be [1;50]\n",
+		},
+		{
+			name:   "CreateExists",
+			method: http.MethodPost,
+			path:   "/genre/add",
+			body:   `{"name":"Drama"}`,
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().CreateGenre(gomock.Any(), &service.Genre{Name: "Drama"}).Return(0, fmt.Errorf("genre: %w", service.ErrAlreadyExists)).Times(1)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       "genre: already exists\n",
+		},
+		{
+			name:   "Get",
+			method: http.MethodGet,
+			path:   "/genre/4",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetGenre(4).Return(&service.Genre{Id: 4, Name: "Drama", Films: 12}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"id":4,"name":"Drama","films":12}`,
+		},
+		{
+			name:   "GetNotFound",
+			method: http.MethodGet,
+			path:   "/genre/5",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetGenre(5).Return(nil, fmt.Errorf("no genre: %w", service.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "no genre: not found\n",
+		},
+		{
+			name:   "GetAll",
+			method: http.MethodGet,
+			path:   "/genre/get_all",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetGenres().Return([]service.Genre{{Id: 4, Name: "Drama", Films: 12}, {Id: 1, Name: "Noir"}}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `[{"id":4,"name":"Drama","films":12},{"id":1,"name":"Noir","films":0}]`,
+		},
+		{
+			name:   "Update",
+			method: http.MethodPatch,
+			path:   "/genre/4",
+			body:   `{"name":"Melodrama"}`,
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().UpdateGenre(gomock.Any(), 4, &service.Genre{Name: "Melodrama"}).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":""}`,
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			path:   "/genre/4",
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().DeleteGenre(gomock.Any(), 4).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":""}`,
+		},
+		{
+			name:   "AddToFilm",
+			method: http.MethodPost,
+			path:   "/relation/genres_by_film",
+			body:   `{"film_id":7,"genres":["Drama","Noir"]}`,
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().AddGenresByFilm(gomock.Any(), &service.AddGenresByFilmParams{FilmId: 7, Genres: []string{"Drama", "Noir"}}).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":""}`,
+		},
+		{
+			name:               "AddToFilmNoGenres",
+			method:             http.MethodPost,
+			path:               "/relation/genres_by_film",
+			body:               `{"film_id":7}`,
+			role:               1,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "film and at least one genre should be set by id or name\n",
+		},
+		{
+			name:   "DeleteFromFilm",
+			method: http.MethodDelete,
+			path:   "/relation/genre",
+			body:   `{"film":"Hamlet","genre_id":4}`,
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().DeleteFilmGenre(gomock.Any(), &service.DeleteFilmGenreParams{Film: "Hamlet", GenreId: 4}).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":""}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
+
+			handler := NewServiceHandler(mockService, mockAuth)
+			rtr := mux.NewRouter()
+			rtr.HandleFunc("/genre/add", handler.CreateGenre).Methods(http.MethodPost)
+			rtr.HandleFunc("/genre/get_all", handler.GetGenres).Methods(http.MethodGet)
+			rtr.HandleFunc("/genre/{id:[0-9]+}", handler.GetGenre).Methods(http.MethodGet)
+			rtr.HandleFunc("/genre/{id:[0-9]+}", handler.UpdateGenre).Methods(http.MethodPatch)
+			rtr.HandleFunc("/genre/{id:[0-9]+}", handler.DeleteGenre).Methods(http.MethodDelete)
+			rtr.HandleFunc("/relation/genres_by_film", handler.AddGenresByFilm).Methods(http.MethodPost)
+			rtr.HandleFunc("/relation/genre", handler.DeleteFilmGenre).Methods(http.MethodDelete)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(testCase.method, testCase.path, bytes.NewBufferString(testCase.body))
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: testCase.role})
+			rtr.ServeHTTP(w, r.WithContext(ctx))
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestReview(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			path:   "/film/7/reviews",
+			body:   `{"rating":8,"text":" Great\nfilm "}`,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().CreateReview(gomock.Any(), &service.Review{FilmId: 7, UserId: 1, Rating: 8, Text: "Great\nfilm"}).Return(3, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":"","id":3}`,
+		},
+		{
+			name:   "CreateTwice",
+			method: http.MethodPost,
+			path:   "/film/7/reviews",
+			body:   `{"rating":8}`,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().CreateReview(gomock.Any(), &service.Review{FilmId: 7, UserId: 1, Rating: 8}).Return(0, fmt.Errorf("Key (film_id, user_id)=(7, 1) already exists.: %w", service.ErrAlreadyExists)).Times(1)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       "Key (film_id, user_id)=(7, 1) already exists.: already exists\n",
+		},
+		{
+			name:               "CreateBadRating",
+			method:             http.MethodPost,
+			path:               "/film/7/reviews",
+			body:               `{"rating":11}`,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "rating should be [1;10]\n",
+		},
+		{
+			name:               "CreateLongText",
+			method:             http.MethodPost,
+			path:               "/film/7/reviews",
+			body:               `{"rating":5,"text":"` + strings.Repeat("я", 5001) + `"}`,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "text should be at most 5000 characters of UTF-8\n",
+		},
+		{
+			name:   "GetAll",
+			method: http.MethodGet,
+			path:   "/film/7/reviews?sort=rating&limit=1",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetReviews(7, &service.DetailsParams{Sort: "rating", Limit: 1}).Return(&service.ReviewsPage{
+					Items: []service.Review{{Id: 3, FilmId: 7, UserId: 1, Login: "user", Rating: 8, CreatedAt: created, UpdatedAt: created}},
+					Total: 1,
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"items":[{"id":3,"film_id":7,"user_id":1,"login":"user","rating":8,"text":"","created_at":"2024-05-01T12:00:00Z","updated_at":"2024-05-01T12:00:00Z"}],"next_cursor":"","total":1}`,
+		},
+		{
+			name:   "GetAllNoFilm",
+			method: http.MethodGet,
+			path:   "/film/8/reviews",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetReviews(8, &service.DetailsParams{Sort: "created_at", Limit: 20}).Return(nil, fmt.Errorf("no film: %w", service.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "no film: not found\n",
+		},
+		{
+			name:   "Update",
+			method: http.MethodPatch,
+			path:   "/review/3",
+			body:   `{"rating":6,"text":"Worse the second time"}`,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().UpdateReview(gomock.Any(), 3, &service.Review{UserId: 1, Rating: 6, Text: "Worse the second time"}).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":""}`,
+		},
+		{
+			name:   "DeleteOthers",
+			method: http.MethodDelete,
+			path:   "/review/4",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().DeleteReview(gomock.Any(), 4, 1).Return(fmt.Errorf("no review: %w", service.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "no review: not found\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
+
+			handler := NewServiceHandler(mockService, mockAuth)
+			rtr := mux.NewRouter()
+			rtr.HandleFunc("/film/{id:[0-9]+}/reviews", handler.CreateReview).Methods(http.MethodPost)
+			rtr.HandleFunc("/film/{id:[0-9]+}/reviews", handler.GetReviews).Methods(http.MethodGet)
+			rtr.HandleFunc("/review/{id:[0-9]+}", handler.UpdateReview).Methods(http.MethodPatch)
+			rtr.HandleFunc("/review/{id:[0-9]+}", handler.DeleteReview).Methods(http.MethodDelete)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(testCase.method, testCase.path, bytes.NewBufferString(testCase.body))
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1})
+			rtr.ServeHTTP(w, r.WithContext(ctx))
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestFilmography(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
+
+	testTable := []struct {
+		name               string
+		path               string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: "ActorFilms",
+			path: "/actor/3/films?sort=rating&limit=1",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetActorFilms(3, &service.DetailsParams{Sort: "rating", Limit: 1}).Return(&service.FilmsPage{
+					Items: []service.Film{{Id: 7, Name: "Hamlet", Rating: 8.1, Actors: []string{"Kenneth Branagh"}, Genres: []string{}}},
+					Total: 2, NextCursor: "next",
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"items":[{"id":7,"name":"Hamlet","rdate":"","rating":8.1,"desc":"","actors":["Kenneth Branagh"],"genres":[]}],` +
+				`"next_cursor":"next","total":2}`,
+		},
+		{
+			name: "ActorFilmsNoActor",
+			path: "/actor/4/films",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetActorFilms(4, &service.DetailsParams{Sort: "release_date", Limit: 20}).
+					Return(nil, fmt.Errorf("no actor: %w", service.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "no actor: not found\n",
+		},
+		{
+			name: "FilmActors",
+			path: "/film/7/actors?sort=bdate&order=desc",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetFilmActors(7, &service.DetailsParams{Sort: "bdate", Order: "desc", Limit: 20}).Return(&service.ActorsPage{
+					Items: []service.Actor{{Id: 3, Name: "Kenneth Branagh", Sex: "male", BDate: "1960-12-10", Films: []string{"Hamlet"}}},
+					Total: 1,
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"items":[{"id":3,"name":"Kenneth Branagh","sex":"male","bdate":"1960-12-10","films":["Hamlet"]}],` +
+				`"next_cursor":"","total":1}`,
+		},
+		{
+			name: "ExpandActor",
+			path: "/actor/3?expand=films",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetActor(3).Return(&service.Actor{Id: 3, Name: "Kenneth Branagh", Films: []string{"Hamlet"}}, nil).Times(1)
+				s.EXPECT().GetActorFilms(3, &service.DetailsParams{Sort: "release_date", Limit: 100}).Return(&service.FilmsPage{
+					Items: []service.Film{{Id: 7, Name: "Hamlet", Actors: []string{}, Genres: []string{}}},
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"id":3,"name":"Kenneth Branagh","sex":"","bdate":"","films":["Hamlet"],` +
+				`"film_details":[{"id":7,"name":"Hamlet","rdate":"","rating":0,"desc":"","actors":[],"genres":[]}]}`,
+		},
+		{
+			name: "ExpandFilm",
+			path: "/film/7?expand=actors",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetFilm(7).Return(&service.Film{Id: 7, Name: "Hamlet", Actors: []string{"Kenneth Branagh"}, Genres: []string{}}, nil).Times(1)
+				s.EXPECT().GetFilmActors(7, &service.DetailsParams{Sort: "actor_name", Limit: 100}).Return(&service.ActorsPage{
+					Items: []service.Actor{{Id: 3, Name: "Kenneth Branagh", Films: []string{"Hamlet"}}},
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"id":7,"name":"Hamlet","rdate":"","rating":0,"desc":"","actors":["Kenneth Branagh"],"genres":[],` +
+				`"actor_details":[{"id":3,"name":"Kenneth Branagh","sex":"","bdate":"","films":["Hamlet"]}]}`,
+		},
+		{
+			name: "ExpandFilmPages",
+			path: "/film/7?expand=actors",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				cursor := service.Cursor{Sort: "actor_name", Key: "Kenneth Branagh", Id: 3}
+				s.EXPECT().GetFilm(7).Return(&service.Film{Id: 7, Name: "Hamlet", Actors: []string{"Kenneth Branagh", "Kate Winslet"}, Genres: []string{}}, nil).Times(1)
+				gomock.InOrder(
+					s.EXPECT().GetFilmActors(7, &service.DetailsParams{Sort: "actor_name", Limit: 100}).Return(&service.ActorsPage{
+						Items: []service.Actor{{Id: 3, Name: "Kenneth Branagh", Films: []string{"Hamlet"}}}, NextCursor: service.EncodeCursor(cursor), Total: 2,
+					}, nil).Times(1),
+					s.EXPECT().GetFilmActors(7, &service.DetailsParams{Sort: "actor_name", Limit: 100, Cursor: &cursor}).Return(&service.ActorsPage{
+						Items: []service.Actor{{Id: 4, Name: "Kate Winslet", Films: []string{"Hamlet"}}}, Total: 2,
+					}, nil).Times(1),
+				)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"id":7,"name":"Hamlet","rdate":"","rating":0,"desc":"","actors":["Kenneth Branagh","Kate Winslet"],"genres":[],` +
+				`"actor_details":[{"id":3,"name":"Kenneth Branagh","sex":"","bdate":"","films":["Hamlet"]},` +
+				`{"id":4,"name":"Kate Winslet","sex":"","bdate":"","films":["Hamlet"]}]}`,
+		},
+		{
+			name:               "BadExpand",
+			path:               "/film/7?expand=films",
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "expand should be one of actors\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
+
+			handler := NewServiceHandler(mockService, mockAuth)
+			rtr := mux.NewRouter()
+			rtr.HandleFunc("/actor/{id:[0-9]+}", handler.GetActor).Methods(http.MethodGet)
+			rtr.HandleFunc("/actor/{id:[0-9]+}/films", handler.GetActorFilms).Methods(http.MethodGet)
+			rtr.HandleFunc("/film/{id:[0-9]+}", handler.GetFilm).Methods(http.MethodGet)
+			rtr.HandleFunc("/film/{id:[0-9]+}/actors", handler.GetFilmActors).Methods(http.MethodGet)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1})
+			rtr.ServeHTTP(w, r.WithContext(ctx))
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestRecommendations(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
+
+	testTable := []struct {
+		name               string
+		path               string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: "Related",
+			path: "/film/7/similar?limit=1",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetRelatedFilms(7, 1).Return([]service.RelatedFilm{{
+					Film:       service.Film{Id: 8, Name: "Hamlet 2", Rating: 7, Actors: []string{}, Genres: []string{"Drama"}},
+					SharedCast: 2, SharedGenres: 1, Score: 7.9,
+				}}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `[{"id":8,"name":"Hamlet 2","rdate":"","rating":7,"desc":"","actors":[],"genres":["Drama"],"shared_cast":2,"shared_genres":1,"score":7.9}]`,
+		},
+		{
+			name: "RelatedNoFilm",
+			path: "/film/9/similar",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetRelatedFilms(9, 20).Return(nil, fmt.Errorf("no film: %w", service.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "no film: not found\n",
+		},
+		{
+			name:               "RelatedBadLimit",
+			path:               "/film/7/similar?limit=1000",
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "limit should be [1;100]\n",
+		},
+		{
+			name: "Recommendations",
+			path: "/me/recommendations?limit=5",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetRecommendations(1, 5).Return([]service.Recommendation{}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `[]`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
+
+			handler := NewServiceHandler(mockService, mockAuth)
+			rtr := mux.NewRouter()
+			rtr.HandleFunc("/film/{id:[0-9]+}/similar", handler.GetRelatedFilms).Methods(http.MethodGet)
+			rtr.HandleFunc("/me/recommendations", handler.GetRecommendations).Methods(http.MethodGet)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1})
+			rtr.ServeHTTP(w, r.WithContext(ctx))
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestTrash(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
+
+	deletedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	deletedBy := 1
+
+	testTable := []struct {
+		name               string
+		method             string
+		path               string
+		role               int
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "ListActors",
+			method: http.MethodGet,
+			path:   "/trash/actors?limit=1",
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetTrash("actors", &service.DetailsParams{Sort: "deleted_at", Limit: 1}).Return(&service.TrashPage{
+					Items: []service.TrashItem{{Id: 3, Name: "Kenneth Branagh", DeletedAt: deletedAt, DeletedBy: &deletedBy}},
+					Total: 2, NextCursor: "next",
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"items":[{"id":3,"name":"Kenneth Branagh","deleted_at":"2024-05-01T12:00:00Z","deleted_by":1}],` +
+				`"next_cursor":"next","total":2}`,
+		},
+		{
+			name:   "RestoreActor",
+			method: http.MethodPost,
+			path:   "/actor/3/restore",
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().RestoreActor(gomock.Any(), 3).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":""}`,
+		},
+		{
+			name:   "RestoreTakenName",
+			method: http.MethodPost,
+			path:   "/film/7/restore",
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().RestoreFilm(gomock.Any(), 7).Return(fmt.Errorf("Key (film_name, release_date)=(Hamlet, 1996-12-25) already exists.: %w",
+					service.ErrAlreadyExists)).Times(1)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       "Key (film_name, release_date)=(Hamlet, 1996-12-25) already exists.: already exists\n",
+		},
+		{
+			name:   "RestoreNotDeleted",
+			method: http.MethodPost,
+			path:   "/film/8/restore",
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().RestoreFilm(gomock.Any(), 8).Return(fmt.Errorf("no deleted film: %w", service.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "no deleted film: not found\n",
+		},
+	}
 
-		mockService := mock_service.NewMockUsecase(c)
-		mockAuth := mock_auth.NewMockUsecase(c)
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
 
-		handler := NewServiceHandler(mockService, mockAuth)
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
 
-		w := httptest.NewRecorder()
-		r := httptest.NewRequest("GET", "/film/search/asha", bytes.NewBufferString(""))
-		ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 0})
-		r = r.WithContext(ctx)
-		handler.UpdateFilm(w, r)
+			handler := NewServiceHandler(mockService, mockAuth)
+			rtr := mux.NewRouter()
+			rtr.HandleFunc("/trash/{kind:actors|films}", handler.GetTrash).Methods(http.MethodGet)
+			rtr.HandleFunc("/actor/{id:[0-9]+}/restore", handler.RestoreActor).Methods(http.MethodPost)
+			rtr.HandleFunc("/film/{id:[0-9]+}/restore", handler.RestoreFilm).Methods(http.MethodPost)
 
-		require.Equal(t, http.StatusForbidden, w.Code)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(testCase.method, testCase.path, nil)
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: testCase.role})
+			rtr.ServeHTTP(w, r.WithContext(ctx))
 
-		w = httptest.NewRecorder()
-		r = httptest.NewRequest("GET", "/film/search/asha", bytes.NewBufferString(""))
-		ctx = context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 0})
-		r = r.WithContext(ctx)
-		handler.UpdateActor(w, r)
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
 
-		require.Equal(t, http.StatusForbidden, w.Code)
-	})
-	t.Run("AddErrRole", func(t *testing.T) {
-		c := gomock.NewController(t)
-		defer c.Finish()
+func TestRevisions(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
 
-		mockService := mock_service.NewMockUsecase(c)
-		mockAuth := mock_auth.NewMockUsecase(c)
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	userId := 1
 
-		handler := NewServiceHandler(mockService, mockAuth)
+	testTable := []struct {
+		name               string
+		method             string
+		path               string
+		ifMatch            string
+		role               int
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "List",
+			method: http.MethodGet,
+			path:   "/person/3/revisions?limit=1",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetRevisions("actor", 3, &service.DetailsParams{Sort: "number", Limit: 1}).Return(&service.RevisionsPage{
+					Items: []service.Revision{{Entity: "actor", EntityId: 3, Number: 2, UserId: &userId,
+						Data: service.RawJSON(`{"bdate":"1960-12-10","name":"Kenneth Branagh","sex":"m"}`), CreatedAt: createdAt}},
+					Total: 2, NextCursor: "next",
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"items":[{"entity":"actor","entity_id":3,"number":2,"user_id":1,` +
+				`"data":{"bdate":"1960-12-10","name":"Kenneth Branagh","sex":"m"},"created_at":"2024-05-01T12:00:00Z"}],` +
+				`"next_cursor":"next","total":2}`,
+		},
+		{
+			name:   "Diff",
+			method: http.MethodGet,
+			path:   "/film/7/revisions/diff?from=1&to=3",
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().DiffRevisions("film", 7, 1, 3).Return(&service.RevisionDiff{From: 1, To: 3,
+					Changes: map[string]service.FieldChange{"rating": {From: 7.7, To: 8.1}}}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"from":1,"to":3,"changes":{"rating":{"from":7.7,"to":8.1}}}`,
+		},
+		{
+			name:               "DiffBadNumbers",
+			method:             http.MethodGet,
+			path:               "/film/7/revisions/diff?from=1",
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "from and to should be revision numbers\n",
+		},
+		{
+			name:   "Revert",
+			method: http.MethodPost,
+			path:   "/actor/3/revisions/1/revert",
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().RevertActor(gomock.Any(), 3, 1, 0).Return(4, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":"","id":4}`,
+		},
+		{
+			name:    "RevertStale",
+			method:  http.MethodPost,
+			path:    "/actor/3/revisions/1/revert",
+			ifMatch: `"2-00"`,
+			role:    1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().RevertActor(gomock.Any(), 3, 1, 2).Return(0, fmt.Errorf("actor is no longer version 2: %w", service.ErrVersionMismatch)).Times(1)
+			},
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       "actor is no longer version 2: changed meanwhile\n",
+		},
+		{
+			name:   "RevertMissing",
+			method: http.MethodPost,
+			path:   "/film/7/revisions/9/revert",
+			role:   1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().RevertFilm(gomock.Any(), 7, 9, 0).Return(0, fmt.Errorf("no revision 9 of film: %w", service.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "no revision 9 of film: not found\n",
+		},
+	}
 
-		w := httptest.NewRecorder()
-		r := httptest.NewRequest("GET", "/film/search/asha", bytes.NewBufferString(""))
-		ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 0})
-		r = r.WithContext(ctx)
-		handler.CreateActor(w, r)
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
 
-		require.Equal(t, http.StatusForbidden, w.Code)
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
 
-		w = httptest.NewRecorder()
-		r = httptest.NewRequest("GET", "/film/search/asha", bytes.NewBufferString(""))
-		ctx = context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 0})
-		r = r.WithContext(ctx)
-		handler.CreateFilm(w, r)
+			handler := NewServiceHandler(mockService, mockAuth)
+			rtr := mux.NewRouter()
+			rtr.HandleFunc("/{entity:actor|person|film}/{id:[0-9]+}/revisions", handler.GetRevisions).Methods(http.MethodGet)
+			rtr.HandleFunc("/{entity:actor|person|film}/{id:[0-9]+}/revisions/diff", handler.DiffRevisions).Methods(http.MethodGet)
+			rtr.HandleFunc("/{entity:actor|person|film}/{id:[0-9]+}/revisions/{number:[0-9]+}/revert", handler.RevertRevision).Methods(http.MethodPost)
 
-		require.Equal(t, http.StatusForbidden, w.Code)
-	})
-	t.Run("DeleteErrRole", func(t *testing.T) {
-		c := gomock.NewController(t)
-		defer c.Finish()
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(testCase.method, testCase.path, nil)
+			if testCase.ifMatch != "" {
+				r.Header.Set("If-Match", testCase.ifMatch)
+			}
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: testCase.role})
+			rtr.ServeHTTP(w, r.WithContext(ctx))
 
-		mockService := mock_service.NewMockUsecase(c)
-		mockAuth := mock_auth.NewMockUsecase(c)
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
 
-		handler := NewServiceHandler(mockService, mockAuth)
+func TestAudit(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockUsecase)
 
-		w := httptest.NewRecorder()
-		r := httptest.NewRequest("GET", "/film/search/asha", bytes.NewBufferString(""))
-		ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 0})
-		r = r.WithContext(ctx)
-		handler.DeleteActor(w, r)
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	userId, entityId := 1, 7
 
-		require.Equal(t, http.StatusForbidden, w.Code)
+	testTable := []struct {
+		name               string
+		path               string
+		role               int
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: "OK",
+			path: "/audit?entity=film&entity_id=7&user_id=1&from=2024-05-01T00:00:00Z&limit=1",
+			role: 1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetAudit(&service.AuditFilter{Entity: "film", EntityId: 7, UserId: 1, From: &from},
+					&service.DetailsParams{Sort: "created_at", Limit: 1}).Return(&service.AuditPage{
+					Items: []service.AuditEntry{{Id: 4, UserId: &userId, Entity: "film", EntityId: &entityId, Operation: "update",
+						Before: service.RawJSON(`{"rating":7.7}`), After: service.RawJSON(`{"rating":8.1}`), RequestId: "req-1", CreatedAt: createdAt}},
+					Total: 2, NextCursor: "next",
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"items":[{"id":4,"user_id":1,"entity":"film","entity_id":7,"operation":"update",` +
+				`"before":{"rating":7.7},"after":{"rating":8.1},"request_id":"req-1","created_at":"2024-05-01T12:00:00Z"}],` +
+				`"next_cursor":"next","total":2}`,
+		},
+		{
+			name: "Created",
+			path: "/audit?operation=create",
+			role: 1,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetAudit(&service.AuditFilter{Operation: "create"}, &service.DetailsParams{Sort: "created_at", Limit: 20}).
+					Return(&service.AuditPage{Items: []service.AuditEntry{{Id: 3, Entity: "genre", Operation: "create",
+						After: service.RawJSON(`{"name":"Drama"}`), CreatedAt: createdAt}}, Total: 1}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"items":[{"id":3,"user_id":null,"entity":"genre","entity_id":null,"operation":"create",` +
+				`"before":null,"after":{"name":"Drama"},"request_id":"","created_at":"2024-05-01T12:00:00Z"}],"next_cursor":"","total":1}`,
+		},
+		{
+			name:               "BadEntity",
+			path:               "/audit?entity=user",
+			role:               1,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "entity should be one of actor, film, genre, review, actor_film, film_crew, film_genre, trash\n",
+		},
+		{
+			name:               "BadUser",
+			path:               "/audit?user_id=x",
+			role:               1,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "user_id should be a positive number\n",
+		},
+		{
+			name:               "BadRange",
+			path:               "/audit?from=2024-05-02T00:00:00Z&to=2024-05-01T00:00:00Z",
+			role:               1,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "from should not be after to\n",
+		},
+		{
+			name:               "BadTime",
+			path:               "/audit?to=yesterday",
+			role:               1,
+			mockBehavior:       func(s *mock_service.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "to should be '2006-01-02T15:04:05Z' format\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			testCase.mockBehavior(mockService)
+
+			handler := NewServiceHandler(mockService, mockAuth)
+			rtr := mux.NewRouter()
+			rtr.HandleFunc("/audit", handler.GetAudit).Methods(http.MethodGet)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			ctx := context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: testCase.role})
+			rtr.ServeHTTP(w, r.WithContext(ctx))
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
 
-		w = httptest.NewRecorder()
-		r = httptest.NewRequest("GET", "/film/search/asha", bytes.NewBufferString(""))
-		ctx = context.WithValue(r.Context(), "tokenData", &auth.TokenData{Id: 1, Role: 0})
-		r = r.WithContext(ctx)
-		handler.DeleteFilm(w, r)
+func TestRole(t *testing.T) {
+	testTable := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		role               int
+		mockBehavior       func(s *mock_service.MockUsecase)
+		expectedStatusCode int
+	}{
+		{name: "ViewerReads", method: http.MethodGet, path: "/api/genre/4", role: auth.RoleViewer,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetGenre(4).Return(&service.Genre{Id: 4, Name: "Drama"}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK},
+		{name: "ViewerCreatesActor", method: http.MethodPost, path: "/api/actor/add", body: `{"name":"Sasha","sex":"m","bdate":"1999-10-10"}`,
+			role: auth.RoleViewer, expectedStatusCode: http.StatusForbidden},
+		{name: "ViewerCreatesFilm", method: http.MethodPost, path: "/api/film/add", role: auth.RoleViewer, expectedStatusCode: http.StatusForbidden},
+		{name: "ViewerUpdatesActor", method: http.MethodPatch, path: "/api/person/1", role: auth.RoleViewer, expectedStatusCode: http.StatusForbidden},
+		{name: "ViewerUpdatesFilm", method: http.MethodPatch, path: "/api/film/update/asha", role: auth.RoleViewer, expectedStatusCode: http.StatusForbidden},
+		{name: "ViewerDeletesActor", method: http.MethodDelete, path: "/api/actor/1", role: auth.RoleViewer, expectedStatusCode: http.StatusForbidden},
+		{name: "ViewerDeletesFilm", method: http.MethodDelete, path: "/api/film/1", role: auth.RoleViewer, expectedStatusCode: http.StatusForbidden},
+		{name: "ViewerChangesCredit", method: http.MethodPatch, path: "/api/relation/credit", role: auth.RoleViewer, expectedStatusCode: http.StatusForbidden},
+		{name: "ViewerAddsCrew", method: http.MethodPost, path: "/api/relation/crew", role: auth.RoleViewer, expectedStatusCode: http.StatusForbidden},
+		{name: "ViewerCreatesGenre", method: http.MethodPost, path: "/api/genre/add", role: auth.RoleViewer, expectedStatusCode: http.StatusForbidden},
+		{name: "ViewerListsTrash", method: http.MethodGet, path: "/api/trash/films", role: auth.RoleViewer, expectedStatusCode: http.StatusForbidden},
+		{name: "ViewerRestores", method: http.MethodPost, path: "/api/actor/3/restore", role: auth.RoleViewer, expectedStatusCode: http.StatusForbidden},
+		{name: "ViewerReverts", method: http.MethodPost, path: "/api/film/7/revisions/1/revert", role: auth.RoleViewer, expectedStatusCode: http.StatusForbidden},
+		{name: "EditorCreatesActor", method: http.MethodPost, path: "/api/actor/add", body: `{"name":"Sasha","sex":"m","bdate":"1999-10-10"}`,
+			role: auth.RoleEditor,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().CreateActor(gomock.Any(), gomock.Any()).Return(1, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK},
+		{name: "EditorCreatesGenre", method: http.MethodPost, path: "/api/genre/add", role: auth.RoleEditor, expectedStatusCode: http.StatusForbidden},
+		{name: "EditorDeletesGenre", method: http.MethodDelete, path: "/api/genre/4", role: auth.RoleEditor, expectedStatusCode: http.StatusForbidden},
+		{name: "ModeratorListsTrash", method: http.MethodGet, path: "/api/trash/actors", role: auth.RoleModerator, expectedStatusCode: http.StatusForbidden},
+		{name: "ModeratorRestores", method: http.MethodPost, path: "/api/film/7/restore", role: auth.RoleModerator, expectedStatusCode: http.StatusForbidden},
+		{name: "AdminRestores", method: http.MethodPost, path: "/api/film/7/restore", role: auth.RoleAdmin,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().RestoreFilm(gomock.Any(), 7).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK},
+		{name: "ModeratorReadsAudit", method: http.MethodGet, path: "/api/audit", role: auth.RoleModerator, expectedStatusCode: http.StatusForbidden},
+		{name: "AdminReadsAudit", method: http.MethodGet, path: "/api/audit", role: auth.RoleAdmin,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().GetAudit(gomock.Any(), gomock.Any()).Return(&service.AuditPage{}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK},
+		{name: "UnknownRole", method: http.MethodPost, path: "/api/genre/add", role: 9, expectedStatusCode: http.StatusForbidden},
+		{name: "ViewerDeletesOwnReview", method: http.MethodDelete, path: "/api/review/5", role: auth.RoleViewer,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().DeleteReview(gomock.Any(), 5, 1).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK},
+		{name: "ModeratorDeletesAnyReview", method: http.MethodDelete, path: "/api/review/5", role: auth.RoleModerator,
+			mockBehavior: func(s *mock_service.MockUsecase) {
+				s.EXPECT().DeleteReview(gomock.Any(), 5, 0).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK},
+	}
 
-		require.Equal(t, http.StatusForbidden, w.Code)
-	})
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockService := mock_service.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			mockAuth.EXPECT().ParseToken("token").Return(&auth.TokenData{Id: 1, Role: testCase.role}, nil).Times(1)
+			if testCase.mockBehavior != nil {
+				testCase.mockBehavior(mockService)
+			}
+
+			handler := NewServiceHandler(mockService, mockAuth)
+			rtr := mux.NewRouter()
+			MapRoutes(rtr, handler)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(testCase.method, testCase.path, bytes.NewBufferString(testCase.body))
+			r.Header.Set("Authorization", "Bearer token")
+			rtr.ServeHTTP(w, r)
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			if testCase.expectedStatusCode == http.StatusForbidden {
+				require.Equal(t, "You don't have permission for this operation.\n", w.Body.String())
+			}
+		})
+	}
 }
@@ -3,6 +3,8 @@ package http
 import (
 	"context"
 	"film_library/internal/cconstant"
+	"film_library/internal/service"
+	"film_library/pkg/requestid"
 	"fmt"
 	"net/http"
 	"strings"
@@ -28,6 +30,7 @@ func (s *ServiceHandler) userIdentity(h http.Handler) http.Handler {
 			return
 		}
 		ctx := context.WithValue(r.Context(), "tokenData", tokenData)
+		ctx = service.WithOrigin(ctx, service.Origin{UserId: tokenData.Id, RequestId: requestid.FromContext(ctx)})
 
 		h.ServeHTTP(rw, r.WithContext(ctx))
 	})
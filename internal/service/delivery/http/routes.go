@@ -1,28 +1,87 @@
 package http
 
 import (
+	"film_library/internal/auth"
+	authHttp "film_library/internal/auth/delivery/http"
 	"github.com/gorilla/mux"
 	"net/http"
 )
 
+// MapRoutes registers the service routes. Names in the path may be any
+// percent-encoded UTF-8, so rtr should match on the encoded path
+// (mux.Router.UseEncodedPath) for names containing "%2F".
+// Reading is open to every user, the other routes name the permission they need.
 func MapRoutes(rtr *mux.Router, s *ServiceHandler) {
+	var (
+		edit   = authHttp.Require(auth.PermCatalogWrite)
+		manage = authHttp.Require(auth.PermCatalogManage)
+		audit  = authHttp.Require(auth.PermAuditRead)
+	)
+
 	api := rtr.PathPrefix("/api").Subrouter()
 	api.Use(s.userIdentity)
-	api.HandleFunc("/actor/add", s.CreateActor).Methods(http.MethodPost)
-	api.HandleFunc("/actor/get/{actor_name:[A-Za-z+]+}", s.GetActor).Methods(http.MethodGet)
+	api.HandleFunc("/actor/add", edit(s.CreateActor)).Methods(http.MethodPost)
+	api.HandleFunc("/actor/get/{actor_name}", s.GetActor).Methods(http.MethodGet)
 	api.HandleFunc("/actor/get_all", s.GetActors).Methods(http.MethodGet)
-	api.HandleFunc("/actor/delete/{actor_name:[A-Za-z+]+}", s.DeleteActor).Methods(http.MethodDelete)
-	api.HandleFunc("/actor/update/{actor_name:[A-Za-z+]+}", s.UpdateActor).Methods(http.MethodPatch)
-	api.HandleFunc("/actor/search/{actor_name:[A-Za-z+]+}", s.SearchActor).Methods(http.MethodGet)
+	api.HandleFunc("/actor/delete/{actor_name}", edit(s.DeleteActor)).Methods(http.MethodDelete)
+	api.HandleFunc("/actor/update/{actor_name}", edit(s.UpdateActor)).Methods(http.MethodPatch)
+	api.HandleFunc("/actor/search", s.SearchActor).Methods(http.MethodGet)
+	api.HandleFunc("/actor/search/{actor_name}", s.SearchActor).Methods(http.MethodGet)
+	api.HandleFunc("/actor/path", s.GetActorPath).Methods(http.MethodGet)
+	api.HandleFunc("/actor/{id:[0-9]+}", s.GetActor).Methods(http.MethodGet)
+	api.HandleFunc("/actor/{id:[0-9]+}", edit(s.UpdateActor)).Methods(http.MethodPatch)
+	api.HandleFunc("/actor/{id:[0-9]+}", edit(s.DeleteActor)).Methods(http.MethodDelete)
+	api.HandleFunc("/actor/{id:[0-9]+}/films", s.GetActorFilms).Methods(http.MethodGet)
+	api.HandleFunc("/actor/{id:[0-9]+}/restore", manage(s.RestoreActor)).Methods(http.MethodPost)
+
+	// People are stored together with actors, so the person routes share the actor handlers.
+	api.HandleFunc("/person/add", edit(s.CreateActor)).Methods(http.MethodPost)
+	api.HandleFunc("/person/get_all", s.GetActors).Methods(http.MethodGet)
+	api.HandleFunc("/person/search", s.SearchActor).Methods(http.MethodGet)
+	api.HandleFunc("/person/{id:[0-9]+}", s.GetActor).Methods(http.MethodGet)
+	api.HandleFunc("/person/{id:[0-9]+}", edit(s.UpdateActor)).Methods(http.MethodPatch)
+	api.HandleFunc("/person/{id:[0-9]+}", edit(s.DeleteActor)).Methods(http.MethodDelete)
+	api.HandleFunc("/person/{id:[0-9]+}/restore", manage(s.RestoreActor)).Methods(http.MethodPost)
 
-	api.HandleFunc("/film/add", s.CreateFilm).Methods(http.MethodPost)
-	api.HandleFunc("/film/get/{film_name:[0-9A-Za-z.?+]+}", s.GetFilm).Methods(http.MethodGet)
+	api.HandleFunc("/film/add", edit(s.CreateFilm)).Methods(http.MethodPost)
+	api.HandleFunc("/film/get/{film_name}", s.GetFilm).Methods(http.MethodGet)
 	api.HandleFunc("/film/get_all", s.GetFilms).Methods(http.MethodGet)
-	api.HandleFunc("/film/delete/{film_name:[0-9A-Za-z.?+]+}", s.DeleteFilm).Methods(http.MethodDelete)
-	api.HandleFunc("/film/update/{film_name:[0-9A-Za-z.?+]+}", s.UpdateFilm).Methods(http.MethodPatch)
-	api.HandleFunc("/film/search/{film_name:[0-9A-Za-z.?+]+}", s.SearchFilms).Methods(http.MethodGet)
+	api.HandleFunc("/film/delete/{film_name}", edit(s.DeleteFilm)).Methods(http.MethodDelete)
+	api.HandleFunc("/film/update/{film_name}", edit(s.UpdateFilm)).Methods(http.MethodPatch)
+	api.HandleFunc("/film/search", s.SearchFilms).Methods(http.MethodGet)
+	api.HandleFunc("/film/search/{film_name}", s.SearchFilms).Methods(http.MethodGet)
+	api.HandleFunc("/film/{id:[0-9]+}", s.GetFilm).Methods(http.MethodGet)
+	api.HandleFunc("/film/{id:[0-9]+}", edit(s.UpdateFilm)).Methods(http.MethodPatch)
+	api.HandleFunc("/film/{id:[0-9]+}", edit(s.DeleteFilm)).Methods(http.MethodDelete)
+	api.HandleFunc("/film/{id:[0-9]+}/reviews", s.CreateReview).Methods(http.MethodPost)
+	api.HandleFunc("/film/{id:[0-9]+}/reviews", s.GetReviews).Methods(http.MethodGet)
+	api.HandleFunc("/film/{id:[0-9]+}/similar", s.GetRelatedFilms).Methods(http.MethodGet)
+	api.HandleFunc("/film/{id:[0-9]+}/actors", s.GetFilmActors).Methods(http.MethodGet)
+	api.HandleFunc("/film/{id:[0-9]+}/restore", manage(s.RestoreFilm)).Methods(http.MethodPost)
+	api.HandleFunc("/me/recommendations", s.GetRecommendations).Methods(http.MethodGet)
+
+	api.HandleFunc("/review/{id:[0-9]+}", s.UpdateReview).Methods(http.MethodPatch)
+	api.HandleFunc("/review/{id:[0-9]+}", s.DeleteReview).Methods(http.MethodDelete)
+
+	api.HandleFunc("/genre/add", manage(s.CreateGenre)).Methods(http.MethodPost)
+	api.HandleFunc("/genre/get_all", s.GetGenres).Methods(http.MethodGet)
+	api.HandleFunc("/genre/{id:[0-9]+}", s.GetGenre).Methods(http.MethodGet)
+	api.HandleFunc("/genre/{id:[0-9]+}", manage(s.UpdateGenre)).Methods(http.MethodPatch)
+	api.HandleFunc("/genre/{id:[0-9]+}", manage(s.DeleteGenre)).Methods(http.MethodDelete)
+
+	api.HandleFunc("/{entity:actor|person|film}/{id:[0-9]+}/revisions", s.GetRevisions).Methods(http.MethodGet)
+	api.HandleFunc("/{entity:actor|person|film}/{id:[0-9]+}/revisions/diff", s.DiffRevisions).Methods(http.MethodGet)
+	api.HandleFunc("/{entity:actor|person|film}/{id:[0-9]+}/revisions/{number:[0-9]+}/revert", edit(s.RevertRevision)).Methods(http.MethodPost)
+
+	api.HandleFunc("/trash/{kind:actors|films}", manage(s.GetTrash)).Methods(http.MethodGet)
+	api.HandleFunc("/audit", audit(s.GetAudit)).Methods(http.MethodGet)
 
-	api.HandleFunc("/relation/films_by_actor", s.AddFilmsByActor).Methods(http.MethodPost)
-	api.HandleFunc("/relation/actors_by_film", s.AddActorsByFilm).Methods(http.MethodPost)
-	api.HandleFunc("/relation/delete", s.DeleteActorFilm).Methods(http.MethodDelete)
+	api.HandleFunc("/relation/films_by_actor", edit(s.AddFilmsByActor)).Methods(http.MethodPost)
+	api.HandleFunc("/relation/actors_by_film", edit(s.AddActorsByFilm)).Methods(http.MethodPost)
+	api.HandleFunc("/relation/delete", edit(s.DeleteActorFilm)).Methods(http.MethodDelete)
+	api.HandleFunc("/relation/credit", edit(s.UpdateCredit)).Methods(http.MethodPatch)
+	api.HandleFunc("/relation/crew", edit(s.AddCrew)).Methods(http.MethodPost)
+	api.HandleFunc("/relation/crew", edit(s.DeleteCrew)).Methods(http.MethodDelete)
+	api.HandleFunc("/relation/genres_by_film", edit(s.AddGenresByFilm)).Methods(http.MethodPost)
+	api.HandleFunc("/relation/genre", edit(s.DeleteFilmGenre)).Methods(http.MethodDelete)
 }
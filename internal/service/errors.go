@@ -0,0 +1,12 @@
+package service
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrAmbiguousName = errors.New("name matches several entries, use id instead")
+	ErrTimeout       = errors.New("took too long")
+	// ErrVersionMismatch is returned when an entry has changed since the version a change was based on.
+	ErrVersionMismatch = errors.New("changed meanwhile")
+)
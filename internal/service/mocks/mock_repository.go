@@ -5,8 +5,11 @@
 package mock_service
 
 import (
+	context "context"
+	recommend "film_library/internal/recommend"
 	service "film_library/internal/service"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,6 +37,21 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ActorLinks mocks base method.
+func (m *MockRepository) ActorLinks(ctx context.Context, actorIds []int) ([]service.ActorLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActorLinks", ctx, actorIds)
+	ret0, _ := ret[0].([]service.ActorLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActorLinks indicates an expected call of ActorLinks.
+func (mr *MockRepositoryMockRecorder) ActorLinks(ctx, actorIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActorLinks", reflect.TypeOf((*MockRepository)(nil).ActorLinks), ctx, actorIds)
+}
+
 // AddActorsByFilm mocks base method.
 func (m *MockRepository) AddActorsByFilm(params *service.AddActorsByFilmParams) error {
 	m.ctrl.T.Helper()
@@ -48,6 +66,34 @@ func (mr *MockRepositoryMockRecorder) AddActorsByFilm(params interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActorsByFilm", reflect.TypeOf((*MockRepository)(nil).AddActorsByFilm), params)
 }
 
+// AddAudit mocks base method.
+func (m *MockRepository) AddAudit(ctx context.Context, entry *service.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAudit", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAudit indicates an expected call of AddAudit.
+func (mr *MockRepositoryMockRecorder) AddAudit(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAudit", reflect.TypeOf((*MockRepository)(nil).AddAudit), ctx, entry)
+}
+
+// AddCrew mocks base method.
+func (m *MockRepository) AddCrew(params *service.CrewParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCrew", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCrew indicates an expected call of AddCrew.
+func (mr *MockRepositoryMockRecorder) AddCrew(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCrew", reflect.TypeOf((*MockRepository)(nil).AddCrew), params)
+}
+
 // AddFilmsByActor mocks base method.
 func (m *MockRepository) AddFilmsByActor(params *service.AddFilmsByActorParams) error {
 	m.ctrl.T.Helper()
@@ -62,14 +108,44 @@ func (mr *MockRepositoryMockRecorder) AddFilmsByActor(params interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFilmsByActor", reflect.TypeOf((*MockRepository)(nil).AddFilmsByActor), params)
 }
 
-// CreateActor mocks base method.
-func (m *MockRepository) CreateActor(params *service.Actor) error {
+// AddGenresByFilm mocks base method.
+func (m *MockRepository) AddGenresByFilm(params *service.AddGenresByFilmParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateActor", params)
+	ret := m.ctrl.Call(m, "AddGenresByFilm", params)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// AddGenresByFilm indicates an expected call of AddGenresByFilm.
+func (mr *MockRepositoryMockRecorder) AddGenresByFilm(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGenresByFilm", reflect.TypeOf((*MockRepository)(nil).AddGenresByFilm), params)
+}
+
+// AddRevision mocks base method.
+func (m *MockRepository) AddRevision(ctx context.Context, rev *service.Revision, baseline service.RawJSON) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRevision", ctx, rev, baseline)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddRevision indicates an expected call of AddRevision.
+func (mr *MockRepositoryMockRecorder) AddRevision(ctx, rev, baseline interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRevision", reflect.TypeOf((*MockRepository)(nil).AddRevision), ctx, rev, baseline)
+}
+
+// CreateActor mocks base method.
+func (m *MockRepository) CreateActor(params *service.Actor) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateActor", params)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // CreateActor indicates an expected call of CreateActor.
 func (mr *MockRepositoryMockRecorder) CreateActor(params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
@@ -77,11 +153,12 @@ func (mr *MockRepositoryMockRecorder) CreateActor(params interface{}) *gomock.Ca
 }
 
 // CreateFilm mocks base method.
-func (m *MockRepository) CreateFilm(params *service.Film) error {
+func (m *MockRepository) CreateFilm(params *service.Film) (int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateFilm", params)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateFilm indicates an expected call of CreateFilm.
@@ -90,18 +167,48 @@ func (mr *MockRepositoryMockRecorder) CreateFilm(params interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFilm", reflect.TypeOf((*MockRepository)(nil).CreateFilm), params)
 }
 
+// CreateGenre mocks base method.
+func (m *MockRepository) CreateGenre(params *service.Genre) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGenre", params)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGenre indicates an expected call of CreateGenre.
+func (mr *MockRepositoryMockRecorder) CreateGenre(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGenre", reflect.TypeOf((*MockRepository)(nil).CreateGenre), params)
+}
+
+// CreateReview mocks base method.
+func (m *MockRepository) CreateReview(params *service.Review) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReview", params)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReview indicates an expected call of CreateReview.
+func (mr *MockRepositoryMockRecorder) CreateReview(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReview", reflect.TypeOf((*MockRepository)(nil).CreateReview), params)
+}
+
 // DeleteActor mocks base method.
-func (m *MockRepository) DeleteActor(name string) error {
+func (m *MockRepository) DeleteActor(id, userId, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteActor", name)
+	ret := m.ctrl.Call(m, "DeleteActor", id, userId, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteActor indicates an expected call of DeleteActor.
-func (mr *MockRepositoryMockRecorder) DeleteActor(name interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) DeleteActor(id, userId, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActor", reflect.TypeOf((*MockRepository)(nil).DeleteActor), name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActor", reflect.TypeOf((*MockRepository)(nil).DeleteActor), id, userId, version)
 }
 
 // DeleteActorFilm mocks base method.
@@ -118,40 +225,111 @@ func (mr *MockRepositoryMockRecorder) DeleteActorFilm(params interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorFilm", reflect.TypeOf((*MockRepository)(nil).DeleteActorFilm), params)
 }
 
+// DeleteCrew mocks base method.
+func (m *MockRepository) DeleteCrew(params *service.CrewParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCrew", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCrew indicates an expected call of DeleteCrew.
+func (mr *MockRepositoryMockRecorder) DeleteCrew(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCrew", reflect.TypeOf((*MockRepository)(nil).DeleteCrew), params)
+}
+
 // DeleteFilm mocks base method.
-func (m *MockRepository) DeleteFilm(name string) error {
+func (m *MockRepository) DeleteFilm(id, userId, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteFilm", name)
+	ret := m.ctrl.Call(m, "DeleteFilm", id, userId, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteFilm indicates an expected call of DeleteFilm.
-func (mr *MockRepositoryMockRecorder) DeleteFilm(name interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) DeleteFilm(id, userId, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFilm", reflect.TypeOf((*MockRepository)(nil).DeleteFilm), id, userId, version)
+}
+
+// DeleteFilmGenre mocks base method.
+func (m *MockRepository) DeleteFilmGenre(params *service.DeleteFilmGenreParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFilmGenre", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFilmGenre indicates an expected call of DeleteFilmGenre.
+func (mr *MockRepositoryMockRecorder) DeleteFilmGenre(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFilmGenre", reflect.TypeOf((*MockRepository)(nil).DeleteFilmGenre), params)
+}
+
+// DeleteGenre mocks base method.
+func (m *MockRepository) DeleteGenre(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGenre", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGenre indicates an expected call of DeleteGenre.
+func (mr *MockRepositoryMockRecorder) DeleteGenre(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFilm", reflect.TypeOf((*MockRepository)(nil).DeleteFilm), name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGenre", reflect.TypeOf((*MockRepository)(nil).DeleteGenre), id)
+}
+
+// DeleteReview mocks base method.
+func (m *MockRepository) DeleteReview(id, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", id, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockRepositoryMockRecorder) DeleteReview(id, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockRepository)(nil).DeleteReview), id, userId)
 }
 
 // GetActor mocks base method.
-func (m *MockRepository) GetActor(name string) (*service.Actor, error) {
+func (m *MockRepository) GetActor(id int) (*service.Actor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActor", name)
+	ret := m.ctrl.Call(m, "GetActor", id)
 	ret0, _ := ret[0].(*service.Actor)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetActor indicates an expected call of GetActor.
-func (mr *MockRepositoryMockRecorder) GetActor(name interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetActor(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActor", reflect.TypeOf((*MockRepository)(nil).GetActor), name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActor", reflect.TypeOf((*MockRepository)(nil).GetActor), id)
+}
+
+// GetActorIds mocks base method.
+func (m *MockRepository) GetActorIds(name string) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActorIds", name)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorIds indicates an expected call of GetActorIds.
+func (mr *MockRepositoryMockRecorder) GetActorIds(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorIds", reflect.TypeOf((*MockRepository)(nil).GetActorIds), name)
 }
 
 // GetActors mocks base method.
-func (m *MockRepository) GetActors(params *service.DetailsParams) ([]service.Actor, error) {
+func (m *MockRepository) GetActors(params *service.DetailsParams) (*service.ActorsPage, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetActors", params)
-	ret0, _ := ret[0].([]service.Actor)
+	ret0, _ := ret[0].(*service.ActorsPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -162,26 +340,86 @@ func (mr *MockRepositoryMockRecorder) GetActors(params interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockRepository)(nil).GetActors), params)
 }
 
+// GetActorsByIds mocks base method.
+func (m *MockRepository) GetActorsByIds(ids []int) ([]service.Actor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActorsByIds", ids)
+	ret0, _ := ret[0].([]service.Actor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorsByIds indicates an expected call of GetActorsByIds.
+func (mr *MockRepositoryMockRecorder) GetActorsByIds(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsByIds", reflect.TypeOf((*MockRepository)(nil).GetActorsByIds), ids)
+}
+
+// GetAudit mocks base method.
+func (m *MockRepository) GetAudit(filter *service.AuditFilter, params *service.DetailsParams) (*service.AuditPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAudit", filter, params)
+	ret0, _ := ret[0].(*service.AuditPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAudit indicates an expected call of GetAudit.
+func (mr *MockRepositoryMockRecorder) GetAudit(filter, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAudit", reflect.TypeOf((*MockRepository)(nil).GetAudit), filter, params)
+}
+
+// GetCredit mocks base method.
+func (m *MockRepository) GetCredit(actorId, filmId int) (*service.Credit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCredit", actorId, filmId)
+	ret0, _ := ret[0].(*service.Credit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCredit indicates an expected call of GetCredit.
+func (mr *MockRepositoryMockRecorder) GetCredit(actorId, filmId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredit", reflect.TypeOf((*MockRepository)(nil).GetCredit), actorId, filmId)
+}
+
 // GetFilm mocks base method.
-func (m *MockRepository) GetFilm(name string) (*service.Film, error) {
+func (m *MockRepository) GetFilm(id int) (*service.Film, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilm", name)
+	ret := m.ctrl.Call(m, "GetFilm", id)
 	ret0, _ := ret[0].(*service.Film)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilm indicates an expected call of GetFilm.
-func (mr *MockRepositoryMockRecorder) GetFilm(name interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetFilm(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilm", reflect.TypeOf((*MockRepository)(nil).GetFilm), id)
+}
+
+// GetFilmIds mocks base method.
+func (m *MockRepository) GetFilmIds(name string) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmIds", name)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilmIds indicates an expected call of GetFilmIds.
+func (mr *MockRepositoryMockRecorder) GetFilmIds(name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilm", reflect.TypeOf((*MockRepository)(nil).GetFilm), name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmIds", reflect.TypeOf((*MockRepository)(nil).GetFilmIds), name)
 }
 
 // GetFilms mocks base method.
-func (m *MockRepository) GetFilms(params *service.DetailsParams) ([]service.Film, error) {
+func (m *MockRepository) GetFilms(params *service.DetailsParams) (*service.FilmsPage, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetFilms", params)
-	ret0, _ := ret[0].([]service.Film)
+	ret0, _ := ret[0].(*service.FilmsPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -192,60 +430,396 @@ func (mr *MockRepositoryMockRecorder) GetFilms(params interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilms", reflect.TypeOf((*MockRepository)(nil).GetFilms), params)
 }
 
+// GetFilmsByIds mocks base method.
+func (m *MockRepository) GetFilmsByIds(ids []int) ([]service.Film, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmsByIds", ids)
+	ret0, _ := ret[0].([]service.Film)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilmsByIds indicates an expected call of GetFilmsByIds.
+func (mr *MockRepositoryMockRecorder) GetFilmsByIds(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmsByIds", reflect.TypeOf((*MockRepository)(nil).GetFilmsByIds), ids)
+}
+
+// GetGenre mocks base method.
+func (m *MockRepository) GetGenre(id int) (*service.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenre", id)
+	ret0, _ := ret[0].(*service.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenre indicates an expected call of GetGenre.
+func (mr *MockRepositoryMockRecorder) GetGenre(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenre", reflect.TypeOf((*MockRepository)(nil).GetGenre), id)
+}
+
+// GetGenreId mocks base method.
+func (m *MockRepository) GetGenreId(name string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenreId", name)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenreId indicates an expected call of GetGenreId.
+func (mr *MockRepositoryMockRecorder) GetGenreId(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenreId", reflect.TypeOf((*MockRepository)(nil).GetGenreId), name)
+}
+
+// GetGenres mocks base method.
+func (m *MockRepository) GetGenres() ([]service.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenres")
+	ret0, _ := ret[0].([]service.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenres indicates an expected call of GetGenres.
+func (mr *MockRepositoryMockRecorder) GetGenres() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenres", reflect.TypeOf((*MockRepository)(nil).GetGenres))
+}
+
+// GetRatings mocks base method.
+func (m *MockRepository) GetRatings() ([]recommend.Rating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRatings")
+	ret0, _ := ret[0].([]recommend.Rating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRatings indicates an expected call of GetRatings.
+func (mr *MockRepositoryMockRecorder) GetRatings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRatings", reflect.TypeOf((*MockRepository)(nil).GetRatings))
+}
+
+// GetReview mocks base method.
+func (m *MockRepository) GetReview(id int) (*service.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReview", id)
+	ret0, _ := ret[0].(*service.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReview indicates an expected call of GetReview.
+func (mr *MockRepositoryMockRecorder) GetReview(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReview", reflect.TypeOf((*MockRepository)(nil).GetReview), id)
+}
+
+// GetReviews mocks base method.
+func (m *MockRepository) GetReviews(filmId int, params *service.DetailsParams) (*service.ReviewsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews", filmId, params)
+	ret0, _ := ret[0].(*service.ReviewsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockRepositoryMockRecorder) GetReviews(filmId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockRepository)(nil).GetReviews), filmId, params)
+}
+
+// GetRevision mocks base method.
+func (m *MockRepository) GetRevision(entity string, entityId, number int) (*service.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", entity, entityId, number)
+	ret0, _ := ret[0].(*service.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockRepositoryMockRecorder) GetRevision(entity, entityId, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockRepository)(nil).GetRevision), entity, entityId, number)
+}
+
+// GetRevisions mocks base method.
+func (m *MockRepository) GetRevisions(entity string, entityId int, params *service.DetailsParams) (*service.RevisionsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", entity, entityId, params)
+	ret0, _ := ret[0].(*service.RevisionsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockRepositoryMockRecorder) GetRevisions(entity, entityId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockRepository)(nil).GetRevisions), entity, entityId, params)
+}
+
+// GetTrash mocks base method.
+func (m *MockRepository) GetTrash(kind string, params *service.DetailsParams) (*service.TrashPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", kind, params)
+	ret0, _ := ret[0].(*service.TrashPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockRepositoryMockRecorder) GetTrash(kind, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockRepository)(nil).GetTrash), kind, params)
+}
+
+// InTx mocks base method.
+func (m *MockRepository) InTx(ctx context.Context, fn func(service.Repository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTx indicates an expected call of InTx.
+func (mr *MockRepositoryMockRecorder) InTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockRepository)(nil).InTx), ctx, fn)
+}
+
+// Lock mocks base method.
+func (m *MockRepository) Lock(entity string, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", entity, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockRepositoryMockRecorder) Lock(entity, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockRepository)(nil).Lock), entity, id)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockRepository) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockRepositoryMockRecorder) PurgeTrash(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockRepository)(nil).PurgeTrash), ctx, before)
+}
+
+// RelatedFilms mocks base method.
+func (m *MockRepository) RelatedFilms(id, limit int) ([]service.RelatedFilm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelatedFilms", id, limit)
+	ret0, _ := ret[0].([]service.RelatedFilm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RelatedFilms indicates an expected call of RelatedFilms.
+func (mr *MockRepositoryMockRecorder) RelatedFilms(id, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelatedFilms", reflect.TypeOf((*MockRepository)(nil).RelatedFilms), id, limit)
+}
+
+// ReplaceActor mocks base method.
+func (m *MockRepository) ReplaceActor(id int, params *service.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceActor", id, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceActor indicates an expected call of ReplaceActor.
+func (mr *MockRepositoryMockRecorder) ReplaceActor(id, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceActor", reflect.TypeOf((*MockRepository)(nil).ReplaceActor), id, params)
+}
+
+// ReplaceFilm mocks base method.
+func (m *MockRepository) ReplaceFilm(id int, params *service.Film) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceFilm", id, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceFilm indicates an expected call of ReplaceFilm.
+func (mr *MockRepositoryMockRecorder) ReplaceFilm(id, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceFilm", reflect.TypeOf((*MockRepository)(nil).ReplaceFilm), id, params)
+}
+
+// RestoreActor mocks base method.
+func (m *MockRepository) RestoreActor(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreActor", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreActor indicates an expected call of RestoreActor.
+func (mr *MockRepositoryMockRecorder) RestoreActor(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreActor", reflect.TypeOf((*MockRepository)(nil).RestoreActor), id)
+}
+
+// RestoreFilm mocks base method.
+func (m *MockRepository) RestoreFilm(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreFilm", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreFilm indicates an expected call of RestoreFilm.
+func (mr *MockRepositoryMockRecorder) RestoreFilm(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreFilm", reflect.TypeOf((*MockRepository)(nil).RestoreFilm), id)
+}
+
 // SearchActor mocks base method.
-func (m *MockRepository) SearchActor(pattern string) ([]string, error) {
+func (m *MockRepository) SearchActor(params *service.SearchParams) (*service.ActorSearchResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchActor", pattern)
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "SearchActor", params)
+	ret0, _ := ret[0].(*service.ActorSearchResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SearchActor indicates an expected call of SearchActor.
-func (mr *MockRepositoryMockRecorder) SearchActor(pattern interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) SearchActor(params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchActor", reflect.TypeOf((*MockRepository)(nil).SearchActor), pattern)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchActor", reflect.TypeOf((*MockRepository)(nil).SearchActor), params)
 }
 
 // SearchFilms mocks base method.
-func (m *MockRepository) SearchFilms(pattern string) ([]string, error) {
+func (m *MockRepository) SearchFilms(params *service.SearchParams) (*service.FilmSearchResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchFilms", pattern)
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "SearchFilms", params)
+	ret0, _ := ret[0].(*service.FilmSearchResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SearchFilms indicates an expected call of SearchFilms.
-func (mr *MockRepositoryMockRecorder) SearchFilms(pattern interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) SearchFilms(params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchFilms", reflect.TypeOf((*MockRepository)(nil).SearchFilms), pattern)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchFilms", reflect.TypeOf((*MockRepository)(nil).SearchFilms), params)
+}
+
+// SimilarActors mocks base method.
+func (m *MockRepository) SimilarActors(params *service.SearchParams) (*service.ActorSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimilarActors", params)
+	ret0, _ := ret[0].(*service.ActorSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimilarActors indicates an expected call of SimilarActors.
+func (mr *MockRepositoryMockRecorder) SimilarActors(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimilarActors", reflect.TypeOf((*MockRepository)(nil).SimilarActors), params)
+}
+
+// SimilarFilms mocks base method.
+func (m *MockRepository) SimilarFilms(params *service.SearchParams) (*service.FilmSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimilarFilms", params)
+	ret0, _ := ret[0].(*service.FilmSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimilarFilms indicates an expected call of SimilarFilms.
+func (mr *MockRepositoryMockRecorder) SimilarFilms(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimilarFilms", reflect.TypeOf((*MockRepository)(nil).SimilarFilms), params)
 }
 
 // UpdateActor mocks base method.
-func (m *MockRepository) UpdateActor(name string, params *service.Actor) error {
+func (m *MockRepository) UpdateActor(id int, params *service.ActorPatch) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateActor", name, params)
+	ret := m.ctrl.Call(m, "UpdateActor", id, params)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateActor indicates an expected call of UpdateActor.
-func (mr *MockRepositoryMockRecorder) UpdateActor(name, params interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) UpdateActor(id, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockRepository)(nil).UpdateActor), id, params)
+}
+
+// UpdateCredit mocks base method.
+func (m *MockRepository) UpdateCredit(params *service.UpdateCreditParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCredit", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCredit indicates an expected call of UpdateCredit.
+func (mr *MockRepositoryMockRecorder) UpdateCredit(params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockRepository)(nil).UpdateActor), name, params)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCredit", reflect.TypeOf((*MockRepository)(nil).UpdateCredit), params)
 }
 
 // UpdateFilm mocks base method.
-func (m *MockRepository) UpdateFilm(name string, params *service.Film) error {
+func (m *MockRepository) UpdateFilm(id int, params *service.FilmPatch) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateFilm", name, params)
+	ret := m.ctrl.Call(m, "UpdateFilm", id, params)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateFilm indicates an expected call of UpdateFilm.
-func (mr *MockRepositoryMockRecorder) UpdateFilm(name, params interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) UpdateFilm(id, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFilm", reflect.TypeOf((*MockRepository)(nil).UpdateFilm), id, params)
+}
+
+// UpdateGenre mocks base method.
+func (m *MockRepository) UpdateGenre(id int, params *service.Genre) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGenre", id, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGenre indicates an expected call of UpdateGenre.
+func (mr *MockRepositoryMockRecorder) UpdateGenre(id, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGenre", reflect.TypeOf((*MockRepository)(nil).UpdateGenre), id, params)
+}
+
+// UpdateReview mocks base method.
+func (m *MockRepository) UpdateReview(id int, params *service.Review) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReview", id, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReview indicates an expected call of UpdateReview.
+func (mr *MockRepositoryMockRecorder) UpdateReview(id, params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFilm", reflect.TypeOf((*MockRepository)(nil).UpdateFilm), name, params)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReview", reflect.TypeOf((*MockRepository)(nil).UpdateReview), id, params)
 }
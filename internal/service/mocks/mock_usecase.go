@@ -0,0 +1,746 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/usecase.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	recommend "film_library/internal/recommend"
+	service "film_library/internal/service"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUsecase is a mock of Usecase interface.
+type MockUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseMockRecorder
+}
+
+// MockUsecaseMockRecorder is the mock recorder for MockUsecase.
+type MockUsecaseMockRecorder struct {
+	mock *MockUsecase
+}
+
+// NewMockUsecase creates a new mock instance.
+func NewMockUsecase(ctrl *gomock.Controller) *MockUsecase {
+	mock := &MockUsecase{ctrl: ctrl}
+	mock.recorder = &MockUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
+	return m.recorder
+}
+
+// AddActorsByFilm mocks base method.
+func (m *MockUsecase) AddActorsByFilm(ctx context.Context, params *service.AddActorsByFilmParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddActorsByFilm", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddActorsByFilm indicates an expected call of AddActorsByFilm.
+func (mr *MockUsecaseMockRecorder) AddActorsByFilm(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActorsByFilm", reflect.TypeOf((*MockUsecase)(nil).AddActorsByFilm), ctx, params)
+}
+
+// AddCrew mocks base method.
+func (m *MockUsecase) AddCrew(ctx context.Context, params *service.CrewParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCrew", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCrew indicates an expected call of AddCrew.
+func (mr *MockUsecaseMockRecorder) AddCrew(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCrew", reflect.TypeOf((*MockUsecase)(nil).AddCrew), ctx, params)
+}
+
+// AddFilmsByActor mocks base method.
+func (m *MockUsecase) AddFilmsByActor(ctx context.Context, params *service.AddFilmsByActorParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFilmsByActor", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFilmsByActor indicates an expected call of AddFilmsByActor.
+func (mr *MockUsecaseMockRecorder) AddFilmsByActor(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFilmsByActor", reflect.TypeOf((*MockUsecase)(nil).AddFilmsByActor), ctx, params)
+}
+
+// AddGenresByFilm mocks base method.
+func (m *MockUsecase) AddGenresByFilm(ctx context.Context, params *service.AddGenresByFilmParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddGenresByFilm", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddGenresByFilm indicates an expected call of AddGenresByFilm.
+func (mr *MockUsecaseMockRecorder) AddGenresByFilm(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGenresByFilm", reflect.TypeOf((*MockUsecase)(nil).AddGenresByFilm), ctx, params)
+}
+
+// CreateActor mocks base method.
+func (m *MockUsecase) CreateActor(ctx context.Context, params *service.Actor) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateActor", ctx, params)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateActor indicates an expected call of CreateActor.
+func (mr *MockUsecaseMockRecorder) CreateActor(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActor", reflect.TypeOf((*MockUsecase)(nil).CreateActor), ctx, params)
+}
+
+// CreateFilm mocks base method.
+func (m *MockUsecase) CreateFilm(ctx context.Context, params *service.Film) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFilm", ctx, params)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFilm indicates an expected call of CreateFilm.
+func (mr *MockUsecaseMockRecorder) CreateFilm(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFilm", reflect.TypeOf((*MockUsecase)(nil).CreateFilm), ctx, params)
+}
+
+// CreateGenre mocks base method.
+func (m *MockUsecase) CreateGenre(ctx context.Context, params *service.Genre) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGenre", ctx, params)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGenre indicates an expected call of CreateGenre.
+func (mr *MockUsecaseMockRecorder) CreateGenre(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGenre", reflect.TypeOf((*MockUsecase)(nil).CreateGenre), ctx, params)
+}
+
+// CreateReview mocks base method.
+func (m *MockUsecase) CreateReview(ctx context.Context, params *service.Review) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReview", ctx, params)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReview indicates an expected call of CreateReview.
+func (mr *MockUsecaseMockRecorder) CreateReview(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReview", reflect.TypeOf((*MockUsecase)(nil).CreateReview), ctx, params)
+}
+
+// DeleteActor mocks base method.
+func (m *MockUsecase) DeleteActor(ctx context.Context, id, userId, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteActor", ctx, id, userId, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteActor indicates an expected call of DeleteActor.
+func (mr *MockUsecaseMockRecorder) DeleteActor(ctx, id, userId, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActor", reflect.TypeOf((*MockUsecase)(nil).DeleteActor), ctx, id, userId, version)
+}
+
+// DeleteActorFilm mocks base method.
+func (m *MockUsecase) DeleteActorFilm(ctx context.Context, params *service.DeleteActorFilmParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteActorFilm", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteActorFilm indicates an expected call of DeleteActorFilm.
+func (mr *MockUsecaseMockRecorder) DeleteActorFilm(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorFilm", reflect.TypeOf((*MockUsecase)(nil).DeleteActorFilm), ctx, params)
+}
+
+// DeleteCrew mocks base method.
+func (m *MockUsecase) DeleteCrew(ctx context.Context, params *service.CrewParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCrew", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCrew indicates an expected call of DeleteCrew.
+func (mr *MockUsecaseMockRecorder) DeleteCrew(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCrew", reflect.TypeOf((*MockUsecase)(nil).DeleteCrew), ctx, params)
+}
+
+// DeleteFilm mocks base method.
+func (m *MockUsecase) DeleteFilm(ctx context.Context, id, userId, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFilm", ctx, id, userId, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFilm indicates an expected call of DeleteFilm.
+func (mr *MockUsecaseMockRecorder) DeleteFilm(ctx, id, userId, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFilm", reflect.TypeOf((*MockUsecase)(nil).DeleteFilm), ctx, id, userId, version)
+}
+
+// DeleteFilmGenre mocks base method.
+func (m *MockUsecase) DeleteFilmGenre(ctx context.Context, params *service.DeleteFilmGenreParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFilmGenre", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFilmGenre indicates an expected call of DeleteFilmGenre.
+func (mr *MockUsecaseMockRecorder) DeleteFilmGenre(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFilmGenre", reflect.TypeOf((*MockUsecase)(nil).DeleteFilmGenre), ctx, params)
+}
+
+// DeleteGenre mocks base method.
+func (m *MockUsecase) DeleteGenre(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGenre", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGenre indicates an expected call of DeleteGenre.
+func (mr *MockUsecaseMockRecorder) DeleteGenre(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGenre", reflect.TypeOf((*MockUsecase)(nil).DeleteGenre), ctx, id)
+}
+
+// DeleteReview mocks base method.
+func (m *MockUsecase) DeleteReview(ctx context.Context, id, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", ctx, id, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockUsecaseMockRecorder) DeleteReview(ctx, id, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockUsecase)(nil).DeleteReview), ctx, id, userId)
+}
+
+// DiffRevisions mocks base method.
+func (m *MockUsecase) DiffRevisions(entity string, id, from, to int) (*service.RevisionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevisions", entity, id, from, to)
+	ret0, _ := ret[0].(*service.RevisionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockUsecaseMockRecorder) DiffRevisions(entity, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockUsecase)(nil).DiffRevisions), entity, id, from, to)
+}
+
+// GetActor mocks base method.
+func (m *MockUsecase) GetActor(id int) (*service.Actor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActor", id)
+	ret0, _ := ret[0].(*service.Actor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActor indicates an expected call of GetActor.
+func (mr *MockUsecaseMockRecorder) GetActor(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActor", reflect.TypeOf((*MockUsecase)(nil).GetActor), id)
+}
+
+// GetActorFilms mocks base method.
+func (m *MockUsecase) GetActorFilms(id int, params *service.DetailsParams) (*service.FilmsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActorFilms", id, params)
+	ret0, _ := ret[0].(*service.FilmsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorFilms indicates an expected call of GetActorFilms.
+func (mr *MockUsecaseMockRecorder) GetActorFilms(id, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorFilms", reflect.TypeOf((*MockUsecase)(nil).GetActorFilms), id, params)
+}
+
+// GetActorId mocks base method.
+func (m *MockUsecase) GetActorId(name string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActorId", name)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorId indicates an expected call of GetActorId.
+func (mr *MockUsecaseMockRecorder) GetActorId(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorId", reflect.TypeOf((*MockUsecase)(nil).GetActorId), name)
+}
+
+// GetActorPath mocks base method.
+func (m *MockUsecase) GetActorPath(ctx context.Context, params *service.PathParams) (*service.ActorPath, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActorPath", ctx, params)
+	ret0, _ := ret[0].(*service.ActorPath)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorPath indicates an expected call of GetActorPath.
+func (mr *MockUsecaseMockRecorder) GetActorPath(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorPath", reflect.TypeOf((*MockUsecase)(nil).GetActorPath), ctx, params)
+}
+
+// GetActors mocks base method.
+func (m *MockUsecase) GetActors(params *service.DetailsParams) (*service.ActorsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActors", params)
+	ret0, _ := ret[0].(*service.ActorsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActors indicates an expected call of GetActors.
+func (mr *MockUsecaseMockRecorder) GetActors(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockUsecase)(nil).GetActors), params)
+}
+
+// GetAudit mocks base method.
+func (m *MockUsecase) GetAudit(filter *service.AuditFilter, params *service.DetailsParams) (*service.AuditPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAudit", filter, params)
+	ret0, _ := ret[0].(*service.AuditPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAudit indicates an expected call of GetAudit.
+func (mr *MockUsecaseMockRecorder) GetAudit(filter, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAudit", reflect.TypeOf((*MockUsecase)(nil).GetAudit), filter, params)
+}
+
+// GetFilm mocks base method.
+func (m *MockUsecase) GetFilm(id int) (*service.Film, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilm", id)
+	ret0, _ := ret[0].(*service.Film)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilm indicates an expected call of GetFilm.
+func (mr *MockUsecaseMockRecorder) GetFilm(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilm", reflect.TypeOf((*MockUsecase)(nil).GetFilm), id)
+}
+
+// GetFilmActors mocks base method.
+func (m *MockUsecase) GetFilmActors(id int, params *service.DetailsParams) (*service.ActorsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmActors", id, params)
+	ret0, _ := ret[0].(*service.ActorsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilmActors indicates an expected call of GetFilmActors.
+func (mr *MockUsecaseMockRecorder) GetFilmActors(id, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmActors", reflect.TypeOf((*MockUsecase)(nil).GetFilmActors), id, params)
+}
+
+// GetFilmId mocks base method.
+func (m *MockUsecase) GetFilmId(name string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmId", name)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilmId indicates an expected call of GetFilmId.
+func (mr *MockUsecaseMockRecorder) GetFilmId(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmId", reflect.TypeOf((*MockUsecase)(nil).GetFilmId), name)
+}
+
+// GetFilms mocks base method.
+func (m *MockUsecase) GetFilms(params *service.DetailsParams) (*service.FilmsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilms", params)
+	ret0, _ := ret[0].(*service.FilmsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilms indicates an expected call of GetFilms.
+func (mr *MockUsecaseMockRecorder) GetFilms(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilms", reflect.TypeOf((*MockUsecase)(nil).GetFilms), params)
+}
+
+// GetGenre mocks base method.
+func (m *MockUsecase) GetGenre(id int) (*service.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenre", id)
+	ret0, _ := ret[0].(*service.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenre indicates an expected call of GetGenre.
+func (mr *MockUsecaseMockRecorder) GetGenre(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenre", reflect.TypeOf((*MockUsecase)(nil).GetGenre), id)
+}
+
+// GetGenreId mocks base method.
+func (m *MockUsecase) GetGenreId(name string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenreId", name)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenreId indicates an expected call of GetGenreId.
+func (mr *MockUsecaseMockRecorder) GetGenreId(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenreId", reflect.TypeOf((*MockUsecase)(nil).GetGenreId), name)
+}
+
+// GetGenres mocks base method.
+func (m *MockUsecase) GetGenres() ([]service.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenres")
+	ret0, _ := ret[0].([]service.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenres indicates an expected call of GetGenres.
+func (mr *MockUsecaseMockRecorder) GetGenres() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenres", reflect.TypeOf((*MockUsecase)(nil).GetGenres))
+}
+
+// GetRecommendations mocks base method.
+func (m *MockUsecase) GetRecommendations(userId, limit int) ([]service.Recommendation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendations", userId, limit)
+	ret0, _ := ret[0].([]service.Recommendation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendations indicates an expected call of GetRecommendations.
+func (mr *MockUsecaseMockRecorder) GetRecommendations(userId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendations", reflect.TypeOf((*MockUsecase)(nil).GetRecommendations), userId, limit)
+}
+
+// GetRelatedFilms mocks base method.
+func (m *MockUsecase) GetRelatedFilms(id, limit int) ([]service.RelatedFilm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRelatedFilms", id, limit)
+	ret0, _ := ret[0].([]service.RelatedFilm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRelatedFilms indicates an expected call of GetRelatedFilms.
+func (mr *MockUsecaseMockRecorder) GetRelatedFilms(id, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelatedFilms", reflect.TypeOf((*MockUsecase)(nil).GetRelatedFilms), id, limit)
+}
+
+// GetReviews mocks base method.
+func (m *MockUsecase) GetReviews(filmId int, params *service.DetailsParams) (*service.ReviewsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews", filmId, params)
+	ret0, _ := ret[0].(*service.ReviewsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockUsecaseMockRecorder) GetReviews(filmId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockUsecase)(nil).GetReviews), filmId, params)
+}
+
+// GetRevisions mocks base method.
+func (m *MockUsecase) GetRevisions(entity string, id int, params *service.DetailsParams) (*service.RevisionsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", entity, id, params)
+	ret0, _ := ret[0].(*service.RevisionsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockUsecaseMockRecorder) GetRevisions(entity, id, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockUsecase)(nil).GetRevisions), entity, id, params)
+}
+
+// GetTrash mocks base method.
+func (m *MockUsecase) GetTrash(kind string, params *service.DetailsParams) (*service.TrashPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", kind, params)
+	ret0, _ := ret[0].(*service.TrashPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockUsecaseMockRecorder) GetTrash(kind, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockUsecase)(nil).GetTrash), kind, params)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockUsecase) PurgeTrash(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockUsecaseMockRecorder) PurgeTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockUsecase)(nil).PurgeTrash), ctx)
+}
+
+// RestoreActor mocks base method.
+func (m *MockUsecase) RestoreActor(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreActor", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreActor indicates an expected call of RestoreActor.
+func (mr *MockUsecaseMockRecorder) RestoreActor(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreActor", reflect.TypeOf((*MockUsecase)(nil).RestoreActor), ctx, id)
+}
+
+// RestoreFilm mocks base method.
+func (m *MockUsecase) RestoreFilm(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreFilm", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreFilm indicates an expected call of RestoreFilm.
+func (mr *MockUsecaseMockRecorder) RestoreFilm(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreFilm", reflect.TypeOf((*MockUsecase)(nil).RestoreFilm), ctx, id)
+}
+
+// RevertActor mocks base method.
+func (m *MockUsecase) RevertActor(ctx context.Context, id, number, version int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertActor", ctx, id, number, version)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertActor indicates an expected call of RevertActor.
+func (mr *MockUsecaseMockRecorder) RevertActor(ctx, id, number, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertActor", reflect.TypeOf((*MockUsecase)(nil).RevertActor), ctx, id, number, version)
+}
+
+// RevertFilm mocks base method.
+func (m *MockUsecase) RevertFilm(ctx context.Context, id, number, version int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertFilm", ctx, id, number, version)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertFilm indicates an expected call of RevertFilm.
+func (mr *MockUsecaseMockRecorder) RevertFilm(ctx, id, number, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertFilm", reflect.TypeOf((*MockUsecase)(nil).RevertFilm), ctx, id, number, version)
+}
+
+// SearchActor mocks base method.
+func (m *MockUsecase) SearchActor(params *service.SearchParams) (*service.ActorSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchActor", params)
+	ret0, _ := ret[0].(*service.ActorSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchActor indicates an expected call of SearchActor.
+func (mr *MockUsecaseMockRecorder) SearchActor(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchActor", reflect.TypeOf((*MockUsecase)(nil).SearchActor), params)
+}
+
+// SearchFilms mocks base method.
+func (m *MockUsecase) SearchFilms(params *service.SearchParams) (*service.FilmSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchFilms", params)
+	ret0, _ := ret[0].(*service.FilmSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchFilms indicates an expected call of SearchFilms.
+func (mr *MockUsecaseMockRecorder) SearchFilms(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchFilms", reflect.TypeOf((*MockUsecase)(nil).SearchFilms), params)
+}
+
+// UpdateActor mocks base method.
+func (m *MockUsecase) UpdateActor(ctx context.Context, id int, params *service.ActorPatch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateActor", ctx, id, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateActor indicates an expected call of UpdateActor.
+func (mr *MockUsecaseMockRecorder) UpdateActor(ctx, id, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockUsecase)(nil).UpdateActor), ctx, id, params)
+}
+
+// UpdateCredit mocks base method.
+func (m *MockUsecase) UpdateCredit(ctx context.Context, params *service.UpdateCreditParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCredit", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCredit indicates an expected call of UpdateCredit.
+func (mr *MockUsecaseMockRecorder) UpdateCredit(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCredit", reflect.TypeOf((*MockUsecase)(nil).UpdateCredit), ctx, params)
+}
+
+// UpdateFilm mocks base method.
+func (m *MockUsecase) UpdateFilm(ctx context.Context, id int, params *service.FilmPatch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFilm", ctx, id, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFilm indicates an expected call of UpdateFilm.
+func (mr *MockUsecaseMockRecorder) UpdateFilm(ctx, id, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFilm", reflect.TypeOf((*MockUsecase)(nil).UpdateFilm), ctx, id, params)
+}
+
+// UpdateGenre mocks base method.
+func (m *MockUsecase) UpdateGenre(ctx context.Context, id int, params *service.Genre) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGenre", ctx, id, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGenre indicates an expected call of UpdateGenre.
+func (mr *MockUsecaseMockRecorder) UpdateGenre(ctx, id, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGenre", reflect.TypeOf((*MockUsecase)(nil).UpdateGenre), ctx, id, params)
+}
+
+// UpdateReview mocks base method.
+func (m *MockUsecase) UpdateReview(ctx context.Context, id int, params *service.Review) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReview", ctx, id, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReview indicates an expected call of UpdateReview.
+func (mr *MockUsecaseMockRecorder) UpdateReview(ctx, id, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReview", reflect.TypeOf((*MockUsecase)(nil).UpdateReview), ctx, id, params)
+}
+
+// MockRecommender is a mock of Recommender interface.
+type MockRecommender struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecommenderMockRecorder
+}
+
+// MockRecommenderMockRecorder is the mock recorder for MockRecommender.
+type MockRecommenderMockRecorder struct {
+	mock *MockRecommender
+}
+
+// NewMockRecommender creates a new mock instance.
+func NewMockRecommender(ctrl *gomock.Controller) *MockRecommender {
+	mock := &MockRecommender{ctrl: ctrl}
+	mock.recorder = &MockRecommenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecommender) EXPECT() *MockRecommenderMockRecorder {
+	return m.recorder
+}
+
+// Recommend mocks base method.
+func (m *MockRecommender) Recommend(userId, limit int) []recommend.Prediction {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recommend", userId, limit)
+	ret0, _ := ret[0].([]recommend.Prediction)
+	return ret0
+}
+
+// Recommend indicates an expected call of Recommend.
+func (mr *MockRecommenderMockRecorder) Recommend(userId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recommend", reflect.TypeOf((*MockRecommender)(nil).Recommend), userId, limit)
+}
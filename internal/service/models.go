@@ -1,22 +1,109 @@
 package service
 
 import (
-	"database/sql"
+	"github.com/jackc/pgx/pgtype"
+	"time"
 )
 
+// Actor is a person. Actors and crew members share one table, so the actor
+// and person endpoints see the same people: Films lists the films a person
+// acted in and Crew the films they worked on in any other job.
 type Actor struct {
-	Name  string         `json:"name" db:"actor_name"`
-	Sex   string         `json:"sex" db:"sex"`
-	BDate string         `json:"bdate" db:"bdate"`
-	Films sql.NullString `json:"films" db:"list_film"`
+	Id    int          `json:"id" db:"id"`
+	Name  string       `json:"name" db:"person_name"`
+	Sex   string       `json:"sex" db:"sex"`
+	BDate string       `json:"bdate" db:"bdate"`
+	Films StringArray  `json:"films" db:"films"`
+	Crew  []CrewCredit `json:"crew,omitempty" db:"-"`
+
+	// Version grows with every change of the actor. It is read by GetActor only,
+	// ActorPatch.Version makes an update conditional on it.
+	Version int `json:"version,omitempty" db:"version"`
+
+	// FilmDetails are the films as full objects, set on request (?expand=films).
+	FilmDetails []Film `json:"film_details,omitempty" db:"-"`
 }
 
+type Person = Actor
+
 type Film struct {
-	Name   string         `json:"name" db:"film_name"`
-	RDate  string         `json:"rdate" db:"release_date"`
-	Rating float32        `json:"rating" db:"rating"`
-	Desc   string         `json:"desc" db:"description"`
-	Actors sql.NullString `json:"actors" db:"list_actor"`
+	Id     int          `json:"id" db:"id"`
+	Name   string       `json:"name" db:"film_name"`
+	RDate  string       `json:"rdate" db:"release_date"`
+	Rating float32      `json:"rating" db:"rating"`
+	Desc   string       `json:"desc" db:"description"`
+	Actors StringArray  `json:"actors" db:"actors"`
+	Genres StringArray  `json:"genres" db:"genres"`
+	Cast   []Credit     `json:"cast,omitempty" db:"-"`
+	Crew   []CrewCredit `json:"crew,omitempty" db:"-"`
+
+	// UserRating aggregates the ratings of users; Rating stays the editorial one.
+	UserRating *RatingSummary `json:"user_rating,omitempty" db:"-"`
+	// Version grows with every change of the film, see Actor.Version.
+	Version int `json:"version,omitempty" db:"version"`
+	// ActorDetails are the actors as full objects, set on request (?expand=actors).
+	ActorDetails []Actor `json:"actor_details,omitempty" db:"-"`
+}
+
+// Credit is an actor's part in a film. Cast lists are sorted by Order;
+// credits without one come last.
+type Credit struct {
+	ActorId   int    `json:"actor_id" db:"actor_id"`
+	Actor     string `json:"actor" db:"person_name"`
+	Character string `json:"character" db:"character_name"`
+	Type      string `json:"type" db:"credit_type"`
+	Order     *int   `json:"order" db:"billing_order"`
+}
+
+// CrewCredit is a job of a person in a film. A film lists its crew by person,
+// a person lists their credits by film, so only one side is set.
+type CrewCredit struct {
+	FilmId   int    `json:"film_id,omitempty" db:"film_id"`
+	Film     string `json:"film,omitempty" db:"film_name"`
+	PersonId int    `json:"person_id,omitempty" db:"person_id"`
+	Person   string `json:"person,omitempty" db:"person_name"`
+	Job      string `json:"job" db:"job"`
+}
+
+// Genre is a film genre; Films is the number of films it is assigned to.
+type Genre struct {
+	Id    int    `json:"id" db:"id"`
+	Name  string `json:"name" db:"genre_name"`
+	Films int    `json:"films" db:"films"`
+}
+
+// Review is a rating of a film by a user, optionally with a text.
+// Every user has at most one review per film.
+type Review struct {
+	Id        int       `json:"id" db:"id"`
+	FilmId    int       `json:"film_id" db:"film_id"`
+	UserId    int       `json:"user_id" db:"user_id"`
+	Login     string    `json:"login" db:"login"`
+	Rating    int       `json:"rating" db:"rating"`
+	Text      string    `json:"text" db:"review_text"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// RatingSummary aggregates the user ratings of a film. Mean is null while
+// nobody has rated the film; Distribution maps every rating to its count.
+type RatingSummary struct {
+	Mean         *float64    `json:"mean"`
+	Count        int         `json:"count"`
+	Distribution map[int]int `json:"distribution"`
+}
+
+// StringArray is a list of names aggregated from a relation (actor_film, film_genre).
+// It scans a postgres text[] column and is encoded as a plain JSON array.
+type StringArray []string
+
+func (a *StringArray) Scan(src any) error {
+	var arr pgtype.TextArray
+	if err := arr.Scan(src); err != nil {
+		return err
+	}
+
+	return arr.AssignTo((*[]string)(a))
 }
 
 type FilmName struct {
@@ -24,25 +111,230 @@ type FilmName struct {
 }
 
 type DetailsParams struct {
-	Sort string `json:"sort"`
+	Sort   string      `json:"sort"`
+	Order  string      `json:"order"`
+	Limit  int         `json:"limit"`
+	Cursor *Cursor     `json:"cursor"`
+	Filter *FilmFilter `json:"filter"`
+	Job    string      `json:"job"`     // people list: only people with this crew job
+	FilmId int         `json:"film_id"` // people list: only the cast of this film
+}
+
+// FilmFilter narrows the film list; every field that is set must match.
+type FilmFilter struct {
+	ReleasedFrom string   `json:"released_from"`
+	ReleasedTo   string   `json:"released_to"`
+	RatingFrom   *float32 `json:"rating_from"`
+	RatingTo     *float32 `json:"rating_to"`
+	ActorIds     []int    `json:"actor_ids"`
+	Actors       []string `json:"actors"`
+	GenreIds     []int    `json:"genre_ids"`
+	Genres       []string `json:"genres"`
+	Keyword      string   `json:"keyword"`
+}
+
+type ActorsPage struct {
+	Items      []Actor `json:"items"`
+	NextCursor string  `json:"next_cursor"`
+	Total      int     `json:"total"`
+}
+
+type FilmsPage struct {
+	Items      []Film `json:"items"`
+	NextCursor string `json:"next_cursor"`
+	Total      int    `json:"total"`
+}
+
+type ReviewsPage struct {
+	Items      []Review `json:"items"`
+	NextCursor string   `json:"next_cursor"`
+	Total      int      `json:"total"`
+}
+
+// TrashItem is a deleted actor or film. DeletedBy is null once the user who
+// deleted it is gone.
+type TrashItem struct {
+	Id        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	DeletedAt time.Time `json:"deleted_at" db:"deleted_at"`
+	DeletedBy *int      `json:"deleted_by" db:"deleted_by"`
+}
+
+type TrashPage struct {
+	Items      []TrashItem `json:"items"`
+	NextCursor string      `json:"next_cursor"`
+	Total      int         `json:"total"`
+}
+
+// Revision is the state of the editable fields of an actor or a film after a
+// change, numbered from 1 per entity. Data has the JSON names of the entity.
+type Revision struct {
+	Entity    string    `json:"entity" db:"entity"`
+	EntityId  int       `json:"entity_id" db:"entity_id"`
+	Number    int       `json:"number" db:"number"`
+	UserId    *int      `json:"user_id" db:"user_id"`
+	Data      RawJSON   `json:"data" db:"data"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type RevisionsPage struct {
+	Items      []Revision `json:"items"`
+	NextCursor string     `json:"next_cursor"`
+	Total      int        `json:"total"`
+}
+
+// RevisionDiff lists the fields that differ between two revisions.
+type RevisionDiff struct {
+	From    int                    `json:"from"`
+	To      int                    `json:"to"`
+	Changes map[string]FieldChange `json:"changes"`
 }
 
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+type SearchParams struct {
+	Query     string  `json:"query"`
+	Mode      string  `json:"mode"`
+	Language  string  `json:"language"`
+	Threshold float32 `json:"threshold"`
+	Limit     int     `json:"limit"`
+}
+
+// ActorHit and FilmHit are search results: the entity itself, its relevance
+// and a fragment of the matched text with the hits wrapped in <b></b>.
+// For fuzzy search the rank is the similarity [0;1] and there is no headline.
+
+type ActorHit struct {
+	Actor
+	Rank     float32 `json:"rank" db:"rank"`
+	Headline string  `json:"headline,omitempty" db:"headline"`
+}
+
+type FilmHit struct {
+	Film
+	Rank     float32 `json:"rank" db:"rank"`
+	Headline string  `json:"headline,omitempty" db:"headline"`
+}
+
+// RelatedFilm is a film similar to another one. Score ranks related films:
+// shared actors weigh most, then shared genres, then how close the ratings are.
+type RelatedFilm struct {
+	Film
+	SharedCast   int     `json:"shared_cast" db:"shared_cast"`
+	SharedGenres int     `json:"shared_genres" db:"shared_genres"`
+	Score        float64 `json:"score" db:"score"`
+}
+
+// Recommendation is a film the user has not rated with the rating they are expected to give it.
+type Recommendation struct {
+	Film
+	PredictedRating float64 `json:"predicted_rating"`
+}
+
+// PathParams ask for the shortest chain of shared films between two actors
+// that is at most MaxDepth films long.
+type PathParams struct {
+	From     int `json:"from"`
+	To       int `json:"to"`
+	MaxDepth int `json:"max_depth"`
+}
+
+// ActorLink says that an actor played in a film together with a co-actor.
+type ActorLink struct {
+	ActorId   int `db:"actor_id"`
+	FilmId    int `db:"film_id"`
+	CoActorId int `db:"co_actor_id"`
+}
+
+// ActorPath is a chain actor -> film -> actor ... from one actor to another.
+// Every link but the last names the film shared with the next actor.
+type ActorPath struct {
+	Degrees int        `json:"degrees"`
+	Chain   []PathLink `json:"chain"`
+}
+
+type PathLink struct {
+	ActorId int    `json:"actor_id"`
+	Actor   string `json:"actor"`
+	FilmId  int    `json:"film_id,omitempty"`
+	Film    string `json:"film,omitempty"`
+}
+
+// DidYouMean is the closest name to the query, set when full-text search finds nothing.
+
+type ActorSearchResult struct {
+	Items      []ActorHit `json:"items"`
+	DidYouMean string     `json:"did_you_mean,omitempty"`
+}
+
+type FilmSearchResult struct {
+	Items      []FilmHit `json:"items"`
+	DidYouMean string    `json:"did_you_mean,omitempty"`
+}
+
+// Relation params address actors and films either by id or by name;
+// names are resolved to ids and must match exactly one entry.
+
 type AddFilmsByActorParams struct {
-	Actor string   `json:"actor"`
-	Films []string `json:"films"`
+	ActorId int      `json:"actor_id"`
+	Actor   string   `json:"actor"`
+	FilmIds []int    `json:"film_ids"`
+	Films   []string `json:"films"`
 }
 
 type AddActorsByFilmParams struct {
-	Film   string   `json:"film"`
-	Actors []string `json:"actors"`
+	FilmId   int      `json:"film_id"`
+	Film     string   `json:"film"`
+	ActorIds []int    `json:"actor_ids"`
+	Actors   []string `json:"actors"`
 }
 
 type DeleteActorFilmParams struct {
-	Film  string `json:"film"`
-	Actor string `json:"actor"`
+	FilmId  int    `json:"film_id"`
+	Film    string `json:"film"`
+	ActorId int    `json:"actor_id"`
+	Actor   string `json:"actor"`
+}
+
+// UpdateCreditParams changes the credit of an actor in a film; only the set fields are changed.
+type UpdateCreditParams struct {
+	FilmId    int     `json:"film_id"`
+	Film      string  `json:"film"`
+	ActorId   int     `json:"actor_id"`
+	Actor     string  `json:"actor"`
+	Character *string `json:"character"`
+	Type      *string `json:"type"`
+	Order     *int    `json:"order"`
+}
+
+// CrewParams address a job of a person in a film; person and film are set by id or name.
+type CrewParams struct {
+	FilmId   int    `json:"film_id"`
+	Film     string `json:"film"`
+	PersonId int    `json:"person_id"`
+	Person   string `json:"person"`
+	Job      string `json:"job"`
+}
+
+type AddGenresByFilmParams struct {
+	FilmId   int      `json:"film_id"`
+	Film     string   `json:"film"`
+	GenreIds []int    `json:"genre_ids"`
+	Genres   []string `json:"genres"`
+}
+
+type DeleteFilmGenreParams struct {
+	FilmId  int    `json:"film_id"`
+	Film    string `json:"film"`
+	GenreId int    `json:"genre_id"`
+	Genre   string `json:"genre"`
 }
 
 type ResponseModel struct {
 	Status string `json:"status"`
 	Error  string `json:"error"`
+	Id     int    `json:"id,omitempty"`
 }
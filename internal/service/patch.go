@@ -0,0 +1,58 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// PatchField is a member of a JSON Merge Patch (RFC 7396). Set tells that the
+// member was in the patch at all and Null that it was null, which clears the
+// field; a member missing from the patch leaves the field as it is.
+type PatchField[T any] struct {
+	Value T
+	Set   bool
+	Null  bool
+}
+
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if bytes.Equal(data, []byte("null")) {
+		f.Null = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &f.Value)
+}
+
+// ActorPatch is a partial update of an actor. The change is made only while
+// the actor has Version, 0 makes it unconditional.
+type ActorPatch struct {
+	Name    PatchField[string] `json:"name"`
+	Sex     PatchField[string] `json:"sex"`
+	BDate   PatchField[string] `json:"bdate"`
+	Version int                `json:"-"`
+}
+
+// Empty tells that the patch changes nothing.
+func (p *ActorPatch) Empty() bool {
+	return !p.Name.Set && !p.Sex.Set && !p.BDate.Set
+}
+
+// FilmPatch is a partial update of a film, see ActorPatch.
+type FilmPatch struct {
+	Name    PatchField[string]  `json:"name"`
+	RDate   PatchField[string]  `json:"rdate"`
+	Rating  PatchField[float32] `json:"rating"`
+	Desc    PatchField[string]  `json:"desc"`
+	Version int                 `json:"-"`
+}
+
+// Empty tells that the patch changes nothing.
+func (p *FilmPatch) Empty() bool {
+	return !p.Name.Set && !p.RDate.Set && !p.Rating.Set && !p.Desc.Set
+}
+
+// Patched makes a patch member setting the field to v, for building patches in code.
+func Patched[T any](v T) PatchField[T] {
+	return PatchField[T]{Value: v, Set: true}
+}
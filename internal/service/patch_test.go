@@ -0,0 +1,51 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFilmPatch(t *testing.T) {
+	var patch FilmPatch
+	require.NoError(t, json.Unmarshal([]byte(`{"rating": 8.1, "desc": null, "version": 2}`), &patch))
+	require.Equal(t, FilmPatch{Rating: Patched[float32](8.1), Desc: PatchField[string]{Set: true, Null: true}}, patch)
+	require.NoError(t, ValidateFilmPatch(&patch))
+
+	cases := []struct {
+		name   string
+		in     string
+		expErr string
+	}{
+		{name: "Empty", in: `{}`, expErr: "patch should set at least one of name, rdate, rating, desc"},
+		{name: "Null", in: `null`, expErr: "patch should set at least one of name, rdate, rating, desc"},
+		{name: "ReadOnly", in: `{"id": 3, "actors": ["Kenneth Branagh"]}`, expErr: "patch should set at least one of name, rdate, rating, desc"},
+		{name: "ClearName", in: `{"name": null}`, expErr: "name can't be cleared"},
+		{name: "ClearRating", in: `{"rating": null}`, expErr: "rating can't be cleared"},
+		{name: "EmptyName", in: `{"name": "  "}`, expErr: "size Name should be [1;150]"},
+		{name: "Rating", in: `{"rating": 0}`, expErr: "rating should be (0;10]"},
+		{name: "RDate", in: `{"rdate": "25.12.1996"}`, expErr: "rdate should be '2000-01-01' format"},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			var patch FilmPatch
+			require.NoError(t, json.Unmarshal([]byte(tCase.in), &patch))
+			require.EqualError(t, ValidateFilmPatch(&patch), tCase.expErr)
+		})
+	}
+}
+
+func TestActorPatch(t *testing.T) {
+	var patch ActorPatch
+	require.NoError(t, json.Unmarshal([]byte(`{"name": " Kenneth Branagh "}`), &patch))
+	require.NoError(t, ValidateActorPatch(&patch))
+	require.Equal(t, ActorPatch{Name: Patched("Kenneth Branagh")}, patch)
+
+	patch = ActorPatch{}
+	require.NoError(t, json.Unmarshal([]byte(`{"sex": null}`), &patch))
+	require.EqualError(t, ValidateActorPatch(&patch), "sex can't be cleared")
+
+	patch = ActorPatch{Sex: Patched("x")}
+	require.EqualError(t, ValidateActorPatch(&patch), "sex should be 'm' - male or 'f' - famale")
+}
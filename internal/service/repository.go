@@ -1,21 +1,76 @@
 package service
 
+import (
+	"context"
+	"film_library/internal/recommend"
+	"time"
+)
+
 type Repository interface {
-	CreateActor(params *Actor) error
-	GetActor(name string) (*Actor, error)
-	GetActors(params *DetailsParams) ([]Actor, error)
-	DeleteActor(name string) error
-	UpdateActor(name string, params *Actor) error
-	SearchActor(pattern string) ([]string, error)
-
-	CreateFilm(params *Film) error
-	GetFilm(name string) (*Film, error)
-	GetFilms(params *DetailsParams) ([]Film, error)
-	DeleteFilm(name string) error
-	UpdateFilm(name string, params *Film) error
-	SearchFilms(pattern string) ([]string, error)
+	// InTx runs fn with a repository bound to one transaction; the changes fn
+	// makes through it are committed together when fn returns nil.
+	InTx(ctx context.Context, fn func(repo Repository) error) error
+	// Lock locks the row of an actor, film, genre or review until the
+	// transaction ends; a missing row is no error.
+	Lock(entity string, id int) error
+
+	CreateActor(params *Actor) (int, error)
+	GetActor(id int) (*Actor, error)
+	GetActorIds(name string) ([]int, error)
+	GetActors(params *DetailsParams) (*ActorsPage, error)
+	DeleteActor(id, userId, version int) error
+	RestoreActor(id int) error
+	UpdateActor(id int, params *ActorPatch) error
+	ReplaceActor(id int, params *Actor) error
+	SearchActor(params *SearchParams) (*ActorSearchResult, error)
+	SimilarActors(params *SearchParams) (*ActorSearchResult, error)
+	GetActorsByIds(ids []int) ([]Actor, error)
+	ActorLinks(ctx context.Context, actorIds []int) ([]ActorLink, error)
+
+	CreateFilm(params *Film) (int, error)
+	GetFilm(id int) (*Film, error)
+	GetFilmIds(name string) ([]int, error)
+	GetFilms(params *DetailsParams) (*FilmsPage, error)
+	DeleteFilm(id, userId, version int) error
+	RestoreFilm(id int) error
+	UpdateFilm(id int, params *FilmPatch) error
+	ReplaceFilm(id int, params *Film) error
+	SearchFilms(params *SearchParams) (*FilmSearchResult, error)
+	SimilarFilms(params *SearchParams) (*FilmSearchResult, error)
+	GetFilmsByIds(ids []int) ([]Film, error)
+	RelatedFilms(id, limit int) ([]RelatedFilm, error)
+
+	CreateGenre(params *Genre) (int, error)
+	GetGenre(id int) (*Genre, error)
+	GetGenreId(name string) (int, error)
+	GetGenres() ([]Genre, error)
+	DeleteGenre(id int) error
+	UpdateGenre(id int, params *Genre) error
+
+	CreateReview(params *Review) (int, error)
+	GetReviews(filmId int, params *DetailsParams) (*ReviewsPage, error)
+	UpdateReview(id int, params *Review) error
+	GetReview(id int) (*Review, error)
+	DeleteReview(id, userId int) error
+	GetRatings() ([]recommend.Rating, error)
 
 	AddFilmsByActor(params *AddFilmsByActorParams) error
 	AddActorsByFilm(params *AddActorsByFilmParams) error
 	DeleteActorFilm(params *DeleteActorFilmParams) error
+	GetCredit(actorId, filmId int) (*Credit, error)
+	UpdateCredit(params *UpdateCreditParams) error
+	AddCrew(params *CrewParams) error
+	DeleteCrew(params *CrewParams) error
+	AddGenresByFilm(params *AddGenresByFilmParams) error
+	DeleteFilmGenre(params *DeleteFilmGenreParams) error
+
+	GetTrash(kind string, params *DetailsParams) (*TrashPage, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int, error)
+
+	AddRevision(ctx context.Context, rev *Revision, baseline RawJSON) (int, error)
+	GetRevisions(entity string, entityId int, params *DetailsParams) (*RevisionsPage, error)
+	GetRevision(entity string, entityId, number int) (*Revision, error)
+
+	AddAudit(ctx context.Context, entry *AuditEntry) error
+	GetAudit(filter *AuditFilter, params *DetailsParams) (*AuditPage, error)
 }
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"film_library/internal/service"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sortColumn describes a column a list can be ordered by and paginated on.
+// A column that may be NULL is ordered by a COALESCE, so every row has a key.
+type sortColumn[T any] struct {
+	expr string               // SQL expression the rows are ordered by
+	cast string               // SQL type the cursor key is cast back to
+	desc bool                 // default direction
+	key  func(item *T) string // value of expr for an item, stored in the cursor
+}
+
+var actorSortColumns = map[string]sortColumn[service.Actor]{
+	"actor_name": {expr: "a.person_name", cast: "varchar", key: func(a *service.Actor) string { return a.Name }},
+	"sex":        {expr: "COALESCE(a.sex, '')", cast: "varchar", key: func(a *service.Actor) string { return a.Sex }},
+	"bdate": {expr: "COALESCE(a.bdate, '-infinity')", cast: "date", key: func(a *service.Actor) string {
+		if a.BDate == "" {
+			return "-infinity"
+		}
+		return a.BDate
+	}},
+}
+
+var filmSortColumns = map[string]sortColumn[service.Film]{
+	"film_name":    {expr: "f.film_name", cast: "varchar", key: func(f *service.Film) string { return f.Name }},
+	"release_date": {expr: "f.release_date", cast: "date", key: func(f *service.Film) string { return f.RDate }},
+	"rating": {expr: "COALESCE(f.rating, 0)", cast: "real", desc: true, key: func(f *service.Film) string {
+		return strconv.FormatFloat(float64(f.Rating), 'f', -1, 32)
+	}},
+	"description": {expr: "COALESCE(f.description, '')", cast: "varchar", key: func(f *service.Film) string { return f.Desc }},
+}
+
+var reviewSortColumns = map[string]sortColumn[service.Review]{
+	"created_at": {expr: "r.created_at", cast: "timestamptz", desc: true, key: func(r *service.Review) string {
+		return r.CreatedAt.Format(time.RFC3339Nano)
+	}},
+	"rating": {expr: "r.rating", cast: "smallint", desc: true, key: func(r *service.Review) string { return strconv.Itoa(r.Rating) }},
+}
+
+// whereBuilder joins conditions with AND. Each "?" in a condition is replaced
+// by the next positional placeholder, so values never end up inside the SQL text.
+type whereBuilder struct {
+	parts  []string
+	values []any
+}
+
+func (w *whereBuilder) add(cond string, values ...any) {
+	for _, v := range values {
+		w.values = append(w.values, v)
+		cond = strings.Replace(cond, "?", fmt.Sprintf("$%d", len(w.values)), 1)
+	}
+	w.parts = append(w.parts, cond)
+}
+
+func (w *whereBuilder) String() string {
+	if len(w.parts) == 0 {
+		return ""
+	}
+
+	return "WHERE " + strings.Join(w.parts, " AND ")
+}
+
+// keyset adds the condition selecting rows after the cursor (if any) to w and
+// returns the ORDER BY list. idExpr is the unique tie breaker column.
+func keyset[T any](col sortColumn[T], idExpr string, order string, cursor *service.Cursor, w *whereBuilder) string {
+	var (
+		direction = "ASC"
+		operator  = ">"
+	)
+
+	if order == "desc" || (order == "" && col.desc) {
+		direction, operator = "DESC", "<"
+	}
+
+	if cursor != nil {
+		w.add(fmt.Sprintf("(%[1]s, %[2]s) %[3]s (CAST(? AS %[4]s), ?)", col.expr, idExpr, operator, col.cast), cursor.Key, cursor.Id)
+	}
+
+	return fmt.Sprintf("%[1]s %[3]s, %[2]s %[3]s", col.expr, idExpr, direction)
+}
+
+// nextCursor trims the extra row fetched to detect a following page and
+// returns the cursor pointing at the last row kept.
+func nextCursor[T any](items []T, params *service.DetailsParams, sort string, col sortColumn[T], id func(item *T) int) ([]T, string) {
+	if len(items) <= params.Limit {
+		return items, ""
+	}
+
+	items = items[:params.Limit]
+	last := &items[params.Limit-1]
+
+	return items, service.EncodeCursor(service.Cursor{Sort: sort, Order: params.Order, Key: col.key(last), Id: id(last)})
+}
+
+// escapeLike makes a user supplied string match literally inside a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
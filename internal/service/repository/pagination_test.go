@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"film_library/internal/service"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWhereBuilder(t *testing.T) {
+	w := &whereBuilder{}
+	require.Equal(t, "", w.String())
+
+	w.add("f.rating >= ?", float32(5))
+	w.add("f.release_date BETWEEN ? AND ?", "1990-01-01", "2000-01-01")
+	require.Equal(t, "WHERE f.rating >= $1 AND f.release_date BETWEEN $2 AND $3", w.String())
+	require.Equal(t, []any{float32(5), "1990-01-01", "2000-01-01"}, w.values)
+}
+
+func TestKeyset(t *testing.T) {
+	col := filmSortColumns["rating"]
+
+	w := &whereBuilder{}
+	order := keyset(col, "f.id", "", nil, w)
+	require.Equal(t, "COALESCE(f.rating, 0) DESC, f.id DESC", order)
+	require.Equal(t, "", w.String())
+
+	w = &whereBuilder{}
+	w.add("f.rating <= ?", float32(9))
+	order = keyset(col, "f.id", "asc", &service.Cursor{Sort: "rating", Order: "asc", Key: "7.7", Id: 3}, w)
+	require.Equal(t, "COALESCE(f.rating, 0) ASC, f.id ASC", order)
+	require.Equal(t, "WHERE f.rating <= $1 AND (COALESCE(f.rating, 0), f.id) > (CAST($2 AS real), $3)", w.String())
+	require.Equal(t, []any{float32(9), "7.7", 3}, w.values)
+}
+
+func TestNextCursor(t *testing.T) {
+	params := &service.DetailsParams{Sort: "rating", Order: "desc", Limit: 2}
+	films := []service.Film{{Id: 1, Rating: 9}, {Id: 2, Rating: 7.7}, {Id: 3, Rating: 5}}
+	id := func(f *service.Film) int { return f.Id }
+
+	items, next := nextCursor(films, params, "rating", filmSortColumns["rating"], id)
+	require.Equal(t, films[:2], items)
+	require.Equal(t, service.EncodeCursor(service.Cursor{Sort: "rating", Order: "desc", Key: "7.7", Id: 2}), next)
+
+	items, next = nextCursor(films[:2], params, "rating", filmSortColumns["rating"], id)
+	require.Equal(t, films[:2], items)
+	require.Empty(t, next)
+}
+
+func TestEscapeLike(t *testing.T) {
+	require.Equal(t, `100\% \_real\\`, escapeLike(`100% _real\`))
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"film_library/internal/cconstant"
+	"film_library/internal/service"
+	"math"
+)
+
+// ratingCount is the number of reviews giving a film one rating.
+type ratingCount struct {
+	Rating int `db:"rating"`
+	Count  int `db:"reviews"`
+}
+
+// summarizeRatings turns per rating counts into the mean (rounded to two
+// decimals), the total and the distribution of every possible rating, zero counts included.
+func summarizeRatings(counts []ratingCount) *service.RatingSummary {
+	summary := &service.RatingSummary{Distribution: make(map[int]int, cconstant.MaxReviewRating)}
+	for r := cconstant.MinReviewRating; r <= cconstant.MaxReviewRating; r++ {
+		summary.Distribution[r] = 0
+	}
+
+	var sum int
+	for _, c := range counts {
+		summary.Distribution[c.Rating] += c.Count
+		summary.Count += c.Count
+		sum += c.Rating * c.Count
+	}
+
+	if summary.Count > 0 {
+		mean := math.Round(float64(sum)/float64(summary.Count)*100) / 100
+		summary.Mean = &mean
+	}
+
+	return summary
+}
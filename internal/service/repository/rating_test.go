@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSummarizeRatings(t *testing.T) {
+	summary := summarizeRatings([]ratingCount{{Rating: 10, Count: 2}, {Rating: 7, Count: 1}})
+	require.Equal(t, 3, summary.Count)
+	require.NotNil(t, summary.Mean)
+	require.Equal(t, 9.0, *summary.Mean)
+	require.Len(t, summary.Distribution, 10)
+	require.Equal(t, 2, summary.Distribution[10])
+	require.Equal(t, 0, summary.Distribution[1])
+
+	summary = summarizeRatings(nil)
+	require.Zero(t, summary.Count)
+	require.Nil(t, summary.Mean)
+}
@@ -3,491 +3,2008 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"film_library/internal/cconstant"
+	"film_library/internal/recommend"
 	"film_library/internal/service"
 	"fmt"
+	"github.com/jackc/pgx"
+	"github.com/jackc/pgx/pgtype"
 	"github.com/jmoiron/sqlx"
+	"strconv"
 	"strings"
+	"time"
 )
 
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// querier runs the queries of a repository: the pool, or the transaction the
+// repository is bound to by InTx.
+type querier interface {
+	sqlx.Ext
+	sqlx.ExtContext
+	Get(dest any, query string, args ...any) error
+	Select(dest any, query string, args ...any) error
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
 type postgresRepository struct {
-	db *sqlx.DB
+	db querier
+	// pool starts the transactions; tx is set instead in a repository bound to one.
+	pool *sqlx.DB
+	tx   *sqlx.Tx
+}
+
+func NewPostgresRepository(db *sqlx.DB) service.Repository {
+	return &postgresRepository{db: db, pool: db}
+}
+
+// NewTxRepository returns a repository bound to a transaction begun
+// elsewhere, like the one InTx hands out. Committing it is up to the caller.
+func NewTxRepository(tx *sqlx.Tx) service.Repository {
+	return &postgresRepository{db: tx, tx: tx}
+}
+
+// InTx runs fn with a repository bound to one transaction, which is committed
+// when fn returns nil and rolled back otherwise. Inside a transaction already
+// fn joins it.
+func (p *postgresRepository) InTx(ctx context.Context, fn func(repo service.Repository) error) error {
+	return p.transact(ctx, func(tx *sqlx.Tx) error {
+		return fn(NewTxRepository(tx))
+	})
+}
+
+// transact runs fn in the transaction of the repository, or else in a new one.
+func (p *postgresRepository) transact(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	if p.tx != nil {
+		return fn(p.tx)
+	}
+
+	tx, err := p.pool.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockTables are the tables of the entities a change locks.
+var lockTables = map[string]string{
+	"actor":  cconstant.PersonDB,
+	"film":   cconstant.FilmDB,
+	"genre":  cconstant.GenreDB,
+	"review": cconstant.ReviewDB,
+}
+
+// Lock takes the row of the entity with SELECT ... FOR UPDATE, so what is read
+// of it afterwards in the transaction stays as it is until the change is
+// committed. A missing row is no error, the change reports it.
+func (p *postgresRepository) Lock(entity string, id int) error {
+	var (
+		data  []int
+		query = `SELECT id FROM %[1]s WHERE id = $1 FOR UPDATE`
+	)
+
+	table, ok := lockTables[entity]
+	if !ok {
+		return fmt.Errorf("cannot lock %s", entity)
+	}
+
+	return p.db.Select(&data, fmt.Sprintf(query, table), id)
+}
+
+// ----------------------------------------------------- Actor ----------------------------------------------------------
+
+func (p *postgresRepository) CreateActor(params *service.Actor) (int, error) {
+	var (
+		query = `
+		INSERT INTO %[1]s (person_name, sex, bdate)
+		VALUES ($1, $2, $3)
+		RETURNING id`
+
+		values = []any{params.Name, params.Sex, params.BDate}
+	)
+
+	query = fmt.Sprintf(query, cconstant.PersonDB)
+
+	var id int
+	if err := p.db.Get(&id, query, values...); err != nil {
+		return 0, translateError(err)
+	}
+
+	return id, nil
+}
+
+func (p *postgresRepository) GetActor(id int) (*service.Actor, error) {
+	var (
+		data  []service.Actor
+		query = `
+		SELECT a.id, a.person_name, COALESCE(a.sex, '') AS sex, COALESCE(to_char(a.bdate, 'YYYY-MM-DD"T00:00:00Z"'), '') AS bdate, a.version,
+		       ARRAY(SELECT f.film_name
+		             FROM %[2]s af
+		             JOIN %[3]s f ON f.id = af.film_id
+		             WHERE af.actor_id = a.id AND f.deleted_at IS NULL
+		             ORDER BY f.film_name) AS films
+		FROM %[1]s a
+		WHERE a.id = $1 AND a.deleted_at IS NULL
+		`
+
+		values = []any{id}
+	)
+
+	query = fmt.Sprintf(query, cconstant.PersonDB, cconstant.ActorFilmDB, cconstant.FilmDB)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return &service.Actor{}, err
+	}
+
+	if len(data) == 0 {
+		return &service.Actor{}, fmt.Errorf("no actor: %w", service.ErrNotFound)
+	}
+
+	crew, err := p.getPersonCrew(id)
+	if err != nil {
+		return &service.Actor{}, err
+	}
+	data[0].Crew = crew
+
+	return &data[0], nil
+}
+
+// getPersonCrew returns the crew jobs of a person, oldest films first.
+func (p *postgresRepository) getPersonCrew(personId int) ([]service.CrewCredit, error) {
+	var (
+		data  []service.CrewCredit
+		query = `
+		SELECT c.film_id, f.film_name, c.job
+		FROM %[1]s c
+		JOIN %[2]s f ON f.id = c.film_id
+		WHERE c.person_id = $1 AND f.deleted_at IS NULL
+		ORDER BY f.release_date, f.film_name, c.job
+		`
+
+		values = []any{personId}
+	)
+
+	query = fmt.Sprintf(query, cconstant.FilmCrewDB, cconstant.FilmDB)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (p *postgresRepository) GetActorIds(name string) ([]int, error) {
+	var (
+		data  []int
+		query = `
+		SELECT id
+		FROM %[1]s
+		WHERE person_name = $1 AND deleted_at IS NULL
+		ORDER BY id
+		`
+
+		values = []any{name}
+	)
+
+	query = fmt.Sprintf(query, cconstant.PersonDB)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+func (p *postgresRepository) GetActors(params *service.DetailsParams) (*service.ActorsPage, error) {
+	var (
+		data  = make([]service.Actor, 0, params.Limit+1)
+		total int
+		query = `
+		SELECT a.id, a.person_name, COALESCE(a.sex, '') AS sex, COALESCE(to_char(a.bdate, 'YYYY-MM-DD"T00:00:00Z"'), '') AS bdate,
+		       ARRAY(SELECT f.film_name
+		             FROM %[2]s af
+		             JOIN %[3]s f ON f.id = af.film_id
+		             WHERE af.actor_id = a.id AND f.deleted_at IS NULL
+		             ORDER BY f.film_name) AS films
+		FROM %[1]s a
+		%[4]s
+		ORDER BY %[5]s
+		LIMIT %[6]d
+		`
+		countQuery = `SELECT count(*) FROM %[1]s a %[2]s`
+	)
+
+	sort := params.Sort
+	col, ok := actorSortColumns[sort]
+	if !ok {
+		sort = "actor_name"
+		col = actorSortColumns[sort]
+	}
+
+	w := &whereBuilder{}
+	w.add("a.deleted_at IS NULL")
+	if params.Job != "" {
+		w.add(fmt.Sprintf(`EXISTS (SELECT 1 FROM %[1]s c WHERE c.person_id = a.id AND c.job = ?)`,
+			cconstant.FilmCrewDB), params.Job)
+	}
+	if params.FilmId != 0 {
+		w.add(fmt.Sprintf(`EXISTS (SELECT 1 FROM %[1]s af WHERE af.actor_id = a.id AND af.film_id = ?)`,
+			cconstant.ActorFilmDB), params.FilmId)
+	}
+
+	countQuery = fmt.Sprintf(countQuery, cconstant.PersonDB, w)
+	if err := p.db.Get(&total, countQuery, w.values...); err != nil {
+		return nil, err
+	}
+
+	order := keyset(col, "a.id", params.Order, params.Cursor, w)
+
+	query = fmt.Sprintf(query, cconstant.PersonDB, cconstant.ActorFilmDB, cconstant.FilmDB, w, order, params.Limit+1)
+
+	if err := p.db.Select(&data, query, w.values...); err != nil {
+		return nil, err
+	}
+
+	items, next := nextCursor(data, params, sort, col, func(a *service.Actor) int { return a.Id })
+
+	return &service.ActorsPage{Items: items, NextCursor: next, Total: total}, nil
+}
+
+// DeleteActor moves the actor to the trash. Its films and crew jobs are kept
+// for a restore, but no read sees them until then.
+func (p *postgresRepository) DeleteActor(id, userId, version int) error {
+	return p.softDelete(cconstant.PersonDB, "actor", id, userId, version)
+}
+
+// RestoreActor takes the actor out of the trash together with its relations.
+func (p *postgresRepository) RestoreActor(id int) error {
+	return p.restore(cconstant.PersonDB, "actor", id)
+}
+
+// UpdateActor sets the fields present in the patch. A patch with a version
+// other than 0 is applied only while the actor still has it.
+func (p *postgresRepository) UpdateActor(id int, params *service.ActorPatch) error {
+	var (
+		query = `
+		UPDATE %[1]s SET %[2]s
+		WHERE id = $%[3]d AND deleted_at IS NULL AND ($%[4]d = 0 OR version = $%[4]d)
+		`
+
+		sets   []string
+		values []any
+	)
+
+	if params.Name.Set {
+		values = append(values, params.Name.Value)
+		sets = append(sets, fmt.Sprintf("person_name = $%d", len(values)))
+	}
+	if params.Sex.Set {
+		values = append(values, params.Sex.Value)
+		sets = append(sets, fmt.Sprintf("sex = $%d", len(values)))
+	}
+	if params.BDate.Set {
+		values = append(values, params.BDate.Value)
+		sets = append(sets, fmt.Sprintf("bdate = $%d", len(values)))
+	}
+	if len(sets) == 0 {
+		return fmt.Errorf("nothing to update in actor %d", id)
+	}
+	values = append(values, id, params.Version)
+
+	query = fmt.Sprintf(query, cconstant.PersonDB, strings.Join(sets, ", "), len(values)-1, len(values))
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return p.unchanged(cconstant.PersonDB, "actor", id, params.Version)
+	}
+
+	return nil
+}
+
+// ReplaceActor sets every editable field of the actor, the empty ones too; an
+// empty sex or bdate, which only a synced person has, is stored as unknown.
+// params.Version works like the version of UpdateActor.
+func (p *postgresRepository) ReplaceActor(id int, params *service.Actor) error {
+	var (
+		query = `
+		UPDATE %[1]s SET person_name = $1, sex = NULLIF($2, ''), bdate = CAST(NULLIF($3, '') AS date)
+		WHERE id = $4 AND deleted_at IS NULL AND ($5 = 0 OR version = $5)
+		`
+
+		values = []any{params.Name, params.Sex, params.BDate, id, params.Version}
+	)
+
+	query = fmt.Sprintf(query, cconstant.PersonDB)
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return p.unchanged(cconstant.PersonDB, "actor", id, params.Version)
+	}
+
+	return nil
+}
+
+func (p *postgresRepository) SearchActor(params *service.SearchParams) (*service.ActorSearchResult, error) {
+	var (
+		data  = make([]service.ActorHit, 0, params.Limit)
+		query = `
+		SELECT a.id, a.person_name, COALESCE(a.sex, '') AS sex, COALESCE(to_char(a.bdate, 'YYYY-MM-DD"T00:00:00Z"'), '') AS bdate,
+		       ARRAY(SELECT f.film_name
+		             FROM %[2]s af
+		             JOIN %[3]s f ON f.id = af.film_id
+		             WHERE af.actor_id = a.id AND f.deleted_at IS NULL
+		             ORDER BY f.film_name) AS films,
+		       ts_rank(%[4]s, q) AS rank,
+		       ts_headline('simple'::regconfig, a.person_name, q, '%[5]s') AS headline
+		FROM %[1]s a, websearch_to_tsquery('simple'::regconfig, $1) q
+		WHERE %[4]s @@ q AND a.deleted_at IS NULL
+		ORDER BY rank DESC, a.id
+		LIMIT $2
+		`
+
+		values = []any{params.Query, params.Limit}
+	)
+
+	query = fmt.Sprintf(query, cconstant.PersonDB, cconstant.ActorFilmDB, cconstant.FilmDB, actorDocument, headlineOptions)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return nil, err
+	}
+
+	return &service.ActorSearchResult{Items: data}, nil
+}
+
+func (p *postgresRepository) SimilarActors(params *service.SearchParams) (*service.ActorSearchResult, error) {
+	var (
+		data  = make([]service.ActorHit, 0, params.Limit)
+		query = `
+		SELECT a.id, a.person_name, COALESCE(a.sex, '') AS sex, COALESCE(to_char(a.bdate, 'YYYY-MM-DD"T00:00:00Z"'), '') AS bdate,
+		       ARRAY(SELECT f.film_name
+		             FROM %[2]s af
+		             JOIN %[3]s f ON f.id = af.film_id
+		             WHERE af.actor_id = a.id AND f.deleted_at IS NULL
+		             ORDER BY f.film_name) AS films,
+		       word_similarity($1, a.person_name) AS rank
+		FROM %[1]s a
+		WHERE $1 <%% a.person_name AND a.deleted_at IS NULL
+		ORDER BY rank DESC, a.person_name, a.id
+		LIMIT $2
+		`
+
+		values = []any{params.Query, params.Limit}
+	)
+
+	query = fmt.Sprintf(query, cconstant.PersonDB, cconstant.ActorFilmDB, cconstant.FilmDB)
+
+	err := p.withSimilarityThreshold(params.Threshold, func(tx *sqlx.Tx) error {
+		return tx.Select(&data, query, values...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.ActorSearchResult{Items: data}, nil
+}
+
+// GetActorsByIds returns the actors with the given ids, without their films, in no particular order.
+func (p *postgresRepository) GetActorsByIds(ids []int) ([]service.Actor, error) {
+	var (
+		data  = make([]service.Actor, 0, len(ids))
+		query = `
+		SELECT a.id, a.person_name, COALESCE(a.sex, '') AS sex, COALESCE(to_char(a.bdate, 'YYYY-MM-DD"T00:00:00Z"'), '') AS bdate
+		FROM %[1]s a
+		WHERE a.id = ANY($1) AND a.deleted_at IS NULL
+		`
+	)
+
+	var actorIds pgtype.Int4Array
+	if err := actorIds.Set(ids); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(query, cconstant.PersonDB)
+
+	if err := p.db.Select(&data, query, &actorIds); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ActorLinks returns every co-actor of the given actors together with the film they share.
+func (p *postgresRepository) ActorLinks(ctx context.Context, actorIds []int) ([]service.ActorLink, error) {
+	var (
+		data  = make([]service.ActorLink, 0)
+		query = `
+		SELECT af1.actor_id, af1.film_id, af2.actor_id AS co_actor_id
+		FROM %[1]s af1
+		JOIN %[1]s af2 ON af2.film_id = af1.film_id AND af2.actor_id <> af1.actor_id
+		JOIN %[2]s f ON f.id = af1.film_id
+		JOIN %[3]s a ON a.id = af2.actor_id
+		WHERE af1.actor_id = ANY($1) AND f.deleted_at IS NULL AND a.deleted_at IS NULL
+		ORDER BY af1.actor_id, af1.film_id, af2.actor_id
+		`
+	)
+
+	var ids pgtype.Int4Array
+	if err := ids.Set(actorIds); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(query, cconstant.ActorFilmDB, cconstant.FilmDB, cconstant.PersonDB)
+
+	if err := p.db.SelectContext(ctx, &data, query, &ids); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ----------------------------------------------------- FILM ----------------------------------------------------------
+
+func (p *postgresRepository) CreateFilm(params *service.Film) (int, error) {
+	var (
+		query = `
+		INSERT INTO %[1]s (film_name, release_date, rating, description)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+		values = []any{params.Name, params.RDate, params.Rating, params.Desc}
+	)
+
+	query = fmt.Sprintf(query, cconstant.FilmDB)
+
+	var id int
+	if err := p.db.Get(&id, query, values...); err != nil {
+		return 0, translateError(err)
+	}
+
+	return id, nil
+}
+
+func (p *postgresRepository) GetFilm(id int) (*service.Film, error) {
+	var (
+		data  []service.Film
+		query = `
+		SELECT f.id, f.film_name, f.release_date, COALESCE(f.rating, 0) AS rating, COALESCE(f.description, '') AS description, f.version,
+		       ARRAY(SELECT a.person_name
+		             FROM %[2]s af
+		             JOIN %[3]s a ON a.id = af.actor_id
+		             WHERE af.film_id = f.id AND a.deleted_at IS NULL
+		             ORDER BY a.person_name) AS actors,
+		       ARRAY(SELECT g.genre_name
+		             FROM %[4]s fg
+		             JOIN %[5]s g ON g.id = fg.genre_id
+		             WHERE fg.film_id = f.id
+		             ORDER BY g.genre_name) AS genres
+		FROM %[1]s f
+		WHERE f.id = $1 AND f.deleted_at IS NULL
+		`
+
+		values = []any{id}
+	)
+
+	query = fmt.Sprintf(query, cconstant.FilmDB, cconstant.ActorFilmDB, cconstant.PersonDB, cconstant.FilmGenreDB, cconstant.GenreDB)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return &service.Film{}, err
+	}
+
+	if len(data) == 0 {
+		return &service.Film{}, fmt.Errorf("no film: %w", service.ErrNotFound)
+	}
+
+	cast, err := p.getCast(id)
+	if err != nil {
+		return &service.Film{}, err
+	}
+	data[0].Cast = cast
+
+	crew, err := p.getFilmCrew(id)
+	if err != nil {
+		return &service.Film{}, err
+	}
+	data[0].Crew = crew
+
+	rating, err := p.getUserRating(id)
+	if err != nil {
+		return &service.Film{}, err
+	}
+	data[0].UserRating = rating
+
+	return &data[0], nil
+}
+
+// getCast returns the credits of a film in billing order.
+func (p *postgresRepository) getCast(filmId int) ([]service.Credit, error) {
+	var (
+		data  []service.Credit
+		query = `
+		SELECT af.actor_id, a.person_name, COALESCE(af.character_name, '') AS character_name,
+		       af.credit_type, af.billing_order
+		FROM %[1]s af
+		JOIN %[2]s a ON a.id = af.actor_id
+		WHERE af.film_id = $1 AND a.deleted_at IS NULL
+		ORDER BY af.billing_order NULLS LAST, a.person_name, af.actor_id
+		`
+
+		values = []any{filmId}
+	)
+
+	query = fmt.Sprintf(query, cconstant.ActorFilmDB, cconstant.PersonDB)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// getFilmCrew returns the crew of a film, directors first.
+func (p *postgresRepository) getFilmCrew(filmId int) ([]service.CrewCredit, error) {
+	var (
+		data  []service.CrewCredit
+		query = `
+		SELECT c.person_id, p.person_name, c.job
+		FROM %[1]s c
+		JOIN %[2]s p ON p.id = c.person_id
+		WHERE c.film_id = $1 AND p.deleted_at IS NULL
+		ORDER BY array_position(ARRAY['director', 'writer', 'producer', 'composer', 'cinematographer']::varchar[], c.job),
+		         p.person_name, c.person_id
+		`
+
+		values = []any{filmId}
+	)
+
+	query = fmt.Sprintf(query, cconstant.FilmCrewDB, cconstant.PersonDB)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (p *postgresRepository) GetFilmIds(name string) ([]int, error) {
+	var (
+		data  []int
+		query = `
+		SELECT id
+		FROM %[1]s
+		WHERE film_name = $1 AND deleted_at IS NULL
+		ORDER BY id
+		`
+
+		values = []any{name}
+	)
+
+	query = fmt.Sprintf(query, cconstant.FilmDB)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+func (p *postgresRepository) GetFilms(params *service.DetailsParams) (*service.FilmsPage, error) {
+	var (
+		data  = make([]service.Film, 0, params.Limit+1)
+		total int
+		query = `
+		SELECT f.id, f.film_name, f.release_date, COALESCE(f.rating, 0) AS rating, COALESCE(f.description, '') AS description,
+		       ARRAY(SELECT a.person_name
+		             FROM %[2]s af
+		             JOIN %[3]s a ON a.id = af.actor_id
+		             WHERE af.film_id = f.id AND a.deleted_at IS NULL
+		             ORDER BY a.person_name) AS actors,
+		       ARRAY(SELECT g.genre_name
+		             FROM %[7]s fg
+		             JOIN %[8]s g ON g.id = fg.genre_id
+		             WHERE fg.film_id = f.id
+		             ORDER BY g.genre_name) AS genres
+		FROM %[1]s f
+		%[4]s
+		ORDER BY %[5]s
+		LIMIT %[6]d
+		`
+		countQuery = `SELECT count(*) FROM %[1]s f %[2]s`
+	)
+
+	sort := params.Sort
+	col, ok := filmSortColumns[sort]
+	if !ok {
+		sort = "rating"
+		col = filmSortColumns[sort]
+	}
+
+	w := &whereBuilder{}
+	w.add("f.deleted_at IS NULL")
+	if params.Filter != nil {
+		p.filmFilter(params.Filter, w)
+	}
+
+	// The total counts every film matching the filter, so it is taken before the cursor condition is added.
+	countQuery = fmt.Sprintf(countQuery, cconstant.FilmDB, w)
+	if err := p.db.Get(&total, countQuery, w.values...); err != nil {
+		return nil, err
+	}
+
+	order := keyset(col, "f.id", params.Order, params.Cursor, w)
+
+	query = fmt.Sprintf(query, cconstant.FilmDB, cconstant.ActorFilmDB, cconstant.PersonDB, w, order, params.Limit+1,
+		cconstant.FilmGenreDB, cconstant.GenreDB)
+
+	if err := p.db.Select(&data, query, w.values...); err != nil {
+		return nil, err
+	}
+
+	items, next := nextCursor(data, params, sort, col, func(f *service.Film) int { return f.Id })
+
+	return &service.FilmsPage{Items: items, NextCursor: next, Total: total}, nil
+}
+
+func (p *postgresRepository) filmFilter(filter *service.FilmFilter, w *whereBuilder) {
+	if filter.ReleasedFrom != "" {
+		w.add("f.release_date >= ?", filter.ReleasedFrom)
+	}
+	if filter.ReleasedTo != "" {
+		w.add("f.release_date <= ?", filter.ReleasedTo)
+	}
+	if filter.RatingFrom != nil {
+		w.add("f.rating >= ?", *filter.RatingFrom)
+	}
+	if filter.RatingTo != nil {
+		w.add("f.rating <= ?", *filter.RatingTo)
+	}
+	for _, actorId := range filter.ActorIds {
+		w.add(fmt.Sprintf(`EXISTS (SELECT 1 FROM %[1]s af WHERE af.film_id = f.id AND af.actor_id = ?)`,
+			cconstant.ActorFilmDB), actorId)
+	}
+	for _, actor := range filter.Actors {
+		w.add(fmt.Sprintf(`EXISTS (SELECT 1 FROM %[1]s af JOIN %[2]s a ON a.id = af.actor_id
+			WHERE af.film_id = f.id AND a.person_name = ? AND a.deleted_at IS NULL)`, cconstant.ActorFilmDB, cconstant.PersonDB), actor)
+	}
+	for _, genreId := range filter.GenreIds {
+		w.add(fmt.Sprintf(`EXISTS (SELECT 1 FROM %[1]s fg WHERE fg.film_id = f.id AND fg.genre_id = ?)`,
+			cconstant.FilmGenreDB), genreId)
+	}
+	for _, genre := range filter.Genres {
+		w.add(fmt.Sprintf(`EXISTS (SELECT 1 FROM %[1]s fg JOIN %[2]s g ON g.id = fg.genre_id
+			WHERE fg.film_id = f.id AND g.genre_name = ?)`, cconstant.FilmGenreDB, cconstant.GenreDB), genre)
+	}
+	if filter.Keyword != "" {
+		w.add("f.description ILIKE ?", "%"+escapeLike(filter.Keyword)+"%")
+	}
+}
+
+// DeleteFilm moves the film to the trash, see DeleteActor.
+func (p *postgresRepository) DeleteFilm(id, userId, version int) error {
+	return p.softDelete(cconstant.FilmDB, "film", id, userId, version)
+}
+
+// RestoreFilm takes the film out of the trash together with its relations.
+func (p *postgresRepository) RestoreFilm(id int) error {
+	return p.restore(cconstant.FilmDB, "film", id)
+}
+
+// UpdateFilm sets the fields present in the patch, a null or empty
+// description clears it. See UpdateActor for the version.
+func (p *postgresRepository) UpdateFilm(id int, params *service.FilmPatch) error {
+	var (
+		query = `
+		UPDATE %[1]s SET %[2]s
+		WHERE id = $%[3]d AND deleted_at IS NULL AND ($%[4]d = 0 OR version = $%[4]d)
+		`
+
+		sets   []string
+		values []any
+	)
+
+	if params.Name.Set {
+		values = append(values, params.Name.Value)
+		sets = append(sets, fmt.Sprintf("film_name = $%d", len(values)))
+	}
+	if params.RDate.Set {
+		values = append(values, params.RDate.Value)
+		sets = append(sets, fmt.Sprintf("release_date = $%d", len(values)))
+	}
+	if params.Rating.Set {
+		values = append(values, params.Rating.Value)
+		sets = append(sets, fmt.Sprintf("rating = $%d", len(values)))
+	}
+	if params.Desc.Set {
+		values = append(values, params.Desc.Value)
+		sets = append(sets, fmt.Sprintf("description = NULLIF($%d, '')", len(values)))
+	}
+	if len(sets) == 0 {
+		return fmt.Errorf("nothing to update in film %d", id)
+	}
+	values = append(values, id, params.Version)
+
+	query = fmt.Sprintf(query, cconstant.FilmDB, strings.Join(sets, ", "), len(values)-1, len(values))
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return p.unchanged(cconstant.FilmDB, "film", id, params.Version)
+	}
+
+	return nil
+}
+
+// ReplaceFilm sets every editable field of the film, an empty description clears it.
+// params.Version works like the version of UpdateActor.
+func (p *postgresRepository) ReplaceFilm(id int, params *service.Film) error {
+	var (
+		query = `
+		UPDATE %[1]s SET film_name = $1, release_date = $2, rating = $3, description = NULLIF($4, '')
+		WHERE id = $5 AND deleted_at IS NULL AND ($6 = 0 OR version = $6)
+		`
+
+		values = []any{params.Name, params.RDate, params.Rating, params.Desc, id, params.Version}
+	)
+
+	query = fmt.Sprintf(query, cconstant.FilmDB)
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return p.unchanged(cconstant.FilmDB, "film", id, params.Version)
+	}
+
+	return nil
+}
+
+func (p *postgresRepository) SearchFilms(params *service.SearchParams) (*service.FilmSearchResult, error) {
+	var (
+		data  = make([]service.FilmHit, 0, params.Limit)
+		query = `
+		SELECT f.id, f.film_name, f.release_date, COALESCE(f.rating, 0) AS rating, COALESCE(f.description, '') AS description,
+		       ARRAY(SELECT a.person_name
+		             FROM %[2]s af
+		             JOIN %[3]s a ON a.id = af.actor_id
+		             WHERE af.film_id = f.id AND a.deleted_at IS NULL
+		             ORDER BY a.person_name) AS actors,
+		       ARRAY(SELECT g.genre_name
+		             FROM %[7]s fg
+		             JOIN %[8]s g ON g.id = fg.genre_id
+		             WHERE fg.film_id = f.id
+		             ORDER BY g.genre_name) AS genres,
+		       ts_rank(%[4]s, q) AS rank,
+		       ts_headline(%[5]s, concat_ws('. ', f.film_name, f.description), q, '%[6]s') AS headline
+		FROM %[1]s f, websearch_to_tsquery(%[5]s, $1) q
+		WHERE %[4]s @@ q AND f.deleted_at IS NULL
+		ORDER BY rank DESC, f.id
+		LIMIT $2
+		`
+
+		values = []any{params.Query, params.Limit}
+	)
+
+	config, err := searchConfig(params.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(query, cconstant.FilmDB, cconstant.ActorFilmDB, cconstant.PersonDB, filmDocument(config), config, headlineOptions,
+		cconstant.FilmGenreDB, cconstant.GenreDB)
+
+	if err = p.db.Select(&data, query, values...); err != nil {
+		return nil, err
+	}
+
+	return &service.FilmSearchResult{Items: data}, nil
+}
+
+func (p *postgresRepository) SimilarFilms(params *service.SearchParams) (*service.FilmSearchResult, error) {
+	var (
+		data  = make([]service.FilmHit, 0, params.Limit)
+		query = `
+		SELECT f.id, f.film_name, f.release_date, COALESCE(f.rating, 0) AS rating, COALESCE(f.description, '') AS description,
+		       ARRAY(SELECT a.person_name
+		             FROM %[2]s af
+		             JOIN %[3]s a ON a.id = af.actor_id
+		             WHERE af.film_id = f.id AND a.deleted_at IS NULL
+		             ORDER BY a.person_name) AS actors,
+		       ARRAY(SELECT g.genre_name
+		             FROM %[4]s fg
+		             JOIN %[5]s g ON g.id = fg.genre_id
+		             WHERE fg.film_id = f.id
+		             ORDER BY g.genre_name) AS genres,
+		       word_similarity($1, f.film_name) AS rank
+		FROM %[1]s f
+		WHERE $1 <%% f.film_name AND f.deleted_at IS NULL
+		ORDER BY rank DESC, f.film_name, f.id
+		LIMIT $2
+		`
+
+		values = []any{params.Query, params.Limit}
+	)
+
+	query = fmt.Sprintf(query, cconstant.FilmDB, cconstant.ActorFilmDB, cconstant.PersonDB, cconstant.FilmGenreDB, cconstant.GenreDB)
+
+	err := p.withSimilarityThreshold(params.Threshold, func(tx *sqlx.Tx) error {
+		return tx.Select(&data, query, values...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.FilmSearchResult{Items: data}, nil
+}
+
+// Weights of the related films score: a shared actor counts as much as three
+// shared genres, rating proximity in [0;1] orders films that share as much.
+const (
+	sharedCastWeight  = 3
+	sharedGenreWeight = 1
+)
+
+// GetFilmsByIds returns the films with the given ids in no particular order.
+func (p *postgresRepository) GetFilmsByIds(ids []int) ([]service.Film, error) {
+	var (
+		data  = make([]service.Film, 0, len(ids))
+		query = `
+		SELECT f.id, f.film_name, f.release_date, COALESCE(f.rating, 0) AS rating, COALESCE(f.description, '') AS description,
+		       ARRAY(SELECT a.person_name
+		             FROM %[2]s af
+		             JOIN %[3]s a ON a.id = af.actor_id
+		             WHERE af.film_id = f.id AND a.deleted_at IS NULL
+		             ORDER BY a.person_name) AS actors,
+		       ARRAY(SELECT g.genre_name
+		             FROM %[4]s fg
+		             JOIN %[5]s g ON g.id = fg.genre_id
+		             WHERE fg.film_id = f.id
+		             ORDER BY g.genre_name) AS genres
+		FROM %[1]s f
+		WHERE f.id = ANY($1) AND f.deleted_at IS NULL
+		`
+	)
+
+	var filmIds pgtype.Int4Array
+	if err := filmIds.Set(ids); err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(query, cconstant.FilmDB, cconstant.ActorFilmDB, cconstant.PersonDB, cconstant.FilmGenreDB, cconstant.GenreDB)
+
+	if err := p.db.Select(&data, query, &filmIds); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// RelatedFilms returns the films sharing actors or genres with the film, the most related first.
+func (p *postgresRepository) RelatedFilms(id, limit int) ([]service.RelatedFilm, error) {
+	var (
+		data   = make([]service.RelatedFilm, 0, limit)
+		rating []float32
+		query  = `
+		WITH shared_cast AS (
+			SELECT af2.film_id, count(*) AS shared_cast
+			FROM %[2]s af1
+			JOIN %[2]s af2 ON af2.actor_id = af1.actor_id AND af2.film_id <> af1.film_id
+			JOIN %[3]s a ON a.id = af1.actor_id
+			WHERE af1.film_id = $1 AND a.deleted_at IS NULL
+			GROUP BY af2.film_id
+		), shared_genres AS (
+			SELECT fg2.film_id, count(*) AS shared_genres
+			FROM %[4]s fg1
+			JOIN %[4]s fg2 ON fg2.genre_id = fg1.genre_id AND fg2.film_id <> fg1.film_id
+			WHERE fg1.film_id = $1
+			GROUP BY fg2.film_id
+		), related AS (
+			SELECT COALESCE(c.film_id, g.film_id) AS film_id,
+			       COALESCE(c.shared_cast, 0) AS shared_cast,
+			       COALESCE(g.shared_genres, 0) AS shared_genres
+			FROM shared_cast c
+			FULL JOIN shared_genres g ON g.film_id = c.film_id
+		)
+		SELECT f.id, f.film_name, f.release_date, COALESCE(f.rating, 0) AS rating, COALESCE(f.description, '') AS description,
+		       ARRAY(SELECT a.person_name
+		             FROM %[2]s af
+		             JOIN %[3]s a ON a.id = af.actor_id
+		             WHERE af.film_id = f.id AND a.deleted_at IS NULL
+		             ORDER BY a.person_name) AS actors,
+		       ARRAY(SELECT g.genre_name
+		             FROM %[4]s fg
+		             JOIN %[5]s g ON g.id = fg.genre_id
+		             WHERE fg.film_id = f.id
+		             ORDER BY g.genre_name) AS genres,
+		       r.shared_cast, r.shared_genres,
+		       r.shared_cast * %[6]d + r.shared_genres * %[7]d + 1 - abs(COALESCE(f.rating, 0) - $2) / 10 AS score
+		FROM related r
+		JOIN %[1]s f ON f.id = r.film_id
+		WHERE f.deleted_at IS NULL
+		ORDER BY score DESC, f.id
+		LIMIT $3
+		`
+		ratingQuery = `SELECT COALESCE(rating, 0) FROM %[1]s WHERE id = $1 AND deleted_at IS NULL`
+	)
+
+	if err := p.db.Select(&rating, fmt.Sprintf(ratingQuery, cconstant.FilmDB), id); err != nil {
+		return nil, err
+	}
+	if len(rating) == 0 {
+		return nil, fmt.Errorf("no film: %w", service.ErrNotFound)
+	}
+
+	query = fmt.Sprintf(query, cconstant.FilmDB, cconstant.ActorFilmDB, cconstant.PersonDB, cconstant.FilmGenreDB, cconstant.GenreDB,
+		sharedCastWeight, sharedGenreWeight)
+
+	if err := p.db.Select(&data, query, id, rating[0], limit); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ----------------------------------------------------- Genre ----------------------------------------------------------
+
+func (p *postgresRepository) CreateGenre(params *service.Genre) (int, error) {
+	var (
+		query = `
+		INSERT INTO %[1]s (genre_name)
+		VALUES ($1)
+		RETURNING id`
+
+		values = []any{params.Name}
+	)
+
+	query = fmt.Sprintf(query, cconstant.GenreDB)
+
+	var id int
+	if err := p.db.Get(&id, query, values...); err != nil {
+		return 0, translateError(err)
+	}
+
+	return id, nil
+}
+
+func (p *postgresRepository) GetGenre(id int) (*service.Genre, error) {
+	var (
+		data  []service.Genre
+		query = `
+		SELECT g.id, g.genre_name,
+		       (SELECT count(*)
+		        FROM %[2]s fg
+		        JOIN %[3]s f ON f.id = fg.film_id
+		        WHERE fg.genre_id = g.id AND f.deleted_at IS NULL) AS films
+		FROM %[1]s g
+		WHERE g.id = $1
+		`
+
+		values = []any{id}
+	)
+
+	query = fmt.Sprintf(query, cconstant.GenreDB, cconstant.FilmGenreDB, cconstant.FilmDB)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return &service.Genre{}, err
+	}
+
+	if len(data) == 0 {
+		return &service.Genre{}, fmt.Errorf("no genre: %w", service.ErrNotFound)
+	}
+
+	return &data[0], nil
+}
+
+func (p *postgresRepository) GetGenreId(name string) (int, error) {
+	var (
+		data  []int
+		query = `
+		SELECT id
+		FROM %[1]s
+		WHERE genre_name = $1
+		`
+
+		values = []any{name}
+	)
+
+	query = fmt.Sprintf(query, cconstant.GenreDB)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return 0, err
+	}
+
+	if len(data) == 0 {
+		return 0, fmt.Errorf("no genre named %q: %w", name, service.ErrNotFound)
+	}
+
+	return data[0], nil
+}
+
+// GetGenres lists every genre with the number of films in it, the largest genres first.
+func (p *postgresRepository) GetGenres() ([]service.Genre, error) {
+	var (
+		data  = make([]service.Genre, 0)
+		query = `
+		SELECT g.id, g.genre_name, count(fg.film_id) AS films
+		FROM %[1]s g
+		LEFT JOIN (%[2]s fg JOIN %[3]s f ON f.id = fg.film_id AND f.deleted_at IS NULL) ON fg.genre_id = g.id
+		GROUP BY g.id, g.genre_name
+		ORDER BY films DESC, g.genre_name
+		`
+	)
+
+	query = fmt.Sprintf(query, cconstant.GenreDB, cconstant.FilmGenreDB, cconstant.FilmDB)
+
+	if err := p.db.Select(&data, query); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (p *postgresRepository) DeleteGenre(id int) error {
+	var (
+		query = `
+		DELETE FROM %[1]s
+		WHERE id = $1
+		`
+
+		values = []any{id}
+	)
+
+	query = fmt.Sprintf(query, cconstant.GenreDB)
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("no genre: %w", service.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (p *postgresRepository) UpdateGenre(id int, params *service.Genre) error {
+	var (
+		query = `
+		UPDATE %[1]s SET genre_name = $1
+		WHERE id = $2
+		`
+
+		values = []any{params.Name, id}
+	)
+
+	query = fmt.Sprintf(query, cconstant.GenreDB)
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("no genre: %w", service.ErrNotFound)
+	}
+
+	return nil
+}
+
+// ----------------------------------------------------- Review ----------------------------------------------------------
+
+func (p *postgresRepository) CreateReview(params *service.Review) (int, error) {
+	var (
+		query = `
+		INSERT INTO %[1]s (film_id, user_id, rating, review_text)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		RETURNING id`
+
+		values = []any{params.FilmId, params.UserId, params.Rating, params.Text}
+	)
+
+	if err := requireLive(p.db, cconstant.FilmDB, "film", params.FilmId); err != nil {
+		return 0, err
+	}
+
+	query = fmt.Sprintf(query, cconstant.ReviewDB)
+
+	var id int
+	if err := p.db.Get(&id, query, values...); err != nil {
+		return 0, translateError(err)
+	}
+
+	return id, nil
+}
+
+func (p *postgresRepository) GetReviews(filmId int, params *service.DetailsParams) (*service.ReviewsPage, error) {
+	var (
+		data  = make([]service.Review, 0, params.Limit+1)
+		total int
+		query = `
+		SELECT r.id, r.film_id, r.user_id, u.login, r.rating, COALESCE(r.review_text, '') AS review_text,
+		       r.created_at, r.updated_at
+		FROM %[1]s r
+		JOIN %[2]s u ON u.id = r.user_id
+		%[3]s
+		ORDER BY %[4]s
+		LIMIT %[5]d
+		`
+		countQuery = `SELECT count(*) FROM %[1]s r %[2]s`
+	)
+
+	sort := params.Sort
+	col, ok := reviewSortColumns[sort]
+	if !ok {
+		sort = "created_at"
+		col = reviewSortColumns[sort]
+	}
+
+	// The reviews of a film in the trash are hidden with the film.
+	if err := requireLive(p.db, cconstant.FilmDB, "film", filmId); err != nil {
+		return nil, err
+	}
+
+	w := &whereBuilder{}
+	w.add("r.film_id = ?", filmId)
+
+	countQuery = fmt.Sprintf(countQuery, cconstant.ReviewDB, w)
+	if err := p.db.Get(&total, countQuery, w.values...); err != nil {
+		return nil, err
+	}
+
+	order := keyset(col, "r.id", params.Order, params.Cursor, w)
+
+	query = fmt.Sprintf(query, cconstant.ReviewDB, cconstant.AuthDB, w, order, params.Limit+1)
+
+	if err := p.db.Select(&data, query, w.values...); err != nil {
+		return nil, err
+	}
+
+	items, next := nextCursor(data, params, sort, col, func(r *service.Review) int { return r.Id })
+
+	return &service.ReviewsPage{Items: items, NextCursor: next, Total: total}, nil
+}
+
+// GetReview returns a review of any user on a film that is not in the trash.
+func (p *postgresRepository) GetReview(id int) (*service.Review, error) {
+	var (
+		data  []service.Review
+		query = `
+		SELECT r.id, r.film_id, r.user_id, u.login, r.rating, COALESCE(r.review_text, '') AS review_text,
+		       r.created_at, r.updated_at
+		FROM %[1]s r
+		JOIN %[2]s u ON u.id = r.user_id
+		JOIN %[3]s f ON f.id = r.film_id AND f.deleted_at IS NULL
+		WHERE r.id = $1
+		`
+	)
+
+	query = fmt.Sprintf(query, cconstant.ReviewDB, cconstant.AuthDB, cconstant.FilmDB)
+
+	if err := p.db.Select(&data, query, id); err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no review: %w", service.ErrNotFound)
+	}
+
+	return &data[0], nil
+}
+
+// UpdateReview replaces the rating and text of a review. Only the author
+// can change it, reviews of other users and of films in the trash are
+// reported as missing.
+func (p *postgresRepository) UpdateReview(id int, params *service.Review) error {
+	var (
+		query = `
+		UPDATE %[1]s r SET rating = $1, review_text = NULLIF($2, ''), updated_at = now()
+		FROM %[2]s f
+		WHERE r.id = $3 AND r.user_id = $4 AND f.id = r.film_id AND f.deleted_at IS NULL
+		`
+
+		values = []any{params.Rating, params.Text, id, params.UserId}
+	)
+
+	query = fmt.Sprintf(query, cconstant.ReviewDB, cconstant.FilmDB)
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("no review: %w", service.ErrNotFound)
+	}
+
+	return nil
+}
+
+// DeleteReview removes a review of the user, of anyone when userId is 0.
+// The reviews of a film in the trash are kept for its restore.
+func (p *postgresRepository) DeleteReview(id, userId int) error {
+	var (
+		query = `
+		DELETE FROM %[1]s r
+		USING %[2]s f
+		WHERE r.id = $1 AND ($2 = 0 OR r.user_id = $2) AND f.id = r.film_id AND f.deleted_at IS NULL
+		`
+
+		values = []any{id, userId}
+	)
+
+	query = fmt.Sprintf(query, cconstant.ReviewDB, cconstant.FilmDB)
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("no review: %w", service.ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetRatings returns every user rating, the input of the recommendation model.
+func (p *postgresRepository) GetRatings() ([]recommend.Rating, error) {
+	var (
+		data  = make([]recommend.Rating, 0)
+		query = `
+		SELECT r.user_id, r.film_id, r.rating
+		FROM %[1]s r
+		JOIN %[2]s f ON f.id = r.film_id
+		WHERE f.deleted_at IS NULL
+		`
+	)
+
+	query = fmt.Sprintf(query, cconstant.ReviewDB, cconstant.FilmDB)
+
+	if err := p.db.Select(&data, query); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// getUserRating aggregates the user ratings of a film.
+func (p *postgresRepository) getUserRating(filmId int) (*service.RatingSummary, error) {
+	var (
+		data  []ratingCount
+		query = `
+		SELECT rating, count(*) AS reviews
+		FROM %[1]s
+		WHERE film_id = $1
+		GROUP BY rating
+		`
+
+		values = []any{filmId}
+	)
+
+	query = fmt.Sprintf(query, cconstant.ReviewDB)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return nil, err
+	}
+
+	return summarizeRatings(data), nil
 }
 
-func NewPostgresRepository(db *sqlx.DB) service.Repository {
-	return &postgresRepository{db: db}
+// ----------------------------------------------------- Relations ----------------------------------------------------------
+
+func (p *postgresRepository) AddFilmsByActor(params *service.AddFilmsByActorParams) error {
+	return p.transact(context.Background(), func(tx *sqlx.Tx) error {
+		for _, filmId := range params.FilmIds {
+			if err := p.addActorFilm(tx, params.ActorId, filmId); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
-// ----------------------------------------------------- Actor ----------------------------------------------------------
+func (p *postgresRepository) AddActorsByFilm(params *service.AddActorsByFilmParams) error {
+	return p.transact(context.Background(), func(tx *sqlx.Tx) error {
+		for _, actorId := range params.ActorIds {
+			if err := p.addActorFilm(tx, actorId, params.FilmId); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
 
-func (p *postgresRepository) CreateActor(params *service.Actor) error {
+func (p *postgresRepository) DeleteActorFilm(params *service.DeleteActorFilmParams) error {
 	var (
 		query = `
-		INSERT INTO %[1]s (actor_name, sex, bdate)
-		VALUES ($1, $2, $3)`
+		DELETE FROM %[1]s
+		WHERE actor_id = $1 AND film_id = $2
+		`
 
-		values = []any{params.Name, params.Sex, params.BDate}
+		values = []any{params.ActorId, params.FilmId}
 	)
 
-	query = fmt.Sprintf(query, cconstant.ActorDB)
+	if err := requireLive(p.db, cconstant.PersonDB, "actor", params.ActorId); err != nil {
+		return err
+	}
+	if err := requireLive(p.db, cconstant.FilmDB, "film", params.FilmId); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(query, cconstant.ActorFilmDB)
 
-	if _, err := p.db.Exec(query, values...); err != nil {
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
 		return err
 	}
 
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("couldn't find relation: %w", service.ErrNotFound)
+	}
+
 	return nil
 }
 
-func (p *postgresRepository) GetActor(name string) (*service.Actor, error) {
+// GetCredit returns the part of an actor in a film.
+func (p *postgresRepository) GetCredit(actorId, filmId int) (*service.Credit, error) {
 	var (
-		data  []service.Actor
+		data  []service.Credit
 		query = `
-		SELECT actor_name, sex, bdate, list_film
-		FROM %[1]s 
-		WHERE actor_name = $1
+		SELECT af.actor_id, a.person_name, COALESCE(af.character_name, '') AS character_name,
+		       af.credit_type, af.billing_order
+		FROM %[1]s af
+		JOIN %[2]s a ON a.id = af.actor_id
+		WHERE af.actor_id = $1 AND af.film_id = $2
 		`
-
-		values = []any{name}
 	)
 
-	query = fmt.Sprintf(query, cconstant.ActorDB)
+	query = fmt.Sprintf(query, cconstant.ActorFilmDB, cconstant.PersonDB)
 
-	if err := p.db.Select(&data, query, values...); err != nil {
-		return &service.Actor{}, err
+	if err := p.db.Select(&data, query, actorId, filmId); err != nil {
+		return nil, err
 	}
 
 	if len(data) == 0 {
-		return &service.Actor{}, fmt.Errorf("no actor")
+		return nil, fmt.Errorf("couldn't find relation: %w", service.ErrNotFound)
 	}
 
 	return &data[0], nil
 }
 
-func (p *postgresRepository) GetActors(params *service.DetailsParams) ([]service.Actor, error) {
+func (p *postgresRepository) UpdateCredit(params *service.UpdateCreditParams) error {
 	var (
-		data  []service.Actor
 		query = `
-		SELECT actor_name, sex, bdate, list_film
-		FROM %[1]s
-		ORDER BY `
+		UPDATE %[1]s SET %[2]s
+		WHERE actor_id = $%[3]d AND film_id = $%[4]d
+		`
+
+		sets   []string
+		values []any
 	)
 
-	query += params.Sort
+	if params.Character != nil {
+		values = append(values, *params.Character)
+		sets = append(sets, fmt.Sprintf("character_name = NULLIF($%d, '')", len(values)))
+	}
+	if params.Type != nil {
+		values = append(values, *params.Type)
+		sets = append(sets, fmt.Sprintf("credit_type = $%d", len(values)))
+	}
+	if params.Order != nil {
+		values = append(values, *params.Order)
+		sets = append(sets, fmt.Sprintf("billing_order = $%d", len(values)))
+	}
+	values = append(values, params.ActorId, params.FilmId)
+
+	if err := requireLive(p.db, cconstant.PersonDB, "actor", params.ActorId); err != nil {
+		return err
+	}
+	if err := requireLive(p.db, cconstant.FilmDB, "film", params.FilmId); err != nil {
+		return err
+	}
 
-	query = fmt.Sprintf(query, cconstant.ActorDB)
+	query = fmt.Sprintf(query, cconstant.ActorFilmDB, strings.Join(sets, ", "), len(values)-1, len(values))
 
-	if err := p.db.Select(&data, query); err != nil {
-		return data, err
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
 	}
 
-	if len(data) == 0 {
-		return data, fmt.Errorf("no data")
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("couldn't find relation: %w", service.ErrNotFound)
 	}
 
-	return data, nil
+	return nil
 }
 
-func (p *postgresRepository) DeleteActor(name string) error {
+func (p *postgresRepository) AddCrew(params *service.CrewParams) error {
 	var (
 		query = `
-		DELETE FROM %[1]s 
-		WHERE actor_name = $1
+		INSERT INTO %[1]s (film_id, person_id, job)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (film_id, person_id, job) DO NOTHING
 		`
 
-		values = []any{name}
+		values = []any{params.FilmId, params.PersonId, params.Job}
 	)
 
-	query = fmt.Sprintf(query, cconstant.ActorDB)
+	if err := requireLive(p.db, cconstant.PersonDB, "person", params.PersonId); err != nil {
+		return err
+	}
+	if err := requireLive(p.db, cconstant.FilmDB, "film", params.FilmId); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(query, cconstant.FilmCrewDB)
 
 	if _, err := p.db.Exec(query, values...); err != nil {
+		return translateError(err)
+	}
+
+	return nil
+}
+
+func (p *postgresRepository) DeleteCrew(params *service.CrewParams) error {
+	var (
+		query = `
+		DELETE FROM %[1]s
+		WHERE film_id = $1 AND person_id = $2 AND job = $3
+		`
+
+		values = []any{params.FilmId, params.PersonId, params.Job}
+	)
+
+	if err := requireLive(p.db, cconstant.PersonDB, "person", params.PersonId); err != nil {
+		return err
+	}
+	if err := requireLive(p.db, cconstant.FilmDB, "film", params.FilmId); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(query, cconstant.FilmCrewDB)
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
 		return err
 	}
 
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("couldn't find relation: %w", service.ErrNotFound)
+	}
+
 	return nil
 }
 
-func (p *postgresRepository) UpdateActor(name string, params *service.Actor) error {
+func (p *postgresRepository) AddGenresByFilm(params *service.AddGenresByFilmParams) error {
 	var (
-		query  string = `UPDATE %[1]s SET `
-		values []any
-		cnt    = 1
+		query = `
+		INSERT INTO %[1]s (film_id, genre_id)
+		VALUES ($1, $2)
+		ON CONFLICT (film_id, genre_id) DO NOTHING
+		`
 	)
 
-	var subQuery string
-	if params.Name != "" {
-		subQuery += " actor_name"
-		cnt++
-		values = append(values, params.Name)
+	if err := requireLive(p.db, cconstant.FilmDB, "film", params.FilmId); err != nil {
+		return err
 	}
-	if params.Sex != "" {
-		if cnt > 1 {
-			subQuery += fmt.Sprintf(", sex")
-		} else {
-			subQuery += fmt.Sprintf(" sex")
-		}
-		cnt++
-		values = append(values, params.Sex)
-	}
-	if params.BDate != "" {
-		if cnt > 1 {
-			subQuery += fmt.Sprintf(", bdate")
-		} else {
-			subQuery += fmt.Sprintf("bdate")
-		}
-		cnt++
-		values = append(values, params.BDate)
-	}
-	if len(values) == 1 {
-		query += fmt.Sprintf(" %s = \n $1 WHERE actor_name = $2", subQuery)
-	} else {
-		query += fmt.Sprintf("( %s ) = \n(", subQuery)
-		for i := 1; i < len(values)+1; i++ {
-			if i == len(values) {
-				query += fmt.Sprintf("$%d)\n WHERE actor_name = $%d", i, i+1)
-				continue
+
+	query = fmt.Sprintf(query, cconstant.FilmGenreDB)
+
+	return p.transact(context.Background(), func(tx *sqlx.Tx) error {
+		for _, genreId := range params.GenreIds {
+			if _, err := tx.Exec(query, params.FilmId, genreId); err != nil {
+				return translateError(err)
 			}
-			query += fmt.Sprintf("$%d, ", i)
 		}
-	}
 
-	values = append(values, name)
+		return nil
+	})
+}
+
+func (p *postgresRepository) DeleteFilmGenre(params *service.DeleteFilmGenreParams) error {
+	var (
+		query = `
+		DELETE FROM %[1]s
+		WHERE film_id = $1 AND genre_id = $2
+		`
 
-	// -----------------------------------------------------------------------------------------------------------------------------
+		values = []any{params.FilmId, params.GenreId}
+	)
 
-	query = fmt.Sprintf(query, cconstant.ActorDB)
+	if err := requireLive(p.db, cconstant.FilmDB, "film", params.FilmId); err != nil {
+		return err
+	}
 
-	// -----------------------------------------------------------------------------------------------------------------------------
+	query = fmt.Sprintf(query, cconstant.FilmGenreDB)
 
-	if _, err := p.db.Exec(query, values...); err != nil {
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
 		return err
 	}
 
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("couldn't find relation: %w", service.ErrNotFound)
+	}
+
 	return nil
 }
 
-func (p *postgresRepository) SearchActor(pattern string) ([]string, error) {
+func (p *postgresRepository) addActorFilm(tx *sqlx.Tx, actorId, filmId int) error {
 	var (
-		data  []string
 		query = `
-		SELECT actor_name
-		FROM %[1]s
-		WHERE actor_name LIKE `
-	)
-	query = fmt.Sprintf(query, cconstant.ActorDB)
+		INSERT INTO %[1]s (actor_id, film_id)
+		VALUES ($1, $2)
+		ON CONFLICT (actor_id, film_id) DO NOTHING
+		`
 
-	query += "'%" + pattern + "%'"
+		values = []any{actorId, filmId}
+	)
 
-	if err := p.db.Select(&data, query); err != nil {
-		return data, err
+	if err := requireLive(tx, cconstant.PersonDB, "actor", actorId); err != nil {
+		return err
+	}
+	if err := requireLive(tx, cconstant.FilmDB, "film", filmId); err != nil {
+		return err
 	}
 
-	if len(data) == 0 {
-		return data, fmt.Errorf("no data")
+	query = fmt.Sprintf(query, cconstant.ActorFilmDB)
+
+	if _, err := tx.Exec(query, values...); err != nil {
+		return translateError(err)
 	}
 
-	return data, nil
+	return nil
 }
 
-// ----------------------------------------------------- FILM ----------------------------------------------------------
+// ----------------------------------------------------- Trash ----------------------------------------------------------
 
-func (p *postgresRepository) CreateFilm(params *service.Film) error {
+// trashTables maps the kinds of the trash onto their tables and name columns.
+var trashTables = map[string][2]string{
+	"actors": {cconstant.PersonDB, "person_name"},
+	"films":  {cconstant.FilmDB, "film_name"},
+}
+
+var trashSortColumns = map[string]sortColumn[service.TrashItem]{
+	"deleted_at": {expr: "t.deleted_at", cast: "timestamptz", desc: true, key: func(t *service.TrashItem) string {
+		return t.DeletedAt.Format(time.RFC3339Nano)
+	}},
+}
+
+// softDelete moves a row to the trash; a version other than 0 has to be the
+// current one.
+func (p *postgresRepository) softDelete(table, entity string, id, userId, version int) error {
 	var (
 		query = `
-		INSERT INTO %[1]s (film_name, release_date, rating, description)
-		VALUES ($1, $2, $3, $4)`
+		UPDATE %[1]s SET deleted_at = now(), deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL AND ($3 = 0 OR version = $3)
+		`
 
-		values = []any{params.Name, params.RDate, params.Rating, params.Desc}
+		values = []any{id, userId, version}
 	)
 
-	query = fmt.Sprintf(query, cconstant.FilmDB)
+	query = fmt.Sprintf(query, table)
 
-	if _, err := p.db.Exec(query, values...); err != nil {
-		return err
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return p.unchanged(table, entity, id, version)
 	}
 
 	return nil
 }
 
-func (p *postgresRepository) GetFilm(name string) (*service.Film, error) {
+// unchanged explains why a conditional update changed no row: the row is
+// gone, or it has another version than the expected one.
+func (p *postgresRepository) unchanged(table, entity string, id, version int) error {
+	if version == 0 {
+		return fmt.Errorf("no %s: %w", entity, service.ErrNotFound)
+	}
+
+	if err := requireLive(p.db, table, entity, id); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%s is no longer version %d: %w", entity, version, service.ErrVersionMismatch)
+}
+
+// restore fails with service.ErrAlreadyExists when the name was taken again meanwhile.
+func (p *postgresRepository) restore(table, entity string, id int) error {
 	var (
-		data  []service.Film
 		query = `
-		SELECT film_name, release_date, rating, description, list_actor
-		FROM %[1]s 
-		WHERE film_name = $1
+		UPDATE %[1]s SET deleted_at = NULL, deleted_by = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
 		`
 
-		values = []any{name}
+		values = []any{id}
 	)
 
-	query = fmt.Sprintf(query, cconstant.FilmDB)
+	query = fmt.Sprintf(query, table)
 
-	if err := p.db.Select(&data, query, values...); err != nil {
-		return &service.Film{}, err
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
 	}
 
-	if len(data) == 0 {
-		return &service.Film{}, fmt.Errorf("no film")
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("no deleted %s: %w", entity, service.ErrNotFound)
 	}
 
-	return &data[0], nil
+	return nil
 }
 
-func (p *postgresRepository) GetFilms(params *service.DetailsParams) ([]service.Film, error) {
+// GetTrash lists the deleted actors or films, the latest deleted first.
+func (p *postgresRepository) GetTrash(kind string, params *service.DetailsParams) (*service.TrashPage, error) {
 	var (
-		data  []service.Film
+		data  = make([]service.TrashItem, 0, params.Limit+1)
+		total int
 		query = `
-		SELECT film_name, release_date, rating, description, list_actor
-		FROM %[1]s
-		ORDER BY `
-
-		//values = []any{params.Sort}
+		SELECT t.id, t.%[2]s AS name, t.deleted_at, t.deleted_by
+		FROM %[1]s t
+		%[3]s
+		ORDER BY %[4]s
+		LIMIT %[5]d
+		`
+		countQuery = `SELECT count(*) FROM %[1]s t %[2]s`
 	)
-	query += params.Sort
-	if params.Sort == "rating" {
-		query += fmt.Sprintf(" DESC")
+
+	table, ok := trashTables[kind]
+	if !ok {
+		return nil, fmt.Errorf("no trash of %s: %w", kind, service.ErrNotFound)
 	}
 
-	query = fmt.Sprintf(query, cconstant.FilmDB)
+	sort := "deleted_at"
+	col := trashSortColumns[sort]
 
-	//fmt.Println(query, params.Sort)
+	w := &whereBuilder{}
+	w.add("t.deleted_at IS NOT NULL")
 
-	if err := p.db.Select(&data, query); err != nil {
-		return data, err
+	countQuery = fmt.Sprintf(countQuery, table[0], w)
+	if err := p.db.Get(&total, countQuery, w.values...); err != nil {
+		return nil, err
 	}
 
-	if len(data) == 0 {
-		return data, fmt.Errorf("no data")
+	order := keyset(col, "t.id", params.Order, params.Cursor, w)
+
+	query = fmt.Sprintf(query, table[0], table[1], w, order, params.Limit+1)
+
+	if err := p.db.Select(&data, query, w.values...); err != nil {
+		return nil, err
 	}
 
-	return data, nil
+	items, next := nextCursor(data, params, sort, col, func(t *service.TrashItem) int { return t.Id })
+
+	return &service.TrashPage{Items: items, NextCursor: next, Total: total}, nil
 }
 
-func (p *postgresRepository) DeleteFilm(name string) error {
+// PurgeTrash deletes for good the actors and films deleted before the given
+// time, their relations, reviews, watchlist entries and revisions go with them.
+func (p *postgresRepository) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
 	var (
 		query = `
-		DELETE FROM %[1]s 
-		WHERE film_name = $1
+		DELETE FROM %[1]s
+		WHERE deleted_at < $1
+		`
+		revisionQuery = `
+		DELETE FROM %[1]s
+		WHERE entity = $1 AND entity_id IN (SELECT id FROM %[2]s WHERE deleted_at < $2)
 		`
 
-		values = []any{name}
+		purged int
 	)
 
-	query = fmt.Sprintf(query, cconstant.FilmDB)
+	err := p.transact(ctx, func(tx *sqlx.Tx) error {
+		for _, entity := range []string{"film", "actor"} {
+			table := revisionTables[entity]
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf(revisionQuery, cconstant.RevisionDB, table), entity, before); err != nil {
+				return err
+			}
 
-	if _, err := p.db.Exec(query, values...); err != nil {
+			res, err := tx.ExecContext(ctx, fmt.Sprintf(query, table), before)
+			if err != nil {
+				return err
+			}
+
+			affected, _ := res.RowsAffected()
+			purged += int(affected)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// requireLive reports a deleted row as missing; the foreign keys still see it.
+func requireLive(q sqlx.Queryer, table, entity string, id int) error {
+	var (
+		live  bool
+		query = `SELECT EXISTS (SELECT 1 FROM %[1]s WHERE id = $1 AND deleted_at IS NULL)`
+	)
+
+	if err := sqlx.Get(q, &live, fmt.Sprintf(query, table), id); err != nil {
 		return err
 	}
 
+	if !live {
+		return fmt.Errorf("no %s: %w", entity, service.ErrNotFound)
+	}
+
 	return nil
 }
 
-func (p *postgresRepository) UpdateFilm(name string, params *service.Film) error {
+// ----------------------------------------------------- Revisions ----------------------------------------------------------
+
+// revisionTables are the tables of the entities that keep revisions.
+var revisionTables = map[string]string{"actor": cconstant.PersonDB, "film": cconstant.FilmDB}
+
+var revisionSortColumns = map[string]sortColumn[service.Revision]{
+	"number": {expr: "v.number", cast: "integer", desc: true, key: func(v *service.Revision) string { return strconv.Itoa(v.Number) }},
+}
+
+// AddRevision stores rev.Data as the next revision of the entity and returns
+// its number. An entity without revisions gets baseline as revision 1 first,
+// when it is set. The entity row is locked, so concurrent changes are
+// numbered one after another.
+func (p *postgresRepository) AddRevision(ctx context.Context, rev *service.Revision, baseline service.RawJSON) (int, error) {
 	var (
-		query  string = `UPDATE %[1]s SET `
-		values []any
-		cnt    = 1
+		lockQuery   = `SELECT id FROM %[1]s WHERE id = $1 FOR UPDATE`
+		numberQuery = `SELECT COALESCE(MAX(number), 0) FROM %[1]s WHERE entity = $1 AND entity_id = $2`
+		query       = `
+		INSERT INTO %[1]s (entity, entity_id, number, user_id, data)
+		VALUES ($1, $2, $3, $4, CAST($5 AS jsonb))
+		`
+
+		number int
+		userId any
 	)
 
-	var subQuery string
-	if params.Name != "" {
-		subQuery += " film_name"
-		cnt++
-		values = append(values, params.Name)
+	table, ok := revisionTables[rev.Entity]
+	if !ok {
+		return 0, fmt.Errorf("no revisions of %s: %w", rev.Entity, service.ErrNotFound)
 	}
-	if params.RDate != "" {
-		if cnt > 1 {
-			subQuery += fmt.Sprintf(", release_date")
-		} else {
-			subQuery += fmt.Sprintf(" release_date")
-		}
-		cnt++
-		values = append(values, params.RDate)
-	}
-	if params.Rating != 0 {
-		if cnt > 1 {
-			subQuery += fmt.Sprintf(", rating")
-		} else {
-			subQuery += fmt.Sprintf("rating")
+	if rev.UserId != nil {
+		userId = *rev.UserId
+	}
+
+	err := p.transact(ctx, func(tx *sqlx.Tx) error {
+		var id int
+		if err := tx.GetContext(ctx, &id, fmt.Sprintf(lockQuery, table), rev.EntityId); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("no %s: %w", rev.Entity, service.ErrNotFound)
+			}
+			return err
 		}
-		cnt++
-		values = append(values, params.Rating)
-	}
-	if params.Desc != "" {
-		if cnt > 1 {
-			subQuery += fmt.Sprintf(", description")
-		} else {
-			subQuery += fmt.Sprintf("description")
+
+		if err := tx.GetContext(ctx, &number, fmt.Sprintf(numberQuery, cconstant.RevisionDB), rev.Entity, rev.EntityId); err != nil {
+			return err
 		}
-		cnt++
-		values = append(values, params.Desc)
-	}
-	if len(values) == 1 {
-		query += fmt.Sprintf(" %s = \n $1 WHERE film_name = $2", subQuery)
-	} else {
-		query += fmt.Sprintf("( %s ) = \n(", subQuery)
-		for i := 1; i < len(values)+1; i++ {
-			if i == len(values) {
-				query += fmt.Sprintf("$%d)\n WHERE film_name = $%d", i, i+1)
-				continue
+
+		query = fmt.Sprintf(query, cconstant.RevisionDB)
+
+		if number == 0 && len(baseline) > 0 {
+			number++
+			if _, err := tx.ExecContext(ctx, query, rev.Entity, rev.EntityId, number, nil, string(baseline)); err != nil {
+				return err
 			}
-			query += fmt.Sprintf("$%d, ", i)
 		}
+
+		number++
+		if _, err := tx.ExecContext(ctx, query, rev.Entity, rev.EntityId, number, userId, string(rev.Data)); err != nil {
+			return translateError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	values = append(values, name)
+	return number, nil
+}
 
-	// -----------------------------------------------------------------------------------------------------------------------------
+// GetRevisions lists the revisions of an entity, the latest first.
+func (p *postgresRepository) GetRevisions(entity string, entityId int, params *service.DetailsParams) (*service.RevisionsPage, error) {
+	var (
+		data  = make([]service.Revision, 0, params.Limit+1)
+		total int
+		query = `
+		SELECT v.entity, v.entity_id, v.number, v.user_id, v.data, v.created_at
+		FROM %[1]s v
+		%[2]s
+		ORDER BY %[3]s
+		LIMIT %[4]d
+		`
+		countQuery = `SELECT count(*) FROM %[1]s v %[2]s`
+	)
 
-	query = fmt.Sprintf(query, cconstant.FilmDB)
+	sort := "number"
+	col := revisionSortColumns[sort]
 
-	// -----------------------------------------------------------------------------------------------------------------------------
+	w := &whereBuilder{}
+	w.add("v.entity = ?", entity)
+	w.add("v.entity_id = ?", entityId)
 
-	if _, err := p.db.Exec(query, values...); err != nil {
-		return err
+	countQuery = fmt.Sprintf(countQuery, cconstant.RevisionDB, w)
+	if err := p.db.Get(&total, countQuery, w.values...); err != nil {
+		return nil, err
 	}
 
-	return nil
+	order := keyset(col, "v.number", params.Order, params.Cursor, w)
+
+	query = fmt.Sprintf(query, cconstant.RevisionDB, w, order, params.Limit+1)
+
+	if err := p.db.Select(&data, query, w.values...); err != nil {
+		return nil, err
+	}
+
+	items, next := nextCursor(data, params, sort, col, func(v *service.Revision) int { return v.Number })
+
+	return &service.RevisionsPage{Items: items, NextCursor: next, Total: total}, nil
 }
 
-func (p *postgresRepository) SearchFilms(pattern string) ([]string, error) {
+func (p *postgresRepository) GetRevision(entity string, entityId, number int) (*service.Revision, error) {
 	var (
-		data  []string
+		data  []service.Revision
 		query = `
-		SELECT film_name
-		FROM %[1]s
-		WHERE film_name LIKE `
+		SELECT v.entity, v.entity_id, v.number, v.user_id, v.data, v.created_at
+		FROM %[1]s v
+		WHERE v.entity = $1 AND v.entity_id = $2 AND v.number = $3
+		`
 	)
-	query = fmt.Sprintf(query, cconstant.FilmDB)
 
-	query += "'%" + pattern + "%'"
+	query = fmt.Sprintf(query, cconstant.RevisionDB)
 
-	if err := p.db.Select(&data, query); err != nil {
-		return data, err
+	if err := p.db.Select(&data, query, entity, entityId, number); err != nil {
+		return nil, err
 	}
 
 	if len(data) == 0 {
-		return data, fmt.Errorf("no data")
+		return nil, fmt.Errorf("no revision %d of %s: %w", number, entity, service.ErrNotFound)
 	}
 
-	return data, nil
+	return &data[0], nil
 }
 
-// ----------------------------------------------------- Relations ----------------------------------------------------------
+// ----------------------------------------------------- Audit ----------------------------------------------------------
 
-func (p *postgresRepository) AddFilmsByActor(params *service.AddFilmsByActorParams) error {
+var auditSortColumns = map[string]sortColumn[service.AuditEntry]{
+	"created_at": {expr: "l.created_at", cast: "timestamptz", desc: true, key: func(e *service.AuditEntry) string {
+		return e.CreatedAt.Format(time.RFC3339Nano)
+	}},
+}
+
+// AddAudit appends an entry to the audit log. A zero user or entity id is
+// stored as null.
+func (p *postgresRepository) AddAudit(ctx context.Context, entry *service.AuditEntry) error {
 	var (
-		query []string = []string{`
-			UPDATE %[1]s SET list_film = (
-							 SELECT array_agg(distinct e) FROM UNNEST(list_film || '{"%[3]s"}') e) 
-			WHERE actor_name = '%[4]s';
-		`}
-		err error
-		res sql.Result
+		query = `
+		INSERT INTO %[1]s (user_id, entity, entity_id, operation, before, after, request_id)
+		VALUES (NULLIF($1, 0), $2, NULLIF($3, 0), $4, CAST(NULLIF($5, '') AS jsonb), CAST(NULLIF($6, '') AS jsonb), NULLIF($7, ''))
+		`
+
+		userId, entityId int
 	)
 
-	for _, film := range params.Films {
-		subQuery := `
-		UPDATE %[2]s SET list_actor = (
-		                 SELECT array_agg(distinct e) FROM UNNEST(list_actor || '{"%[4]s"}') e) 
-		WHERE film_name = '`
-		subQuery += film + "';\n"
-		query = append(query, subQuery)
+	if entry.UserId != nil {
+		userId = *entry.UserId
 	}
-
-	tx, err := p.db.Begin()
-	if err != nil {
-		return err
+	if entry.EntityId != nil {
+		entityId = *entry.EntityId
 	}
 
-	for _, q := range query {
-		q = fmt.Sprintf(q, cconstant.ActorDB, cconstant.FilmDB, strings.Join(params.Films[:], "\",\""), params.Actor)
-		fmt.Println(q)
-		if res, err = tx.Exec(q); err != nil {
-			tx.Rollback()
-			return err
-		}
-		if affected, _ := res.RowsAffected(); affected == 0 {
-			tx.Rollback()
-			return fmt.Errorf("couldn't find film or actor")
-		}
-	}
+	values := []any{userId, entry.Entity, entityId, entry.Operation, string(entry.Before), string(entry.After), entry.RequestId}
 
-	return tx.Commit()
+	query = fmt.Sprintf(query, cconstant.AuditDB)
+
+	_, err := p.db.ExecContext(ctx, query, values...)
+
+	return err
 }
 
-func (p *postgresRepository) AddActorsByFilm(params *service.AddActorsByFilmParams) error {
+// GetAudit lists the audit trail matching the filter, the latest change first.
+func (p *postgresRepository) GetAudit(filter *service.AuditFilter, params *service.DetailsParams) (*service.AuditPage, error) {
 	var (
-		query []string = []string{`
-			UPDATE %[1]s SET list_actor = (
-							 SELECT array_agg(distinct e) FROM UNNEST(list_actor || '{"%[3]s"}') e) 
-			WHERE film_name = '%[4]s';
-		`}
-		//values = []any{params.Film}
-		err error
-		res sql.Result
+		data  = make([]service.AuditEntry, 0, params.Limit+1)
+		total int
+		query = `
+		SELECT l.id, l.user_id, l.entity, l.entity_id, l.operation, l.before, l.after,
+		       COALESCE(l.request_id, '') AS request_id, l.created_at
+		FROM %[1]s l
+		%[2]s
+		ORDER BY %[3]s
+		LIMIT %[4]d
+		`
+		countQuery = `SELECT count(*) FROM %[1]s l %[2]s`
 	)
 
-	for _, actor := range params.Actors {
-		subQuery := `
-		UPDATE %[2]s SET list_film = (
-		                 SELECT array_agg(distinct e) FROM UNNEST(list_film || '{"%[4]s"}') e) 
-		WHERE actor_name = '`
-		subQuery += actor + "';\n"
-		query = append(query, subQuery)
+	sort := "created_at"
+	col := auditSortColumns[sort]
+
+	w := &whereBuilder{}
+	if filter.Entity != "" {
+		w.add("l.entity = ?", filter.Entity)
+	}
+	if filter.EntityId != 0 {
+		w.add("l.entity_id = ?", filter.EntityId)
+	}
+	if filter.UserId != 0 {
+		w.add("l.user_id = ?", filter.UserId)
+	}
+	if filter.Operation != "" {
+		w.add("l.operation = ?", filter.Operation)
+	}
+	if filter.From != nil {
+		w.add("l.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		w.add("l.created_at < ?", *filter.To)
 	}
 
-	tx, err := p.db.Begin()
-	if err != nil {
-		return err
+	countQuery = fmt.Sprintf(countQuery, cconstant.AuditDB, w)
+	if err := p.db.Get(&total, countQuery, w.values...); err != nil {
+		return nil, err
 	}
 
-	for _, q := range query {
-		q = fmt.Sprintf(q, cconstant.FilmDB, cconstant.ActorDB, strings.Join(params.Actors[:], "\",\""), params.Film)
-		fmt.Println(q)
-		if res, err = tx.Exec(q); err != nil {
-			tx.Rollback()
-			return err
-		}
+	order := keyset(col, "l.id", params.Order, params.Cursor, w)
 
-		if affected, _ := res.RowsAffected(); affected == 0 {
-			tx.Rollback()
-			return fmt.Errorf("couldn't find film or actor")
-		}
+	query = fmt.Sprintf(query, cconstant.AuditDB, w, order, params.Limit+1)
+
+	if err := p.db.Select(&data, query, w.values...); err != nil {
+		return nil, err
 	}
 
-	return tx.Commit()
+	items, next := nextCursor(data, params, sort, col, func(e *service.AuditEntry) int { return e.Id })
+
+	return &service.AuditPage{Items: items, NextCursor: next, Total: total}, nil
 }
 
-func (p *postgresRepository) DeleteActorFilm(params *service.DeleteActorFilmParams) error {
-	var (
-		query []string = []string{`
-			UPDATE %[1]s SET list_actor = array_remove(list_actor, '%[3]s') 
-			WHERE film_name = '%[4]s';`, `
-			UPDATE %[2]s SET list_film = array_remove(list_film, '%[4]s') 
-			WHERE actor_name = '%[3]s';
-		`}
-		err error
-		res sql.Result
-	)
-	ctx := context.Background()
-	tx, err := p.db.BeginTx(ctx, nil)
-	if err != nil {
+// ----------------------------------------------------- Errors ----------------------------------------------------------
+
+// translateError maps constraint violations onto the service errors the handlers understand.
+func translateError(err error) error {
+	var pgErr pgx.PgError
+	if !errors.As(err, &pgErr) {
 		return err
 	}
 
-	for _, q := range query {
-		q = fmt.Sprintf(q, cconstant.FilmDB, cconstant.ActorDB, params.Actor, params.Film)
-		fmt.Println(q)
-		if res, err = tx.ExecContext(ctx, q); err != nil {
-			tx.Rollback()
-			return err
-		}
-		if affected, _ := res.RowsAffected(); affected == 0 {
-			tx.Rollback()
-			return fmt.Errorf("couldn't find film or actor")
-		}
+	switch pgErr.Code {
+	case uniqueViolation:
+		return fmt.Errorf("%s: %w", pgErr.Detail, service.ErrAlreadyExists)
+	case foreignKeyViolation:
+		// Detail names the missing row, e.g. Key (genre_id)=(5) is not present in table "genre".
+		return fmt.Errorf("%s: %w", pgErr.Detail, service.ErrNotFound)
 	}
 
-	return tx.Commit()
+	return err
 }
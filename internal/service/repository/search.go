@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"film_library/internal/cconstant"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"slices"
+	"strconv"
+)
+
+// headlineOptions configure ts_headline: up to two fragments of the matched text
+// with the hits wrapped in <b></b>.
+const headlineOptions = `StartSel=<b>, StopSel=</b>, MaxWords=35, MinWords=15, MaxFragments=2`
+
+// actorDocument is the tsvector people are searched by. Names are not stemmed,
+// so it always uses the "simple" configuration. Must match actor_fts_idx.
+const actorDocument = `to_tsvector('simple'::regconfig, a.person_name)`
+
+// searchConfig returns lang as a regconfig literal. The configuration can't be
+// passed as a placeholder or the planner won't match the expression indexes,
+// so only the whitelisted names are ever inlined into the query.
+func searchConfig(lang string) (string, error) {
+	if !slices.Contains(cconstant.SearchLanguages, lang) {
+		return "", fmt.Errorf("unsupported search language %q", lang)
+	}
+
+	return fmt.Sprintf("'%s'::regconfig", lang), nil
+}
+
+// filmDocument returns the tsvector films are searched by: the name weighs more
+// than the description. Must match the film_fts_<language>_idx indexes.
+func filmDocument(config string) string {
+	return fmt.Sprintf(
+		`(setweight(to_tsvector(%[1]s, f.film_name), 'A') || setweight(to_tsvector(%[1]s, COALESCE(f.description, '')), 'B'))`,
+		config)
+}
+
+// withSimilarityThreshold runs fn in a transaction in which the "<%" operator
+// matches at threshold. The setting is local to the transaction, so it never
+// leaks to other queries sharing the pooled connection.
+func (p *postgresRepository) withSimilarityThreshold(threshold float32, fn func(tx *sqlx.Tx) error) error {
+	return p.transact(context.Background(), func(tx *sqlx.Tx) error {
+		value := strconv.FormatFloat(float64(threshold), 'f', -1, 32)
+		if _, err := tx.Exec(`SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`, value); err != nil {
+			return err
+		}
+
+		return fn(tx)
+	})
+}
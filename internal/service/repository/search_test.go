@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSearchConfig(t *testing.T) {
+	config, err := searchConfig("russian")
+	require.NoError(t, err)
+	require.Equal(t, "'russian'::regconfig", config)
+	require.Contains(t, filmDocument(config), "to_tsvector('russian'::regconfig, f.film_name)")
+
+	_, err = searchConfig("russian'::regconfig, f.film_name); --")
+	require.EqualError(t, err, `unsupported search language "russian'::regconfig, f.film_name); --"`)
+}
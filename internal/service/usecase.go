@@ -1,21 +1,69 @@
 package service
 
+import (
+	"context"
+	"film_library/internal/recommend"
+)
+
+// Usecase mutations take the context of the request; the Origin in it is
+// written to the audit log together with the change.
 type Usecase interface {
-	CreateActor(params *Actor) error
-	GetActor(name string) (*Actor, error)
-	GetActors(params *DetailsParams) ([]Actor, error)
-	UpdateActor(name string, params *Actor) error
-	DeleteActor(name string) error
-	SearchActor(pattern string) ([]string, error)
-
-	CreateFilm(params *Film) error
-	GetFilm(name string) (*Film, error)
-	GetFilms(params *DetailsParams) ([]Film, error)
-	UpdateFilm(name string, params *Film) error
-	DeleteFilm(name string) error
-	SearchFilms(pattern string) ([]string, error)
-
-	AddFilmsByActor(params *AddFilmsByActorParams) error
-	AddActorsByFilm(params *AddActorsByFilmParams) error
-	DeleteActorFilm(params *DeleteActorFilmParams) error
+	CreateActor(ctx context.Context, params *Actor) (int, error)
+	GetActor(id int) (*Actor, error)
+	GetActorId(name string) (int, error)
+	GetActors(params *DetailsParams) (*ActorsPage, error)
+	UpdateActor(ctx context.Context, id int, params *ActorPatch) error
+	DeleteActor(ctx context.Context, id, userId, version int) error
+	RestoreActor(ctx context.Context, id int) error
+	SearchActor(params *SearchParams) (*ActorSearchResult, error)
+	GetActorPath(ctx context.Context, params *PathParams) (*ActorPath, error)
+	GetActorFilms(id int, params *DetailsParams) (*FilmsPage, error)
+
+	CreateFilm(ctx context.Context, params *Film) (int, error)
+	GetFilm(id int) (*Film, error)
+	GetFilmId(name string) (int, error)
+	GetFilms(params *DetailsParams) (*FilmsPage, error)
+	UpdateFilm(ctx context.Context, id int, params *FilmPatch) error
+	DeleteFilm(ctx context.Context, id, userId, version int) error
+	RestoreFilm(ctx context.Context, id int) error
+	SearchFilms(params *SearchParams) (*FilmSearchResult, error)
+	GetRelatedFilms(id, limit int) ([]RelatedFilm, error)
+	GetFilmActors(id int, params *DetailsParams) (*ActorsPage, error)
+	GetRecommendations(userId, limit int) ([]Recommendation, error)
+
+	CreateGenre(ctx context.Context, params *Genre) (int, error)
+	GetGenre(id int) (*Genre, error)
+	GetGenreId(name string) (int, error)
+	GetGenres() ([]Genre, error)
+	UpdateGenre(ctx context.Context, id int, params *Genre) error
+	DeleteGenre(ctx context.Context, id int) error
+
+	CreateReview(ctx context.Context, params *Review) (int, error)
+	GetReviews(filmId int, params *DetailsParams) (*ReviewsPage, error)
+	UpdateReview(ctx context.Context, id int, params *Review) error
+	DeleteReview(ctx context.Context, id, userId int) error
+
+	AddFilmsByActor(ctx context.Context, params *AddFilmsByActorParams) error
+	AddActorsByFilm(ctx context.Context, params *AddActorsByFilmParams) error
+	DeleteActorFilm(ctx context.Context, params *DeleteActorFilmParams) error
+	UpdateCredit(ctx context.Context, params *UpdateCreditParams) error
+	AddCrew(ctx context.Context, params *CrewParams) error
+	DeleteCrew(ctx context.Context, params *CrewParams) error
+	AddGenresByFilm(ctx context.Context, params *AddGenresByFilmParams) error
+	DeleteFilmGenre(ctx context.Context, params *DeleteFilmGenreParams) error
+
+	GetTrash(kind string, params *DetailsParams) (*TrashPage, error)
+	PurgeTrash(ctx context.Context) (int, error)
+
+	GetRevisions(entity string, id int, params *DetailsParams) (*RevisionsPage, error)
+	DiffRevisions(entity string, id, from, to int) (*RevisionDiff, error)
+	RevertActor(ctx context.Context, id, number, version int) (int, error)
+	RevertFilm(ctx context.Context, id, number, version int) (int, error)
+
+	GetAudit(filter *AuditFilter, params *DetailsParams) (*AuditPage, error)
+}
+
+// Recommender predicts ratings of the films a user has not rated, see recommend.Recommender.
+type Recommender interface {
+	Recommend(userId, limit int) []recommend.Prediction
 }
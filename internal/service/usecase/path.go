@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"film_library/internal/cconstant"
+	"film_library/internal/service"
+	"fmt"
+)
+
+// step is how the search reached an actor: from prev through film, depth films away from where it started.
+type step struct {
+	prev  int
+	film  int
+	depth int
+}
+
+// side is one half of the bidirectional search.
+type side struct {
+	seen     map[int]step
+	frontier []int
+	depth    int
+}
+
+func newSide(start int) *side {
+	return &side{seen: map[int]step{start: {}}, frontier: []int{start}}
+}
+
+// GetActorPath finds the shortest chain of shared films between two actors.
+// The search runs from both actors at once and always expands the smaller
+// frontier, one query per level, until the halves meet, the chain would be
+// longer than MaxDepth films or the configured timeout runs out. The search
+// stops as well when ctx, the one of the request, is done.
+func (s *ServiceUsecase) GetActorPath(ctx context.Context, params *service.PathParams) (*service.ActorPath, error) {
+	if params.MaxDepth == 0 {
+		params.MaxDepth = cconstant.MaxPathDepth
+	}
+
+	names, err := s.actorNames([]int{params.From, params.To})
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range []int{params.From, params.To} {
+		if _, ok := names[id]; !ok {
+			return nil, fmt.Errorf("no actor with id %d: %w", id, service.ErrNotFound)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Path.Timeout)
+	defer cancel()
+
+	fwd, bwd := newSide(params.From), newSide(params.To)
+	meet, found := params.From, params.From == params.To
+
+	for !found && fwd.depth+bwd.depth < params.MaxDepth {
+		this, other := fwd, bwd
+		if len(bwd.frontier) < len(fwd.frontier) {
+			this, other = bwd, fwd
+		}
+
+		links, err := s.repo.ActorLinks(ctx, this.frontier)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("path search stopped after %s: %w", s.cfg.Path.Timeout, service.ErrTimeout)
+			}
+			return nil, err
+		}
+
+		this.depth++
+		this.frontier = nil
+		closest := 0
+		for _, l := range links {
+			if _, ok := this.seen[l.CoActorId]; ok {
+				continue
+			}
+			this.seen[l.CoActorId] = step{prev: l.ActorId, film: l.FilmId, depth: this.depth}
+			this.frontier = append(this.frontier, l.CoActorId)
+
+			// Every actor reached on this level is this.depth films away, so the
+			// shortest chain goes through the one closest to the other end.
+			if o, ok := other.seen[l.CoActorId]; ok && (!found || o.depth < closest) {
+				meet, closest, found = l.CoActorId, o.depth, true
+			}
+		}
+
+		if len(this.frontier) == 0 {
+			break
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("actors are not connected by %d films or less: %w", params.MaxDepth, service.ErrNotFound)
+	}
+
+	return s.buildPath(fwd.seen, bwd.seen, meet)
+}
+
+// buildPath joins the chain from the first actor to meet with the chain from
+// meet to the second actor and names the actors and films in it.
+func (s *ServiceUsecase) buildPath(fwd, bwd map[int]step, meet int) (*service.ActorPath, error) {
+	var (
+		actors = []int{meet}
+		films  []int
+	)
+
+	for actor := meet; fwd[actor].depth > 0; actor = fwd[actor].prev {
+		actors = append([]int{fwd[actor].prev}, actors...)
+		films = append([]int{fwd[actor].film}, films...)
+	}
+	for actor := meet; bwd[actor].depth > 0; actor = bwd[actor].prev {
+		actors = append(actors, bwd[actor].prev)
+		films = append(films, bwd[actor].film)
+	}
+
+	actorNames, err := s.actorNames(actors)
+	if err != nil {
+		return nil, err
+	}
+
+	filmNames := make(map[int]string, len(films))
+	if len(films) > 0 {
+		data, err := s.repo.GetFilmsByIds(films)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range data {
+			filmNames[f.Id] = f.Name
+		}
+	}
+
+	resp := &service.ActorPath{Degrees: len(films), Chain: make([]service.PathLink, len(actors))}
+	for i, id := range actors {
+		resp.Chain[i] = service.PathLink{ActorId: id, Actor: actorNames[id]}
+		if i < len(films) {
+			resp.Chain[i].FilmId, resp.Chain[i].Film = films[i], filmNames[films[i]]
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *ServiceUsecase) actorNames(ids []int) (map[int]string, error) {
+	data, err := s.repo.GetActorsByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int]string, len(data))
+	for _, a := range data {
+		names[a.Id] = a.Name
+	}
+
+	return names, nil
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"film_library/internal/service"
+	"fmt"
+	"reflect"
+)
+
+// GetRevisions lists the revisions of an actor or a film, the latest first.
+func (s *ServiceUsecase) GetRevisions(entity string, id int, params *service.DetailsParams) (*service.RevisionsPage, error) {
+	page, err := s.repo.GetRevisions(entity, id, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// An entity that was never changed has no revisions, a missing one is not found.
+	if page.Total == 0 {
+		if err = s.requireEntity(entity, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// DiffRevisions lists the fields changed from one revision to another.
+func (s *ServiceUsecase) DiffRevisions(entity string, id, from, to int) (*service.RevisionDiff, error) {
+	var fields [2]map[string]any
+	for i, number := range []int{from, to} {
+		rev, err := s.repo.GetRevision(entity, id, number)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(rev.Data, &fields[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	diff := &service.RevisionDiff{From: from, To: to, Changes: make(map[string]service.FieldChange)}
+	for _, snapshot := range fields {
+		for key := range snapshot {
+			if !reflect.DeepEqual(fields[0][key], fields[1][key]) {
+				diff.Changes[key] = service.FieldChange{From: fields[0][key], To: fields[1][key]}
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+// RevertActor gives the actor the fields of an earlier revision. The revert
+// is a change too and returns the number of the revision it makes. Like an
+// update, a version other than 0 has to be the current one.
+func (s *ServiceUsecase) RevertActor(ctx context.Context, id, number, version int) (int, error) {
+	rev, err := s.repo.GetRevision("actor", id, number)
+	if err != nil {
+		return 0, err
+	}
+
+	var actor service.Actor
+	if err = json.Unmarshal(rev.Data, &actor); err != nil {
+		return 0, err
+	}
+	actor.Version = version
+
+	var revised int
+	err = s.repo.InTx(ctx, func(repo service.Repository) error {
+		before, err := service.Locked(repo, "actor", id, service.ActorSnapshot)
+		if err != nil {
+			return err
+		}
+		if err = repo.ReplaceActor(id, &actor); err != nil {
+			return err
+		}
+
+		after, err := service.ActorSnapshot(repo, id)
+		if err != nil {
+			return err
+		}
+		if err = service.Audit(ctx, repo, "actor", id, "revert", before, after); err != nil {
+			return err
+		}
+
+		revised, err = service.Revise(ctx, repo, "actor", id, before, after)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return revised, nil
+}
+
+// RevertFilm gives the film the fields of an earlier revision, see RevertActor.
+func (s *ServiceUsecase) RevertFilm(ctx context.Context, id, number, version int) (int, error) {
+	rev, err := s.repo.GetRevision("film", id, number)
+	if err != nil {
+		return 0, err
+	}
+
+	var film service.Film
+	if err = json.Unmarshal(rev.Data, &film); err != nil {
+		return 0, err
+	}
+	film.Version = version
+
+	var revised int
+	err = s.repo.InTx(ctx, func(repo service.Repository) error {
+		before, err := service.Locked(repo, "film", id, service.FilmSnapshot)
+		if err != nil {
+			return err
+		}
+		if err = repo.ReplaceFilm(id, &film); err != nil {
+			return err
+		}
+
+		after, err := service.FilmSnapshot(repo, id)
+		if err != nil {
+			return err
+		}
+		if err = service.Audit(ctx, repo, "film", id, "revert", before, after); err != nil {
+			return err
+		}
+
+		revised, err = service.Revise(ctx, repo, "film", id, before, after)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return revised, nil
+}
+
+func (s *ServiceUsecase) requireEntity(entity string, id int) error {
+	var found int
+	switch entity {
+	case "actor":
+		actors, err := s.repo.GetActorsByIds([]int{id})
+		if err != nil {
+			return err
+		}
+		found = len(actors)
+	case "film":
+		films, err := s.repo.GetFilmsByIds([]int{id})
+		if err != nil {
+			return err
+		}
+		found = len(films)
+	}
+
+	if found == 0 {
+		return fmt.Errorf("no %s: %w", entity, service.ErrNotFound)
+	}
+
+	return nil
+}
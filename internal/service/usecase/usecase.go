@@ -1,26 +1,58 @@
 package usecase
 
-import "film_library/internal/service"
+import (
+	"context"
+	"film_library/config"
+	"film_library/internal/service"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
 
 type ServiceUsecase struct {
-	repo service.Repository
+	cfg         *config.Config
+	repo        service.Repository
+	recommender service.Recommender
 }
 
-func NewServiceUsecase(repo service.Repository) service.Usecase {
-	return &ServiceUsecase{repo: repo}
+func NewServiceUsecase(cfg *config.Config, repo service.Repository, recommender service.Recommender) service.Usecase {
+	return &ServiceUsecase{cfg: cfg, repo: repo, recommender: recommender}
 }
 
-func (s *ServiceUsecase) CreateActor(params *service.Actor) error {
-	return s.repo.CreateActor(params)
+func (s *ServiceUsecase) CreateActor(ctx context.Context, params *service.Actor) (int, error) {
+	var id int
+	err := s.repo.InTx(ctx, func(repo service.Repository) error {
+		var err error
+		if id, err = repo.CreateActor(params); err != nil {
+			return err
+		}
+
+		after, err := service.ActorSnapshot(repo, id)
+		if err != nil {
+			return err
+		}
+		if err = service.Audit(ctx, repo, "actor", id, "create", nil, after); err != nil {
+			return err
+		}
+
+		_, err = service.Revise(ctx, repo, "actor", id, nil, after)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
 }
 
-func (s *ServiceUsecase) GetActor(name string) (*service.Actor, error) {
+func (s *ServiceUsecase) GetActor(id int) (*service.Actor, error) {
 	var (
 		resp *service.Actor
 		err  error
 	)
 
-	resp, err = s.repo.GetActor(name)
+	resp, err = s.repo.GetActor(id)
 	if err != nil {
 		return resp, err
 	}
@@ -28,9 +60,18 @@ func (s *ServiceUsecase) GetActor(name string) (*service.Actor, error) {
 	return resp, err
 }
 
-func (s *ServiceUsecase) GetActors(params *service.DetailsParams) ([]service.Actor, error) {
+func (s *ServiceUsecase) GetActorId(name string) (int, error) {
+	ids, err := s.repo.GetActorIds(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return resolveName("actor", name, ids)
+}
+
+func (s *ServiceUsecase) GetActors(params *service.DetailsParams) (*service.ActorsPage, error) {
 	var (
-		resp []service.Actor
+		resp *service.ActorsPage
 		err  error
 	)
 
@@ -42,39 +83,134 @@ func (s *ServiceUsecase) GetActors(params *service.DetailsParams) ([]service.Act
 	return resp, err
 }
 
-func (s *ServiceUsecase) UpdateActor(name string, params *service.Actor) error {
-	return s.repo.UpdateActor(name, params)
+// GetActorFilms lists the films the actor played in, paged like the film list.
+func (s *ServiceUsecase) GetActorFilms(id int, params *service.DetailsParams) (*service.FilmsPage, error) {
+	actors, err := s.repo.GetActorsByIds([]int{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(actors) == 0 {
+		return nil, fmt.Errorf("no actor: %w", service.ErrNotFound)
+	}
+
+	if params.Filter == nil {
+		params.Filter = &service.FilmFilter{}
+	}
+	params.Filter.ActorIds = append(params.Filter.ActorIds, id)
+
+	return s.repo.GetFilms(params)
+}
+
+func (s *ServiceUsecase) UpdateActor(ctx context.Context, id int, params *service.ActorPatch) error {
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		before, err := service.Locked(repo, "actor", id, service.ActorSnapshot)
+		if err != nil {
+			return err
+		}
+		if err = repo.UpdateActor(id, params); err != nil {
+			return err
+		}
+
+		after, err := service.ActorSnapshot(repo, id)
+		if err != nil {
+			return err
+		}
+		if err = service.Audit(ctx, repo, "actor", id, "update", before, after); err != nil {
+			return err
+		}
+
+		_, err = service.Revise(ctx, repo, "actor", id, before, after)
+		return err
+	})
 }
 
-func (s *ServiceUsecase) DeleteActor(name string) error {
-	return s.repo.DeleteActor(name)
+// DeleteActor moves the actor to the trash on behalf of the user; a version
+// other than 0 has to be the current one.
+func (s *ServiceUsecase) DeleteActor(ctx context.Context, id, userId, version int) error {
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		before, err := service.Locked(repo, "actor", id, service.ActorSnapshot)
+		if err != nil {
+			return err
+		}
+		if err = repo.DeleteActor(id, userId, version); err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "actor", id, "delete", before, nil)
+	})
 }
 
-func (s *ServiceUsecase) SearchActor(pattern string) ([]string, error) {
-	var (
-		resp []string
-		err  error
-	)
+func (s *ServiceUsecase) RestoreActor(ctx context.Context, id int) error {
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		if err := repo.RestoreActor(id); err != nil {
+			return err
+		}
 
-	resp, err = s.repo.SearchActor(pattern)
-	if err != nil {
+		after, err := service.ActorSnapshot(repo, id)
+		if err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "actor", id, "restore", nil, after)
+	})
+}
+
+func (s *ServiceUsecase) SearchActor(params *service.SearchParams) (*service.ActorSearchResult, error) {
+	s.searchDefaults(params)
+
+	if params.Mode == "fuzzy" {
+		return s.repo.SimilarActors(params)
+	}
+
+	resp, err := s.repo.SearchActor(params)
+	if err != nil || len(resp.Items) > 0 {
 		return resp, err
 	}
 
-	return resp, err
+	suggestion, err := s.repo.SimilarActors(&service.SearchParams{Query: params.Query, Threshold: params.Threshold, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(suggestion.Items) > 0 {
+		resp.DidYouMean = suggestion.Items[0].Name
+	}
+
+	return resp, nil
 }
 
-func (s *ServiceUsecase) CreateFilm(params *service.Film) error {
-	return s.repo.CreateFilm(params)
+func (s *ServiceUsecase) CreateFilm(ctx context.Context, params *service.Film) (int, error) {
+	var id int
+	err := s.repo.InTx(ctx, func(repo service.Repository) error {
+		var err error
+		if id, err = repo.CreateFilm(params); err != nil {
+			return err
+		}
+
+		after, err := service.FilmSnapshot(repo, id)
+		if err != nil {
+			return err
+		}
+		if err = service.Audit(ctx, repo, "film", id, "create", nil, after); err != nil {
+			return err
+		}
+
+		_, err = service.Revise(ctx, repo, "film", id, nil, after)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
 }
 
-func (s *ServiceUsecase) GetFilm(name string) (*service.Film, error) {
+func (s *ServiceUsecase) GetFilm(id int) (*service.Film, error) {
 	var (
 		resp *service.Film
 		err  error
 	)
 
-	resp, err = s.repo.GetFilm(name)
+	resp, err = s.repo.GetFilm(id)
 	if err != nil {
 		return resp, err
 	}
@@ -82,9 +218,18 @@ func (s *ServiceUsecase) GetFilm(name string) (*service.Film, error) {
 	return resp, err
 }
 
-func (s *ServiceUsecase) GetFilms(params *service.DetailsParams) ([]service.Film, error) {
+func (s *ServiceUsecase) GetFilmId(name string) (int, error) {
+	ids, err := s.repo.GetFilmIds(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return resolveName("film", name, ids)
+}
+
+func (s *ServiceUsecase) GetFilms(params *service.DetailsParams) (*service.FilmsPage, error) {
 	var (
-		resp []service.Film
+		resp *service.FilmsPage
 		err  error
 	)
 
@@ -96,36 +241,552 @@ func (s *ServiceUsecase) GetFilms(params *service.DetailsParams) ([]service.Film
 	return resp, err
 }
 
-func (s *ServiceUsecase) UpdateFilm(name string, params *service.Film) error {
-	return s.repo.UpdateFilm(name, params)
+// GetFilmActors lists the cast of the film, paged like the actor list.
+func (s *ServiceUsecase) GetFilmActors(id int, params *service.DetailsParams) (*service.ActorsPage, error) {
+	films, err := s.repo.GetFilmsByIds([]int{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(films) == 0 {
+		return nil, fmt.Errorf("no film: %w", service.ErrNotFound)
+	}
+
+	params.FilmId = id
+
+	return s.repo.GetActors(params)
+}
+
+func (s *ServiceUsecase) UpdateFilm(ctx context.Context, id int, params *service.FilmPatch) error {
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		before, err := service.Locked(repo, "film", id, service.FilmSnapshot)
+		if err != nil {
+			return err
+		}
+		if err = repo.UpdateFilm(id, params); err != nil {
+			return err
+		}
+
+		after, err := service.FilmSnapshot(repo, id)
+		if err != nil {
+			return err
+		}
+		if err = service.Audit(ctx, repo, "film", id, "update", before, after); err != nil {
+			return err
+		}
+
+		_, err = service.Revise(ctx, repo, "film", id, before, after)
+		return err
+	})
+}
+
+// DeleteFilm moves the film to the trash on behalf of the user.
+func (s *ServiceUsecase) DeleteFilm(ctx context.Context, id, userId, version int) error {
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		before, err := service.Locked(repo, "film", id, service.FilmSnapshot)
+		if err != nil {
+			return err
+		}
+		if err = repo.DeleteFilm(id, userId, version); err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "film", id, "delete", before, nil)
+	})
+}
+
+func (s *ServiceUsecase) RestoreFilm(ctx context.Context, id int) error {
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		if err := repo.RestoreFilm(id); err != nil {
+			return err
+		}
+
+		after, err := service.FilmSnapshot(repo, id)
+		if err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "film", id, "restore", nil, after)
+	})
+}
+
+func (s *ServiceUsecase) SearchFilms(params *service.SearchParams) (*service.FilmSearchResult, error) {
+	s.searchDefaults(params)
+
+	if params.Mode == "fuzzy" {
+		return s.repo.SimilarFilms(params)
+	}
+
+	resp, err := s.repo.SearchFilms(params)
+	if err != nil || len(resp.Items) > 0 {
+		return resp, err
+	}
+
+	suggestion, err := s.repo.SimilarFilms(&service.SearchParams{Query: params.Query, Threshold: params.Threshold, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(suggestion.Items) > 0 {
+		resp.DidYouMean = suggestion.Items[0].Name
+	}
+
+	return resp, nil
+}
+
+func (s *ServiceUsecase) GetRelatedFilms(id, limit int) ([]service.RelatedFilm, error) {
+	return s.repo.RelatedFilms(id, limit)
+}
+
+// GetRecommendations returns the films the recommender expects the user to
+// rate highest. Films deleted since the model was built are skipped.
+func (s *ServiceUsecase) GetRecommendations(userId, limit int) ([]service.Recommendation, error) {
+	predictions := s.recommender.Recommend(userId, limit)
+
+	ids := make([]int, 0, len(predictions))
+	for _, p := range predictions {
+		ids = append(ids, p.FilmId)
+	}
+
+	films, err := s.repo.GetFilmsByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[int]service.Film, len(films))
+	for _, f := range films {
+		byId[f.Id] = f
+	}
+
+	resp := make([]service.Recommendation, 0, len(predictions))
+	for _, p := range predictions {
+		if film, ok := byId[p.FilmId]; ok {
+			resp = append(resp, service.Recommendation{Film: film, PredictedRating: math.Round(p.Score*100) / 100})
+		}
+	}
+
+	return resp, nil
+}
+
+// searchDefaults fills what the request left out from the search config.
+func (s *ServiceUsecase) searchDefaults(params *service.SearchParams) {
+	if params.Language == "" {
+		params.Language = s.cfg.Search.Language
+	}
+	if params.Threshold == 0 {
+		params.Threshold = s.cfg.Search.Threshold
+	}
+}
+
+func (s *ServiceUsecase) CreateGenre(ctx context.Context, params *service.Genre) (int, error) {
+	var id int
+	err := s.repo.InTx(ctx, func(repo service.Repository) error {
+		var err error
+		if id, err = repo.CreateGenre(params); err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "genre", id, "create", nil, service.GenreFields(params))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *ServiceUsecase) GetGenre(id int) (*service.Genre, error) {
+	return s.repo.GetGenre(id)
+}
+
+func (s *ServiceUsecase) GetGenreId(name string) (int, error) {
+	return s.repo.GetGenreId(name)
+}
+
+func (s *ServiceUsecase) GetGenres() ([]service.Genre, error) {
+	return s.repo.GetGenres()
+}
+
+func (s *ServiceUsecase) UpdateGenre(ctx context.Context, id int, params *service.Genre) error {
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		before, err := service.Locked(repo, "genre", id, service.GenreSnapshot)
+		if err != nil {
+			return err
+		}
+		if err = repo.UpdateGenre(id, params); err != nil {
+			return err
+		}
+
+		after, err := service.GenreSnapshot(repo, id)
+		if err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "genre", id, "update", before, after)
+	})
+}
+
+func (s *ServiceUsecase) DeleteGenre(ctx context.Context, id int) error {
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		before, err := service.Locked(repo, "genre", id, service.GenreSnapshot)
+		if err != nil {
+			return err
+		}
+		if err = repo.DeleteGenre(id); err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "genre", id, "delete", before, nil)
+	})
+}
+
+func (s *ServiceUsecase) CreateReview(ctx context.Context, params *service.Review) (int, error) {
+	var id int
+	err := s.repo.InTx(ctx, func(repo service.Repository) error {
+		var err error
+		if id, err = repo.CreateReview(params); err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "review", id, "create", nil, service.ReviewFields(params))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *ServiceUsecase) GetReviews(filmId int, params *service.DetailsParams) (*service.ReviewsPage, error) {
+	return s.repo.GetReviews(filmId, params)
+}
+
+func (s *ServiceUsecase) UpdateReview(ctx context.Context, id int, params *service.Review) error {
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		before, err := service.Locked(repo, "review", id, service.ReviewSnapshot)
+		if err != nil {
+			return err
+		}
+		if err = repo.UpdateReview(id, params); err != nil {
+			return err
+		}
+
+		after, err := service.ReviewSnapshot(repo, id)
+		if err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "review", id, "update", before, after)
+	})
+}
+
+// DeleteReview deletes a review of the user; a userId of 0 deletes the review of anyone.
+func (s *ServiceUsecase) DeleteReview(ctx context.Context, id, userId int) error {
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		before, err := service.Locked(repo, "review", id, service.ReviewSnapshot)
+		if err != nil {
+			return err
+		}
+		if err = repo.DeleteReview(id, userId); err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "review", id, "delete", before, nil)
+	})
+}
+
+// The relations are audited pair by pair under the film they belong to.
+
+func (s *ServiceUsecase) AddFilmsByActor(ctx context.Context, params *service.AddFilmsByActorParams) error {
+	var err error
+
+	if params.Actor != "" {
+		if params.ActorId, err = s.GetActorId(params.Actor); err != nil {
+			return err
+		}
+	}
+
+	for _, film := range params.Films {
+		id, err := s.GetFilmId(film)
+		if err != nil {
+			return err
+		}
+		params.FilmIds = append(params.FilmIds, id)
+	}
+
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		if err := repo.AddFilmsByActor(params); err != nil {
+			return err
+		}
+
+		for _, filmId := range params.FilmIds {
+			if err := service.Audit(ctx, repo, "actor_film", filmId, "create", nil, map[string]any{"actor_id": params.ActorId}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *ServiceUsecase) AddActorsByFilm(ctx context.Context, params *service.AddActorsByFilmParams) error {
+	var err error
+
+	if params.Film != "" {
+		if params.FilmId, err = s.GetFilmId(params.Film); err != nil {
+			return err
+		}
+	}
+
+	for _, actor := range params.Actors {
+		id, err := s.GetActorId(actor)
+		if err != nil {
+			return err
+		}
+		params.ActorIds = append(params.ActorIds, id)
+	}
+
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		if err := repo.AddActorsByFilm(params); err != nil {
+			return err
+		}
+
+		for _, actorId := range params.ActorIds {
+			if err := service.Audit(ctx, repo, "actor_film", params.FilmId, "create", nil, map[string]any{"actor_id": actorId}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
-func (s *ServiceUsecase) DeleteFilm(name string) error {
-	return s.repo.DeleteFilm(name)
+func (s *ServiceUsecase) DeleteActorFilm(ctx context.Context, params *service.DeleteActorFilmParams) error {
+	var err error
+
+	if params.Actor != "" {
+		if params.ActorId, err = s.GetActorId(params.Actor); err != nil {
+			return err
+		}
+	}
+
+	if params.Film != "" {
+		if params.FilmId, err = s.GetFilmId(params.Film); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		if err := repo.DeleteActorFilm(params); err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "actor_film", params.FilmId, "delete", map[string]any{"actor_id": params.ActorId}, nil)
+	})
 }
 
-func (s *ServiceUsecase) SearchFilms(pattern string) ([]string, error) {
+func (s *ServiceUsecase) UpdateCredit(ctx context.Context, params *service.UpdateCreditParams) error {
+	var err error
+
+	if params.Actor != "" {
+		if params.ActorId, err = s.GetActorId(params.Actor); err != nil {
+			return err
+		}
+	}
+
+	if params.Film != "" {
+		if params.FilmId, err = s.GetFilmId(params.Film); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		// A credit is locked with its film, under which it is filed.
+		if err := repo.Lock("film", params.FilmId); err != nil {
+			return err
+		}
+
+		before, err := service.CreditSnapshot(repo, params.ActorId, params.FilmId)
+		if err != nil {
+			return err
+		}
+		if err = repo.UpdateCredit(params); err != nil {
+			return err
+		}
+
+		after, err := service.CreditSnapshot(repo, params.ActorId, params.FilmId)
+		if err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "actor_film", params.FilmId, "update", before, after, "actor_id")
+	})
+}
+
+func (s *ServiceUsecase) AddCrew(ctx context.Context, params *service.CrewParams) error {
+	if err := s.resolveCrew(params); err != nil {
+		return err
+	}
+
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		if err := repo.AddCrew(params); err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "film_crew", params.FilmId, "create", nil, map[string]any{"person_id": params.PersonId, "job": params.Job})
+	})
+}
+
+func (s *ServiceUsecase) DeleteCrew(ctx context.Context, params *service.CrewParams) error {
+	if err := s.resolveCrew(params); err != nil {
+		return err
+	}
+
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		if err := repo.DeleteCrew(params); err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "film_crew", params.FilmId, "delete", map[string]any{"person_id": params.PersonId, "job": params.Job}, nil)
+	})
+}
+
+func (s *ServiceUsecase) resolveCrew(params *service.CrewParams) error {
+	var err error
+
+	if params.Person != "" {
+		if params.PersonId, err = s.GetActorId(params.Person); err != nil {
+			return err
+		}
+	}
+
+	if params.Film != "" {
+		if params.FilmId, err = s.GetFilmId(params.Film); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ServiceUsecase) AddGenresByFilm(ctx context.Context, params *service.AddGenresByFilmParams) error {
+	var err error
+
+	if params.Film != "" {
+		if params.FilmId, err = s.GetFilmId(params.Film); err != nil {
+			return err
+		}
+	}
+
+	for _, genre := range params.Genres {
+		id, err := s.repo.GetGenreId(genre)
+		if err != nil {
+			return err
+		}
+		params.GenreIds = append(params.GenreIds, id)
+	}
+
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		if err := repo.AddGenresByFilm(params); err != nil {
+			return err
+		}
+
+		for _, genreId := range params.GenreIds {
+			if err := service.Audit(ctx, repo, "film_genre", params.FilmId, "create", nil, map[string]any{"genre_id": genreId}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *ServiceUsecase) DeleteFilmGenre(ctx context.Context, params *service.DeleteFilmGenreParams) error {
+	var err error
+
+	if params.Film != "" {
+		if params.FilmId, err = s.GetFilmId(params.Film); err != nil {
+			return err
+		}
+	}
+
+	if params.Genre != "" {
+		if params.GenreId, err = s.repo.GetGenreId(params.Genre); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.InTx(ctx, func(repo service.Repository) error {
+		if err := repo.DeleteFilmGenre(params); err != nil {
+			return err
+		}
+
+		return service.Audit(ctx, repo, "film_genre", params.FilmId, "delete", map[string]any{"genre_id": params.GenreId}, nil)
+	})
+}
+
+func (s *ServiceUsecase) GetTrash(kind string, params *service.DetailsParams) (*service.TrashPage, error) {
+	return s.repo.GetTrash(kind, params)
+}
+
+// PurgeTrash deletes for good what has been in the trash longer than the
+// retention period. Nothing is purged while the retention is 0.
+func (s *ServiceUsecase) PurgeTrash(ctx context.Context) (int, error) {
+	if s.cfg.Trash.Retention <= 0 {
+		return 0, nil
+	}
+
 	var (
-		resp []string
-		err  error
+		before = time.Now().Add(-s.cfg.Trash.Retention)
+		purged int
 	)
+	err := s.repo.InTx(ctx, func(repo service.Repository) error {
+		var err error
+		if purged, err = repo.PurgeTrash(ctx, before); err != nil || purged == 0 {
+			return err
+		}
 
-	resp, err = s.repo.SearchFilms(pattern)
+		return service.Audit(ctx, repo, "trash", 0, "purge", nil, map[string]any{"purged": purged, "deleted_before": before})
+	})
 	if err != nil {
-		return resp, err
+		return 0, err
 	}
 
-	return resp, err
+	return purged, nil
 }
 
-func (s *ServiceUsecase) AddFilmsByActor(params *service.AddFilmsByActorParams) error {
-	return s.repo.AddFilmsByActor(params)
+func (s *ServiceUsecase) GetAudit(filter *service.AuditFilter, params *service.DetailsParams) (*service.AuditPage, error) {
+	return s.repo.GetAudit(filter, params)
 }
 
-func (s *ServiceUsecase) AddActorsByFilm(params *service.AddActorsByFilmParams) error {
-	return s.repo.AddActorsByFilm(params)
+// RunPurge purges the trash right away and then every interval until ctx is done.
+func RunPurge(ctx context.Context, uc service.Usecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := uc.PurgeTrash(ctx)
+		if err != nil {
+			log.Printf("Cannot purge trash. Error: {%s}", err.Error())
+		} else if purged > 0 {
+			log.Printf("Purged %d deleted actors and films", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
-func (s *ServiceUsecase) DeleteActorFilm(params *service.DeleteActorFilmParams) error {
-	return s.repo.DeleteActorFilm(params)
+// resolveName turns the ids found for a name into a single id, reporting
+// a missing name or a name shared by several entries.
+func resolveName(entity, name string, ids []int) (int, error) {
+	switch len(ids) {
+	case 0:
+		return 0, fmt.Errorf("no %s named %q: %w", entity, name, service.ErrNotFound)
+	case 1:
+		return ids[0], nil
+	default:
+		return 0, fmt.Errorf("%s %q has ids %v: %w", entity, name, ids, service.ErrAmbiguousName)
+	}
 }
@@ -1,41 +1,78 @@
 package usecase
 
 import (
+	"context"
+	"film_library/config"
+	"film_library/internal/recommend"
 	"film_library/internal/service"
 	mock_service "film_library/internal/service/mocks"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	"slices"
 	"testing"
+	"time"
 )
 
+var cfg = &config.Config{Search: config.SearchConfig{Language: "russian", Threshold: 0.3}}
+
+// allowTx runs the transactions of the mutations on repo itself.
+func allowTx(repo *mock_service.MockRepository) {
+	repo.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, fn func(service.Repository) error) error {
+		return fn(repo)
+	}).AnyTimes()
+}
+
+// allowAudit lets the mutations lock and look up their snapshots and write the audit log and revisions.
+func allowAudit(repo *mock_service.MockRepository) {
+	allowTx(repo)
+	repo.EXPECT().Lock(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	repo.EXPECT().GetActorsByIds(gomock.Any()).Return(nil, nil).AnyTimes()
+	repo.EXPECT().GetFilmsByIds(gomock.Any()).Return(nil, nil).AnyTimes()
+	repo.EXPECT().GetGenre(gomock.Any()).Return(nil, service.ErrNotFound).AnyTimes()
+	repo.EXPECT().GetReview(gomock.Any()).Return(nil, service.ErrNotFound).AnyTimes()
+	repo.EXPECT().GetCredit(gomock.Any(), gomock.Any()).Return(nil, service.ErrNotFound).AnyTimes()
+	repo.EXPECT().AddAudit(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	repo.EXPECT().AddRevision(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil).AnyTimes()
+}
+
 func TestActor(t *testing.T) {
 	ctr := gomock.NewController(t)
 	defer ctr.Finish()
 
 	repo := mock_service.NewMockRepository(ctr)
+	allowAudit(repo)
 	in := service.Actor{Name: "Sasha", Sex: "m", BDate: "1999-10-10"}
 	detail := service.DetailsParams{Sort: "Name"}
 
-	repo.EXPECT().CreateActor(&in).Return(nil).Times(1)
-	repo.EXPECT().GetActor("Sasha").Return(&in, nil).Times(1)
-	repo.EXPECT().UpdateActor("Sasha", &in).Return(nil).Times(1)
-	repo.EXPECT().DeleteActor("Sasha").Return(nil).Times(1)
-	repo.EXPECT().SearchActor("Sasha").Return([]string{"Sasha1", "Sasha2"}, nil).Times(1)
-	repo.EXPECT().GetActors(&detail).Return([]service.Actor{in}, nil).Times(1)
-	useCase := NewServiceUsecase(repo)
-	err := useCase.CreateActor(&in)
+	repo.EXPECT().CreateActor(&in).Return(1, nil).Times(1)
+	repo.EXPECT().GetActorIds("Sasha").Return([]int{1}, nil).Times(1)
+	repo.EXPECT().GetActor(1).Return(&in, nil).Times(1)
+	patch := service.ActorPatch{BDate: service.Patched("1999-10-11"), Version: 3}
+	repo.EXPECT().UpdateActor(1, &patch).Return(nil).Times(1)
+	repo.EXPECT().DeleteActor(1, 7, 3).Return(nil).Times(1)
+	search := service.SearchParams{Query: "Sasha", Limit: 20}
+	hits := &service.ActorSearchResult{Items: []service.ActorHit{{Actor: in, Rank: 0.5}}}
+	repo.EXPECT().SearchActor(&service.SearchParams{Query: "Sasha", Language: "russian", Threshold: 0.3, Limit: 20}).Return(hits, nil).Times(1)
+	repo.EXPECT().GetActors(&detail).Return(&service.ActorsPage{Items: []service.Actor{in}, Total: 1}, nil).Times(1)
+	useCase := NewServiceUsecase(cfg, repo, nil)
+	id, err := useCase.CreateActor(context.Background(), &in)
+	require.NoError(t, err)
+	require.Equal(t, 1, id)
+	id, err = useCase.GetActorId("Sasha")
 	require.NoError(t, err)
-	err = useCase.UpdateActor("Sasha", &in)
+	require.Equal(t, 1, id)
+	err = useCase.UpdateActor(context.Background(), 1, &patch)
 	require.NoError(t, err)
-	err = useCase.DeleteActor("Sasha")
+	err = useCase.DeleteActor(context.Background(), 1, 7, 3)
 	require.NoError(t, err)
-	resp, err := useCase.SearchActor("Sasha")
+	resp, err := useCase.SearchActor(&search)
 	require.NoError(t, err)
-	require.Equal(t, resp, []string{"Sasha1", "Sasha2"})
+	require.Equal(t, resp, hits)
 	actors, err := useCase.GetActors(&detail)
 	require.NoError(t, err)
-	require.Equal(t, actors, []service.Actor{in})
-	actor, err := useCase.GetActor("Sasha")
+	require.Equal(t, actors, &service.ActorsPage{Items: []service.Actor{in}, Total: 1})
+	actor, err := useCase.GetActor(1)
 	require.NoError(t, err)
 	require.Equal(t, actor, &in)
 }
@@ -45,30 +82,40 @@ func TestFilm(t *testing.T) {
 	defer ctr.Finish()
 
 	repo := mock_service.NewMockRepository(ctr)
+	allowAudit(repo)
 	in := service.Film{Name: "Sasha", Rating: 7.7, RDate: "1999-10-10", Desc: "nice file, klyanus`"}
 	detail := service.DetailsParams{Sort: "Name"}
 
-	repo.EXPECT().CreateFilm(&in).Return(nil).Times(1)
-	repo.EXPECT().GetFilm("Rocky").Return(&in, nil).Times(1)
-	repo.EXPECT().UpdateFilm("Rocky", &in).Return(nil).Times(1)
-	repo.EXPECT().DeleteFilm("Rocky").Return(nil).Times(1)
-	repo.EXPECT().SearchFilms("Rocky").Return([]string{"Rocky 1", "Rocky 2"}, nil).Times(1)
-	repo.EXPECT().GetFilms(&detail).Return([]service.Film{in}, nil).Times(1)
-	useCase := NewServiceUsecase(repo)
-	err := useCase.CreateFilm(&in)
+	repo.EXPECT().CreateFilm(&in).Return(2, nil).Times(1)
+	repo.EXPECT().GetFilmIds("Rocky").Return([]int{2}, nil).Times(1)
+	repo.EXPECT().GetFilm(2).Return(&in, nil).Times(1)
+	patch := service.FilmPatch{Desc: service.PatchField[string]{Set: true, Null: true}}
+	repo.EXPECT().UpdateFilm(2, &patch).Return(nil).Times(1)
+	repo.EXPECT().DeleteFilm(2, 7, 0).Return(nil).Times(1)
+	search := service.SearchParams{Query: "Rocky", Limit: 20}
+	hits := &service.FilmSearchResult{Items: []service.FilmHit{{Film: in, Rank: 0.5}}}
+	repo.EXPECT().SearchFilms(&service.SearchParams{Query: "Rocky", Language: "russian", Threshold: 0.3, Limit: 20}).Return(hits, nil).Times(1)
+	repo.EXPECT().GetFilms(&detail).Return(&service.FilmsPage{Items: []service.Film{in}, Total: 1}, nil).Times(1)
+	useCase := NewServiceUsecase(cfg, repo, nil)
+	id, err := useCase.CreateFilm(context.Background(), &in)
 	require.NoError(t, err)
-	err = useCase.UpdateFilm("Rocky", &in)
+	require.Equal(t, 2, id)
+	id, err = useCase.GetFilmId("Rocky")
 	require.NoError(t, err)
-	err = useCase.DeleteFilm("Rocky")
+	require.Equal(t, 2, id)
+	err = useCase.UpdateFilm(context.Background(), 2, &patch)
 	require.NoError(t, err)
-	resp, err := useCase.SearchFilms("Rocky")
+	err = useCase.DeleteFilm(context.Background(), 2, 7, 0)
 	require.NoError(t, err)
-	require.Equal(t, resp, []string{"Rocky 1", "Rocky 2"})
+	// The configured language is used when the request doesn't ask for one.
+	resp, err := useCase.SearchFilms(&search)
+	require.NoError(t, err)
+	require.Equal(t, resp, hits)
 	films, err := useCase.GetFilms(&detail)
 	require.NoError(t, err)
-	require.Equal(t, films, []service.Film{in})
+	require.Equal(t, films, &service.FilmsPage{Items: []service.Film{in}, Total: 1})
 
-	film, err := useCase.GetFilm("Rocky")
+	film, err := useCase.GetFilm(2)
 	require.NoError(t, err)
 	require.Equal(t, film, &in)
 }
@@ -78,17 +125,542 @@ func TestOther(t *testing.T) {
 	defer ctr.Finish()
 
 	repo := mock_service.NewMockRepository(ctr)
-	actors := []string{"Milla Jovovich", "Mark Zakharov", "Cameron Diaz", "John Travolta", "James cameron", "Kate Winslet"}
-	films := []string{"Forrest Gump", "The Shawshank Redemption", "The Social Network", "Pulp Fiction", "The King's Speech", "Dead Poets Society"}
-	repo.EXPECT().AddFilmsByActor(&service.AddFilmsByActorParams{Films: films, Actor: actors[0]}).Return(nil).Times(1)
-	repo.EXPECT().AddActorsByFilm(&service.AddActorsByFilmParams{Film: films[0], Actors: actors}).Return(nil).Times(1)
-	repo.EXPECT().DeleteActorFilm(&service.DeleteActorFilmParams{Film: films[0], Actor: actors[0]}).Return(nil).Times(1)
+	allowAudit(repo)
+	actors := []string{"Milla Jovovich", "Mark Zakharov"}
+	films := []string{"Forrest Gump", "The Shawshank Redemption"}
+
+	repo.EXPECT().GetActorIds(actors[0]).Return([]int{1}, nil).Times(2)
+	repo.EXPECT().GetActorIds(actors[1]).Return([]int{2}, nil).Times(1)
+	repo.EXPECT().GetFilmIds(films[0]).Return([]int{10}, nil).Times(3)
+	repo.EXPECT().GetFilmIds(films[1]).Return([]int{20}, nil).Times(1)
+	repo.EXPECT().AddFilmsByActor(&service.AddFilmsByActorParams{Actor: actors[0], ActorId: 1, Films: films, FilmIds: []int{10, 20}}).Return(nil).Times(1)
+	repo.EXPECT().AddActorsByFilm(&service.AddActorsByFilmParams{Film: films[0], FilmId: 10, Actors: actors, ActorIds: []int{1, 2}}).Return(nil).Times(1)
+	repo.EXPECT().DeleteActorFilm(&service.DeleteActorFilmParams{Film: films[0], FilmId: 10, ActorId: 1}).Return(nil).Times(1)
+
+	useCase := NewServiceUsecase(cfg, repo, nil)
+	err := useCase.AddActorsByFilm(context.Background(), &service.AddActorsByFilmParams{Film: films[0], Actors: actors})
+	require.NoError(t, err)
+	err = useCase.AddFilmsByActor(context.Background(), &service.AddFilmsByActorParams{Films: films, Actor: actors[0]})
+	require.NoError(t, err)
+	err = useCase.DeleteActorFilm(context.Background(), &service.DeleteActorFilmParams{Film: films[0], ActorId: 1})
+	require.NoError(t, err)
+}
+
+func TestUpdateCredit(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	allowAudit(repo)
+	order := 2
+
+	repo.EXPECT().GetActorIds("Robin Wright").Return([]int{5}, nil).Times(1)
+	repo.EXPECT().GetFilmIds("Forrest Gump").Return([]int{10}, nil).Times(1)
+	repo.EXPECT().UpdateCredit(&service.UpdateCreditParams{Actor: "Robin Wright", ActorId: 5, Film: "Forrest Gump", FilmId: 10, Order: &order}).Return(nil).Times(1)
+
+	useCase := NewServiceUsecase(cfg, repo, nil)
+	err := useCase.UpdateCredit(context.Background(), &service.UpdateCreditParams{Actor: "Robin Wright", Film: "Forrest Gump", Order: &order})
+	require.NoError(t, err)
+}
+
+func TestCrew(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	allowAudit(repo)
+
+	repo.EXPECT().GetActorIds("Robert Zemeckis").Return([]int{8}, nil).Times(2)
+	repo.EXPECT().GetFilmIds("Forrest Gump").Return([]int{10}, nil).Times(1)
+	repo.EXPECT().AddCrew(&service.CrewParams{Person: "Robert Zemeckis", PersonId: 8, Film: "Forrest Gump", FilmId: 10, Job: "director"}).Return(nil).Times(1)
+	repo.EXPECT().DeleteCrew(&service.CrewParams{Person: "Robert Zemeckis", PersonId: 8, FilmId: 10, Job: "writer"}).Return(nil).Times(1)
+
+	useCase := NewServiceUsecase(cfg, repo, nil)
+	err := useCase.AddCrew(context.Background(), &service.CrewParams{Person: "Robert Zemeckis", Film: "Forrest Gump", Job: "director"})
+	require.NoError(t, err)
+	err = useCase.DeleteCrew(context.Background(), &service.CrewParams{Person: "Robert Zemeckis", FilmId: 10, Job: "writer"})
+	require.NoError(t, err)
+}
+
+func TestGenre(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	allowAudit(repo)
+	in := service.Genre{Name: "Drama"}
+
+	repo.EXPECT().CreateGenre(&in).Return(4, nil).Times(1)
+	repo.EXPECT().GetGenres().Return([]service.Genre{{Id: 4, Name: "Drama", Films: 2}}, nil).Times(1)
+	repo.EXPECT().GetFilmIds("Hamlet").Return([]int{7}, nil).Times(2)
+	repo.EXPECT().GetGenreId("Drama").Return(4, nil).Times(2)
+	repo.EXPECT().GetGenreId("Western").Return(0, fmt.Errorf("no genre named %q: %w", "Western", service.ErrNotFound)).Times(1)
+	repo.EXPECT().AddGenresByFilm(&service.AddGenresByFilmParams{Film: "Hamlet", FilmId: 7, Genres: []string{"Drama"}, GenreIds: []int{1, 4}}).Return(nil).Times(1)
+	repo.EXPECT().DeleteFilmGenre(&service.DeleteFilmGenreParams{FilmId: 7, Genre: "Drama", GenreId: 4}).Return(nil).Times(1)
+
+	useCase := NewServiceUsecase(cfg, repo, nil)
+	id, err := useCase.CreateGenre(context.Background(), &in)
+	require.NoError(t, err)
+	require.Equal(t, 4, id)
+	genres, err := useCase.GetGenres()
+	require.NoError(t, err)
+	require.Equal(t, []service.Genre{{Id: 4, Name: "Drama", Films: 2}}, genres)
+
+	err = useCase.AddGenresByFilm(context.Background(), &service.AddGenresByFilmParams{Film: "Hamlet", Genres: []string{"Drama"}, GenreIds: []int{1}})
+	require.NoError(t, err)
+	err = useCase.AddGenresByFilm(context.Background(), &service.AddGenresByFilmParams{Film: "Hamlet", Genres: []string{"Western"}})
+	require.ErrorIs(t, err, service.ErrNotFound)
+	err = useCase.DeleteFilmGenre(context.Background(), &service.DeleteFilmGenreParams{FilmId: 7, Genre: "Drama"})
+	require.NoError(t, err)
+}
+
+func TestReview(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	allowAudit(repo)
+	in := service.Review{FilmId: 7, UserId: 2, Rating: 9}
+	params := service.DetailsParams{Sort: "created_at", Limit: 20}
 
-	useCase := NewServiceUsecase(repo)
-	err := useCase.AddActorsByFilm(&service.AddActorsByFilmParams{Film: films[0], Actors: actors})
+	repo.EXPECT().CreateReview(&in).Return(3, nil).Times(1)
+	repo.EXPECT().GetReviews(7, &params).Return(&service.ReviewsPage{Items: []service.Review{in}, Total: 1}, nil).Times(1)
+	repo.EXPECT().DeleteReview(3, 5).Return(fmt.Errorf("no review: %w", service.ErrNotFound)).Times(1)
+
+	useCase := NewServiceUsecase(cfg, repo, nil)
+	id, err := useCase.CreateReview(context.Background(), &in)
+	require.NoError(t, err)
+	require.Equal(t, 3, id)
+	page, err := useCase.GetReviews(7, &params)
 	require.NoError(t, err)
-	err = useCase.AddFilmsByActor(&service.AddFilmsByActorParams{Films: films, Actor: actors[0]})
+	require.Equal(t, 1, page.Total)
+	err = useCase.DeleteReview(context.Background(), 3, 5)
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestFilmography(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	useCase := NewServiceUsecase(cfg, repo, nil)
+
+	repo.EXPECT().GetActorsByIds([]int{3}).Return([]service.Actor{{Id: 3}}, nil).Times(1)
+	repo.EXPECT().GetFilms(&service.DetailsParams{Sort: "rating", Filter: &service.FilmFilter{ActorIds: []int{3}}}).
+		Return(&service.FilmsPage{Total: 1}, nil).Times(1)
+	films, err := useCase.GetActorFilms(3, &service.DetailsParams{Sort: "rating"})
+	require.NoError(t, err)
+	require.Equal(t, 1, films.Total)
+
+	repo.EXPECT().GetActorsByIds([]int{4}).Return(nil, nil).Times(1)
+	_, err = useCase.GetActorFilms(4, &service.DetailsParams{})
+	require.ErrorIs(t, err, service.ErrNotFound)
+
+	repo.EXPECT().GetFilmsByIds([]int{7}).Return([]service.Film{{Id: 7}}, nil).Times(1)
+	repo.EXPECT().GetActors(&service.DetailsParams{Sort: "actor_name", FilmId: 7}).Return(&service.ActorsPage{Total: 2}, nil).Times(1)
+	actors, err := useCase.GetFilmActors(7, &service.DetailsParams{Sort: "actor_name"})
 	require.NoError(t, err)
-	err = useCase.DeleteActorFilm(&service.DeleteActorFilmParams{Film: films[0], Actor: actors[0]})
+	require.Equal(t, 2, actors.Total)
+
+	repo.EXPECT().GetFilmsByIds([]int{8}).Return(nil, nil).Times(1)
+	_, err = useCase.GetFilmActors(8, &service.DetailsParams{})
+	require.ErrorIs(t, err, service.ErrNotFound)
+}
+
+func TestResolveName(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	repo.EXPECT().GetFilmIds("Hamlet").Return([]int{3, 7}, nil).Times(1)
+	repo.EXPECT().GetActorIds("Nobody").Return([]int{}, nil).Times(1)
+	repo.EXPECT().GetFilmIds("Hamlet").Return([]int{3, 7}, nil).Times(1)
+
+	useCase := NewServiceUsecase(cfg, repo, nil)
+	_, err := useCase.GetFilmId("Hamlet")
+	require.ErrorIs(t, err, service.ErrAmbiguousName)
+	_, err = useCase.GetActorId("Nobody")
+	require.ErrorIs(t, err, service.ErrNotFound)
+
+	err = useCase.AddFilmsByActor(context.Background(), &service.AddFilmsByActorParams{ActorId: 1, Films: []string{"Hamlet"}})
+	require.ErrorIs(t, err, service.ErrAmbiguousName)
+}
+
+func TestSearchSuggestion(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	empty := func() *service.ActorSearchResult { return &service.ActorSearchResult{Items: []service.ActorHit{}} }
+	similar := &service.ActorSearchResult{Items: []service.ActorHit{{Actor: service.Actor{Name: "Leonardo DiCaprio"}, Rank: 0.7}}}
+
+	gomock.InOrder(
+		repo.EXPECT().SearchActor(gomock.Any()).Return(empty(), nil).Times(1),
+		repo.EXPECT().SimilarActors(&service.SearchParams{Query: "Di Caprio", Threshold: 0.3, Limit: 1}).Return(similar, nil).Times(1),
+		repo.EXPECT().SearchActor(gomock.Any()).Return(empty(), nil).Times(1),
+		repo.EXPECT().SimilarActors(gomock.Any()).Return(empty(), nil).Times(1),
+		repo.EXPECT().SimilarActors(&service.SearchParams{Query: "Di Caprio", Mode: "fuzzy", Language: "russian", Threshold: 0.6, Limit: 20}).Return(similar, nil).Times(1),
+	)
+
+	useCase := NewServiceUsecase(cfg, repo, nil)
+
+	resp, err := useCase.SearchActor(&service.SearchParams{Query: "Di Caprio", Limit: 20})
+	require.NoError(t, err)
+	require.Equal(t, &service.ActorSearchResult{Items: []service.ActorHit{}, DidYouMean: "Leonardo DiCaprio"}, resp)
+
+	resp, err = useCase.SearchActor(&service.SearchParams{Query: "zzz", Limit: 20})
 	require.NoError(t, err)
+	require.Equal(t, empty(), resp)
+
+	resp, err = useCase.SearchActor(&service.SearchParams{Query: "Di Caprio", Mode: "fuzzy", Threshold: 0.6, Limit: 20})
+	require.NoError(t, err)
+	require.Equal(t, similar, resp)
+}
+
+func TestGetRecommendations(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	recommender := mock_service.NewMockRecommender(ctr)
+
+	recommender.EXPECT().Recommend(2, 3).Return([]recommend.Prediction{{FilmId: 5, Score: 8.456}, {FilmId: 6, Score: 8}, {FilmId: 4, Score: 7.5}}).Times(1)
+	// Film 6 was deleted after the model was built.
+	repo.EXPECT().GetFilmsByIds([]int{5, 6, 4}).Return([]service.Film{{Id: 4, Name: "Hamlet"}, {Id: 5, Name: "Macbeth"}}, nil).Times(1)
+
+	useCase := NewServiceUsecase(cfg, repo, recommender)
+	resp, err := useCase.GetRecommendations(2, 3)
+	require.NoError(t, err)
+	require.Equal(t, []service.Recommendation{
+		{Film: service.Film{Id: 5, Name: "Macbeth"}, PredictedRating: 8.46},
+		{Film: service.Film{Id: 4, Name: "Hamlet"}, PredictedRating: 7.5},
+	}, resp)
+}
+
+func TestGetActorPath(t *testing.T) {
+	// Films and their casts: 1-2-3-4-5 is a chain of four films, 1-9-5 a
+	// shortcut of two, and 6 and 7 only know each other.
+	casts := map[int][]int{10: {1, 2}, 11: {2, 3}, 12: {3, 4}, 13: {4, 5}, 15: {1, 9}, 16: {9, 5}, 14: {6, 7}}
+	names := map[int]string{1: "A", 2: "B", 3: "C", 4: "D", 5: "E", 6: "F", 7: "G", 9: "I"}
+
+	links := func(_ context.Context, ids []int) ([]service.ActorLink, error) {
+		var data []service.ActorLink
+		for _, id := range ids {
+			for film, cast := range casts {
+				if slices.Contains(cast, id) {
+					for _, co := range cast {
+						if co != id {
+							data = append(data, service.ActorLink{ActorId: id, FilmId: film, CoActorId: co})
+						}
+					}
+				}
+			}
+		}
+		return data, nil
+	}
+	actors := func(ids []int) ([]service.Actor, error) {
+		var data []service.Actor
+		for _, id := range ids {
+			if name, ok := names[id]; ok {
+				data = append(data, service.Actor{Id: id, Name: name})
+			}
+		}
+		return data, nil
+	}
+	films := func(ids []int) ([]service.Film, error) {
+		var data []service.Film
+		for _, id := range ids {
+			data = append(data, service.Film{Id: id, Name: fmt.Sprintf("Film %d", id)})
+		}
+		return data, nil
+	}
+
+	cfg := &config.Config{Path: config.PathConfig{Timeout: time.Second}}
+
+	testTable := []struct {
+		name     string
+		params   service.PathParams
+		expected *service.ActorPath
+		err      error
+	}{
+		{
+			name:   "Shortcut",
+			params: service.PathParams{From: 1, To: 5},
+			expected: &service.ActorPath{Degrees: 2, Chain: []service.PathLink{
+				{ActorId: 1, Actor: "A", FilmId: 15, Film: "Film 15"},
+				{ActorId: 9, Actor: "I", FilmId: 16, Film: "Film 16"},
+				{ActorId: 5, Actor: "E"},
+			}},
+		},
+		{
+			name:   "Chain",
+			params: service.PathParams{From: 2, To: 4},
+			expected: &service.ActorPath{Degrees: 2, Chain: []service.PathLink{
+				{ActorId: 2, Actor: "B", FilmId: 11, Film: "Film 11"},
+				{ActorId: 3, Actor: "C", FilmId: 12, Film: "Film 12"},
+				{ActorId: 4, Actor: "D"},
+			}},
+		},
+		{
+			name:     "Same",
+			params:   service.PathParams{From: 3, To: 3},
+			expected: &service.ActorPath{Chain: []service.PathLink{{ActorId: 3, Actor: "C"}}},
+		},
+		{
+			name:   "TooDeep",
+			params: service.PathParams{From: 1, To: 5, MaxDepth: 1},
+			err:    service.ErrNotFound,
+		},
+		{
+			name:   "NotConnected",
+			params: service.PathParams{From: 1, To: 7},
+			err:    service.ErrNotFound,
+		},
+		{
+			name:   "NoActor",
+			params: service.PathParams{From: 1, To: 8},
+			err:    service.ErrNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+
+			repo := mock_service.NewMockRepository(ctr)
+			repo.EXPECT().ActorLinks(gomock.Any(), gomock.Any()).DoAndReturn(links).AnyTimes()
+			repo.EXPECT().GetActorsByIds(gomock.Any()).DoAndReturn(actors).AnyTimes()
+			repo.EXPECT().GetFilmsByIds(gomock.Any()).DoAndReturn(films).AnyTimes()
+
+			useCase := NewServiceUsecase(cfg, repo, nil)
+			resp, err := useCase.GetActorPath(context.Background(), &testCase.params)
+			if testCase.err != nil {
+				require.ErrorIs(t, err, testCase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, resp)
+		})
+	}
+}
+
+func TestGetActorPathTimeout(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	repo.EXPECT().GetActorsByIds([]int{1, 2}).Return([]service.Actor{{Id: 1}, {Id: 2}}, nil).Times(2)
+	repo.EXPECT().ActorLinks(gomock.Any(), []int{1}).DoAndReturn(func(ctx context.Context, _ []int) ([]service.ActorLink, error) {
+		<-ctx.Done()
+		return nil, fmt.Errorf("canceling statement due to user request")
+	}).Times(2)
+
+	useCase := NewServiceUsecase(&config.Config{Path: config.PathConfig{Timeout: 10 * time.Millisecond}}, repo, nil)
+	_, err := useCase.GetActorPath(context.Background(), &service.PathParams{From: 1, To: 2})
+	require.ErrorIs(t, err, service.ErrTimeout)
+
+	// A request that goes away stops the search long before the timeout.
+	useCase = NewServiceUsecase(&config.Config{Path: config.PathConfig{Timeout: time.Hour}}, repo, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = useCase.GetActorPath(ctx, &service.PathParams{From: 1, To: 2})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, service.ErrTimeout)
+}
+
+func TestTrash(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	allowAudit(repo)
+	page := &service.TrashPage{Items: []service.TrashItem{{Id: 3, Name: "Hamlet"}}, Total: 1}
+	params := &service.DetailsParams{Sort: "deleted_at", Limit: 20}
+
+	repo.EXPECT().RestoreActor(1).Return(nil).Times(1)
+	repo.EXPECT().RestoreFilm(3).Return(fmt.Errorf("no deleted film: %w", service.ErrNotFound)).Times(1)
+	repo.EXPECT().GetTrash("films", params).Return(page, nil).Times(1)
+
+	useCase := NewServiceUsecase(cfg, repo, nil)
+	require.NoError(t, useCase.RestoreActor(context.Background(), 1))
+	require.ErrorIs(t, useCase.RestoreFilm(context.Background(), 3), service.ErrNotFound)
+
+	resp, err := useCase.GetTrash("films", params)
+	require.NoError(t, err)
+	require.Equal(t, page, resp)
+}
+
+func TestPurgeTrash(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	allowAudit(repo)
+	repo.EXPECT().PurgeTrash(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, before time.Time) (int, error) {
+		require.WithinDuration(t, time.Now().Add(-72*time.Hour), before, time.Minute)
+		return 4, nil
+	}).Times(1)
+
+	purged, err := NewServiceUsecase(&config.Config{Trash: config.TrashConfig{Retention: 72 * time.Hour}}, repo, nil).
+		PurgeTrash(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 4, purged)
+
+	// Without a retention period the trash is kept.
+	purged, err = NewServiceUsecase(cfg, repo, nil).PurgeTrash(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, purged)
+}
+
+func TestAudit(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	useCase := NewServiceUsecase(cfg, repo, nil)
+	ctx := service.WithOrigin(context.Background(), service.Origin{UserId: 7, RequestId: "req-1"})
+	allowTx(repo)
+
+	var (
+		entries  []service.AuditEntry
+		auditErr error
+	)
+	repo.EXPECT().AddAudit(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *service.AuditEntry) error {
+		entries = append(entries, *e)
+		return auditErr
+	}).AnyTimes()
+	repo.EXPECT().AddRevision(gomock.Any(), gomock.Any(), gomock.Any()).Return(2, nil).AnyTimes()
+
+	user, actor, film, genre := 7, 3, 2, 4
+	order := 2
+
+	// A new actor is recorded as it was stored, not as it was asked for.
+	repo.EXPECT().CreateActor(&service.Actor{Name: "Kenneth Branagh", Sex: "m", BDate: "1960-12-10"}).Return(3, nil).Times(1)
+	repo.EXPECT().GetActorsByIds([]int{3}).
+		Return([]service.Actor{{Id: 3, Name: "Kenneth Branagh", Sex: "m", BDate: "1960-12-10T00:00:00Z"}}, nil).Times(1)
+	_, err := useCase.CreateActor(ctx, &service.Actor{Name: "Kenneth Branagh", Sex: "m", BDate: "1960-12-10"})
+	require.NoError(t, err)
+
+	// Only the changed fields of an update are kept. The film is locked before it is read.
+	in := service.Film{Name: "Hamlet", RDate: "1996-12-25", Rating: 7.7}
+	gomock.InOrder(
+		repo.EXPECT().Lock("film", 2).Return(nil).Times(1),
+		repo.EXPECT().GetFilmsByIds([]int{2}).Return([]service.Film{in}, nil).Times(1),
+		repo.EXPECT().UpdateFilm(2, &service.FilmPatch{Rating: service.Patched[float32](8.1)}).Return(nil).Times(1),
+		repo.EXPECT().GetFilmsByIds([]int{2}).Return([]service.Film{{Name: "Hamlet", RDate: "1996-12-25", Rating: 8.1}}, nil).Times(1),
+	)
+	require.NoError(t, useCase.UpdateFilm(ctx, 2, &service.FilmPatch{Rating: service.Patched[float32](8.1)}))
+
+	// Relations are filed pair by pair under their film.
+	repo.EXPECT().AddActorsByFilm(&service.AddActorsByFilmParams{FilmId: 2, ActorIds: []int{10, 11}}).Return(nil).Times(1)
+	require.NoError(t, useCase.AddActorsByFilm(ctx, &service.AddActorsByFilmParams{FilmId: 2, ActorIds: []int{10, 11}}))
+
+	// The actor stays in a credit change to tell which credit it was.
+	gomock.InOrder(
+		repo.EXPECT().Lock("film", 2).Return(nil).Times(1),
+		repo.EXPECT().GetCredit(10, 2).Return(&service.Credit{ActorId: 10, Type: "supporting"}, nil).Times(1),
+		repo.EXPECT().UpdateCredit(&service.UpdateCreditParams{ActorId: 10, FilmId: 2, Order: &order}).Return(nil).Times(1),
+		repo.EXPECT().GetCredit(10, 2).Return(&service.Credit{ActorId: 10, Type: "supporting", Order: &order}, nil).Times(1),
+	)
+	require.NoError(t, useCase.UpdateCredit(ctx, &service.UpdateCreditParams{ActorId: 10, FilmId: 2, Order: &order}))
+
+	// A failed change is not recorded, and a failed record fails the change.
+	repo.EXPECT().Lock("genre", 4).Return(nil).Times(2)
+	repo.EXPECT().GetGenre(4).Return(&service.Genre{Id: 4, Name: "Drama"}, nil).Times(2)
+	repo.EXPECT().DeleteGenre(4).Return(fmt.Errorf("no genre: %w", service.ErrNotFound)).Times(1)
+	require.ErrorIs(t, useCase.DeleteGenre(ctx, 4), service.ErrNotFound)
+
+	require.Equal(t, []service.AuditEntry{
+		{UserId: &user, Entity: "actor", EntityId: &actor, Operation: "create",
+			After: service.RawJSON(`{"bdate":"1960-12-10T00:00:00Z","name":"Kenneth Branagh","sex":"m"}`), RequestId: "req-1"},
+		{UserId: &user, Entity: "film", EntityId: &film, Operation: "update",
+			Before: service.RawJSON(`{"rating":7.7}`), After: service.RawJSON(`{"rating":8.1}`), RequestId: "req-1"},
+		{UserId: &user, Entity: "actor_film", EntityId: &film, Operation: "create", After: service.RawJSON(`{"actor_id":10}`), RequestId: "req-1"},
+		{UserId: &user, Entity: "actor_film", EntityId: &film, Operation: "create", After: service.RawJSON(`{"actor_id":11}`), RequestId: "req-1"},
+		{UserId: &user, Entity: "actor_film", EntityId: &film, Operation: "update",
+			Before: service.RawJSON(`{"actor_id":10,"order":null}`), After: service.RawJSON(`{"actor_id":10,"order":2}`), RequestId: "req-1"},
+	}, entries)
+
+	auditErr = fmt.Errorf("connection refused")
+	repo.EXPECT().DeleteGenre(4).Return(nil).Times(1)
+	require.EqualError(t, useCase.DeleteGenre(context.Background(), 4), "connection refused")
+	require.Equal(t, service.AuditEntry{Entity: "genre", EntityId: &genre, Operation: "delete",
+		Before: service.RawJSON(`{"name":"Drama"}`)}, entries[len(entries)-1])
+}
+
+func TestRevisions(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_service.NewMockRepository(ctr)
+	useCase := NewServiceUsecase(cfg, repo, nil)
+	ctx := service.WithOrigin(context.Background(), service.Origin{UserId: 7})
+	allowTx(repo)
+	repo.EXPECT().Lock("film", 2).Return(nil).AnyTimes()
+	repo.EXPECT().AddAudit(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	user := 7
+	hamlet := service.Film{Name: "Hamlet", RDate: "1996-12-25", Rating: 7.7, Desc: "Branagh"}
+	edited := service.Film{Name: "Hamlet", RDate: "1996-12-25", Rating: 8.1}
+	edit := service.FilmPatch{Rating: service.Patched[float32](8.1), Desc: service.PatchField[string]{Set: true, Null: true}}
+
+	// The first change stores the state before it as revision 1.
+	gomock.InOrder(
+		repo.EXPECT().GetFilmsByIds([]int{2}).Return([]service.Film{hamlet}, nil).Times(1),
+		repo.EXPECT().UpdateFilm(2, &edit).Return(nil).Times(1),
+		repo.EXPECT().GetFilmsByIds([]int{2}).Return([]service.Film{edited}, nil).Times(1),
+		repo.EXPECT().AddRevision(gomock.Any(),
+			&service.Revision{Entity: "film", EntityId: 2, UserId: &user, Data: service.RawJSON(`{"desc":"","name":"Hamlet","rating":8.1,"rdate":"1996-12-25"}`)},
+			service.RawJSON(`{"desc":"Branagh","name":"Hamlet","rating":7.7,"rdate":"1996-12-25"}`)).Return(2, nil).Times(1),
+	)
+	require.NoError(t, useCase.UpdateFilm(ctx, 2, &edit))
+
+	repo.EXPECT().GetRevision("film", 2, 1).Return(&service.Revision{Number: 1,
+		Data: service.RawJSON(`{"desc":"Branagh","name":"Hamlet","rating":7.7,"rdate":"1996-12-25"}`)}, nil).Times(2)
+	repo.EXPECT().GetRevision("film", 2, 2).Return(&service.Revision{Number: 2,
+		Data: service.RawJSON(`{"desc":"","name":"Hamlet","rating":8.1,"rdate":"1996-12-25"}`)}, nil).Times(1)
+
+	diff, err := useCase.DiffRevisions("film", 2, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, &service.RevisionDiff{From: 1, To: 2, Changes: map[string]service.FieldChange{
+		"desc":   {From: "Branagh", To: ""},
+		"rating": {From: 7.7, To: 8.1},
+	}}, diff)
+
+	// A revert sets every field, the cleared description too, and is a revision of its own.
+	gomock.InOrder(
+		repo.EXPECT().GetFilmsByIds([]int{2}).Return([]service.Film{edited}, nil).Times(1),
+		repo.EXPECT().ReplaceFilm(2, &hamlet).Return(nil).Times(1),
+		repo.EXPECT().GetFilmsByIds([]int{2}).Return([]service.Film{hamlet}, nil).Times(1),
+		repo.EXPECT().AddRevision(gomock.Any(), gomock.Any(), gomock.Any()).Return(3, nil).Times(1),
+	)
+	number, err := useCase.RevertFilm(ctx, 2, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, 3, number)
+
+	// A revision that cannot be stored fails the change, which is rolled back with it.
+	gomock.InOrder(
+		repo.EXPECT().GetFilmsByIds([]int{2}).Return([]service.Film{hamlet}, nil).Times(1),
+		repo.EXPECT().UpdateFilm(2, &edit).Return(nil).Times(1),
+		repo.EXPECT().GetFilmsByIds([]int{2}).Return([]service.Film{edited}, nil).Times(1),
+		repo.EXPECT().AddRevision(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, fmt.Errorf("connection refused")).Times(1),
+	)
+	require.EqualError(t, useCase.UpdateFilm(ctx, 2, &edit), "connection refused")
+
+	repo.EXPECT().GetRevision("actor", 5, 9).Return(nil, fmt.Errorf("no revision 9 of actor: %w", service.ErrNotFound)).Times(1)
+	_, err = useCase.RevertActor(ctx, 5, 9, 0)
+	require.ErrorIs(t, err, service.ErrNotFound)
+
+	// An actor that was never changed has no revisions, a missing one is not found.
+	params := &service.DetailsParams{Sort: "number", Limit: 20}
+	repo.EXPECT().GetRevisions("actor", 5, params).Return(&service.RevisionsPage{Items: []service.Revision{}}, nil).Times(2)
+	repo.EXPECT().GetActorsByIds([]int{5}).Return([]service.Actor{{Id: 5}}, nil).Times(1)
+	page, err := useCase.GetRevisions("actor", 5, params)
+	require.NoError(t, err)
+	require.Equal(t, 0, page.Total)
+
+	repo.EXPECT().GetActorsByIds([]int{5}).Return(nil, nil).Times(1)
+	_, err = useCase.GetRevisions("actor", 5, params)
+	require.ErrorIs(t, err, service.ErrNotFound)
 }
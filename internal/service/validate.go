@@ -0,0 +1,166 @@
+package service
+
+import (
+	"fmt"
+	"golang.org/x/text/unicode/norm"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var patternDate = regexp.MustCompile("[1-2][0-9][0-9][0-9]-[0-1][0-9]-[0-3][0-9]")
+
+// ValidateActor checks a new actor. The name is trimmed and brought to NFC
+// form, so the same name typed differently is stored and found the same way.
+func ValidateActor(data *Actor) error {
+	data.Name = NormalizeName(data.Name)
+	if err := ValidName(data.Name, 100); err != nil {
+		return err
+	}
+	if err := validSex(data.Sex); err != nil {
+		return err
+	}
+	if !ValidDate(data.BDate) {
+		return fmt.Errorf("bdate should be '2000-01-01' format")
+	}
+
+	return nil
+}
+
+// ValidateActorPatch checks the fields set by a patch with the rules of
+// ValidateActor. Every field of an actor is required, so none can be cleared.
+func ValidateActorPatch(data *ActorPatch) error {
+	switch {
+	case data.Empty():
+		return fmt.Errorf("patch should set at least one of name, sex, bdate")
+	case data.Name.Null:
+		return fmt.Errorf("name can't be cleared")
+	case data.Sex.Null:
+		return fmt.Errorf("sex can't be cleared")
+	case data.BDate.Null:
+		return fmt.Errorf("bdate can't be cleared")
+	}
+
+	if data.Name.Set {
+		data.Name.Value = NormalizeName(data.Name.Value)
+		if err := ValidName(data.Name.Value, 100); err != nil {
+			return err
+		}
+	}
+	if data.Sex.Set {
+		if err := validSex(data.Sex.Value); err != nil {
+			return err
+		}
+	}
+	if data.BDate.Set && !ValidDate(data.BDate.Value) {
+		return fmt.Errorf("bdate should be '2000-01-01' format")
+	}
+
+	return nil
+}
+
+// ValidateFilm checks a new film; the name is normalized like in ValidateActor.
+func ValidateFilm(data *Film) error {
+	data.Name = NormalizeName(data.Name)
+	if err := ValidName(data.Name, 150); err != nil {
+		return err
+	}
+	if err := validDesc(data.Desc); err != nil {
+		return err
+	}
+	if err := validRating(data.Rating); err != nil {
+		return err
+	}
+	if !ValidDate(data.RDate) {
+		return fmt.Errorf("rdate should be '2000-01-01' format")
+	}
+
+	return nil
+}
+
+// ValidateFilmPatch checks the fields set by a patch with the rules of
+// ValidateFilm. Only the description is optional and can be cleared.
+func ValidateFilmPatch(data *FilmPatch) error {
+	switch {
+	case data.Empty():
+		return fmt.Errorf("patch should set at least one of name, rdate, rating, desc")
+	case data.Name.Null:
+		return fmt.Errorf("name can't be cleared")
+	case data.RDate.Null:
+		return fmt.Errorf("rdate can't be cleared")
+	case data.Rating.Null:
+		return fmt.Errorf("rating can't be cleared")
+	}
+
+	if data.Name.Set {
+		data.Name.Value = NormalizeName(data.Name.Value)
+		if err := ValidName(data.Name.Value, 150); err != nil {
+			return err
+		}
+	}
+	if data.Desc.Set {
+		if err := validDesc(data.Desc.Value); err != nil {
+			return err
+		}
+	}
+	if data.Rating.Set {
+		if err := validRating(data.Rating.Value); err != nil {
+			return err
+		}
+	}
+	if data.RDate.Set && !ValidDate(data.RDate.Value) {
+		return fmt.Errorf("rdate should be '2000-01-01' format")
+	}
+
+	return nil
+}
+
+func validSex(sex string) error {
+	if sex != "f" && sex != "m" {
+		return fmt.Errorf("sex should be 'm' - male or 'f' - famale")
+	}
+
+	return nil
+}
+
+func validDesc(desc string) error {
+	if !utf8.ValidString(desc) || utf8.RuneCountInString(desc) > 1000 {
+		return fmt.Errorf("size Name should be < 1000 symbols")
+	}
+
+	return nil
+}
+
+func validRating(rating float32) error {
+	if rating <= 0 || rating > 10 {
+		return fmt.Errorf("rating should be (0;10]")
+	}
+
+	return nil
+}
+
+// ValidDate checks that date is in '2000-01-01' format.
+func ValidDate(date string) bool {
+	return patternDate.MatchString(date)
+}
+
+func NormalizeName(name string) string {
+	return norm.NFC.String(strings.TrimSpace(name))
+}
+
+// ValidName checks that name is 1 to max characters of printable UTF-8 text.
+// Length is counted in characters, not bytes.
+func ValidName(name string, max int) error {
+	if !utf8.ValidString(name) {
+		return fmt.Errorf("name should be UTF-8 text")
+	}
+	if n := utf8.RuneCountInString(name); n == 0 || n > max {
+		return fmt.Errorf("size Name should be [1;%d]", max)
+	}
+	if strings.ContainsFunc(name, unicode.IsControl) {
+		return fmt.Errorf("name should not contain control characters")
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"film_library/internal/auth"
+	"film_library/internal/cconstant"
+	"film_library/internal/transfer"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// contentFormats maps the content type of an import body onto its format.
+var contentFormats = map[string]string{
+	"text/csv":             "csv",
+	"application/json":     "json",
+	"application/x-ndjson": "jsonl",
+	"application/jsonl":    "jsonl",
+	"application/zip":      "zip",
+}
+
+// exportFiles are the content type and file extension of every export format.
+var exportFiles = map[string]struct{ contentType, ext string }{
+	"jsonl": {"application/x-ndjson", "jsonl"},
+	"json":  {"application/json", "json"},
+	"csv":   {"application/zip", "zip"},
+}
+
+type TransferHandler struct {
+	transferUC transfer.Usecase
+	authUC     auth.Usecase
+}
+
+func NewTransferHandler(transferUC transfer.Usecase, authUC auth.Usecase) *TransferHandler {
+	return &TransferHandler{
+		transferUC: transferUC,
+		authUC:     authUC,
+	}
+}
+
+// @Summary      Import
+// @Description  Import actors, films, relations, crew and genres from a CSV file with a header row, a JSON array, JSON Lines
+// @Description  or the zip of a csv export, whose files are read in the order actors, films, relations, crews, genres.
+// @Description  Records are written in batched transactions; the report lists every record that was not imported.
+// @Tags         transfer
+// @Accept       plain
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        format 		query   string false "File format, taken from Content-Type when not set" Enums(csv, json, jsonl, zip)
+// @Param        dry_run 		query   bool   false "Validate and write everything, then roll back"
+// @Param        input	body	string  true  "records"
+// @Success      200  {object}	transfer.Report
+// @Failure      400  {object}	error
+// @Failure      403  {object}	error
+// @Failure      413  {object}	error
+// @Failure      500  {object}  error
+// @Router       /import [post]
+func (h *TransferHandler) Import(rw http.ResponseWriter, r *http.Request) {
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: Import. User with ID:%d", tokenData.Id)
+
+	params, err := importParams(r)
+	if err != nil {
+		log.Printf("Request: Import. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	body := http.MaxBytesReader(rw, r.Body, cconstant.MaxImportSize)
+	report, err := h.transferUC.Import(r.Context(), body, params)
+	if err != nil {
+		log.Printf("Request: Import. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+	log.Printf("Request: Import. Imported %d of %d records, dry run: %t", report.Imported, report.Total, report.DryRun)
+
+	rawResponse, _ := json.Marshal(report)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      Export
+// @Description  Export every actor, film, relation, crew job and genre, streamed as it is read from the database.
+// @Description  jsonl and json hold the records of the import; csv is a zip with one file per kind, e.g. actors.csv.
+// @Tags         transfer
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      application/zip
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        format 		query   string false "jsonl by default" Enums(jsonl, csv, json)
+// @Success      200  {file}	file
+// @Failure      400  {object}	error
+// @Failure      403  {object}	error
+// @Failure      500  {object}  error
+// @Router       /export [get]
+func (h *TransferHandler) Export(rw http.ResponseWriter, r *http.Request) {
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: Export. User with ID:%d", tokenData.Id)
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "jsonl"
+	}
+	file, ok := exportFiles[format]
+	if !ok {
+		log.Printf("Request: Export. Error: unknown format %q", format)
+		http.Error(rw, fmt.Sprintf("format should be one of %s", strings.Join(cconstant.ExportFormats, ", ")), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set("Content-Type", file.contentType)
+	rw.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="film_library.%s"`, file.ext))
+
+	// Once the first byte is sent the status cannot change, so a later error only cuts the body short.
+	w := &countingWriter{w: rw}
+	if err := h.transferUC.Export(r.Context(), w, format); err != nil {
+		log.Printf("Request: Export. Error: %s", err.Error())
+		if w.n == 0 {
+			rw.Header().Del("Content-Disposition")
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	log.Printf("Request: Export. Sent %d bytes", w.n)
+}
+
+//---------------------------------------------------------------------------------------------------------------------
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func importParams(r *http.Request) (*transfer.ImportParams, error) {
+	var (
+		query  = r.URL.Query()
+		params = &transfer.ImportParams{Format: strings.ToLower(query.Get("format"))}
+		err    error
+	)
+
+	if params.Format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		params.Format = contentFormats[mediaType]
+	}
+	if !slices.Contains(cconstant.ImportFormats, params.Format) {
+		return nil, fmt.Errorf("format should be one of %s", strings.Join(cconstant.ImportFormats, ", "))
+	}
+
+	if rawDryRun := query.Get("dry_run"); rawDryRun != "" {
+		if params.DryRun, err = strconv.ParseBool(rawDryRun); err != nil {
+			return nil, fmt.Errorf("dry_run should be true or false")
+		}
+	}
+
+	return params, nil
+}
+
+func errorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, transfer.ErrBadFile):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"film_library/internal/auth"
+	mock_auth "film_library/internal/auth/mocks"
+	"film_library/internal/transfer"
+	mock_transfer "film_library/internal/transfer/mocks"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	type mockBehavior func(s *mock_transfer.MockUsecase)
+
+	testTable := []struct {
+		name               string
+		path               string
+		contentType        string
+		role               int
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: "DryRun",
+			path: "/api/import?format=csv&dry_run=true",
+			role: 1,
+			mockBehavior: func(s *mock_transfer.MockUsecase) {
+				s.EXPECT().Import(gomock.Any(), gomock.Any(), &transfer.ImportParams{Format: "csv", DryRun: true}).Return(&transfer.Report{
+					DryRun: true, Total: 2, Imported: 1, Failed: 1,
+					Errors: []transfer.RowError{{Row: 3, Kind: "film", Name: "Hamlet", Error: "rating should be (0;10]"}},
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"dry_run":true,"total":2,"imported":1,"failed":1,` +
+				`"errors":[{"row":3,"kind":"film","name":"Hamlet","error":"rating should be (0;10]"}]}`,
+		},
+		{
+			name:        "FormatFromContentType",
+			path:        "/api/import",
+			contentType: "application/x-ndjson; charset=utf-8",
+			role:        1,
+			mockBehavior: func(s *mock_transfer.MockUsecase) {
+				s.EXPECT().Import(gomock.Any(), gomock.Any(), &transfer.ImportParams{Format: "jsonl"}).Return(&transfer.Report{Errors: []transfer.RowError{}}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"dry_run":false,"total":0,"imported":0,"failed":0,"errors":[]}`,
+		},
+		{
+			name:               "NoFormat",
+			path:               "/api/import",
+			role:               1,
+			mockBehavior:       func(s *mock_transfer.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "format should be one of csv, json, jsonl, zip\n",
+		},
+		{
+			name:               "BadDryRun",
+			path:               "/api/import?format=json&dry_run=maybe",
+			role:               1,
+			mockBehavior:       func(s *mock_transfer.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "dry_run should be true or false\n",
+		},
+		{
+			name: "BadFile",
+			path: "/api/import?format=json",
+			role: 1,
+			mockBehavior: func(s *mock_transfer.MockUsecase) {
+				s.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("json should be an array of records: %w", transfer.ErrBadFile)).Times(1)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "json should be an array of records: bad file\n",
+		},
+		{
+			name:               "NotAdmin",
+			path:               "/api/import?format=csv",
+			mockBehavior:       func(s *mock_transfer.MockUsecase) {},
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       "You don't have permission for this operation.\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockTransfer := mock_transfer.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			mockAuth.EXPECT().ParseToken("token").Return(&auth.TokenData{Id: 1, Role: testCase.role}, nil).AnyTimes()
+			testCase.mockBehavior(mockTransfer)
+
+			handler := NewTransferHandler(mockTransfer, mockAuth)
+			rtr := mux.NewRouter()
+			MapRoutes(rtr, handler)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, testCase.path, bytes.NewBufferString("[]"))
+			r.Header.Set("Authorization", "Bearer token")
+			r.Header.Set("Content-Type", testCase.contentType)
+			rtr.ServeHTTP(w, r)
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	type mockBehavior func(s *mock_transfer.MockUsecase)
+
+	testTable := []struct {
+		name                string
+		path                string
+		role                int
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name: "JSONL",
+			path: "/api/export",
+			role: 1,
+			mockBehavior: func(s *mock_transfer.MockUsecase) {
+				s.EXPECT().Export(gomock.Any(), gomock.Any(), "jsonl").DoAndReturn(func(ctx context.Context, w io.Writer, format string) error {
+					_, err := io.WriteString(w, `{"kind":"film","name":"Hamlet"}`+"\n")
+					return err
+				}).Times(1)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        `{"kind":"film","name":"Hamlet"}` + "\n",
+		},
+		{
+			name: "FailedBeforeWriting",
+			path: "/api/export?format=csv",
+			role: 1,
+			mockBehavior: func(s *mock_transfer.MockUsecase) {
+				s.EXPECT().Export(gomock.Any(), gomock.Any(), "csv").Return(errors.New("connection refused")).Times(1)
+			},
+			expectedStatusCode:  http.StatusInternalServerError,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "connection refused\n",
+		},
+		{
+			name:                "BadFormat",
+			path:                "/api/export?format=xml",
+			role:                1,
+			mockBehavior:        func(s *mock_transfer.MockUsecase) {},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "format should be one of jsonl, csv, json\n",
+		},
+		{
+			name:                "NotAdmin",
+			path:                "/api/export",
+			mockBehavior:        func(s *mock_transfer.MockUsecase) {},
+			expectedStatusCode:  http.StatusForbidden,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "You don't have permission for this operation.\n",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockTransfer := mock_transfer.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			mockAuth.EXPECT().ParseToken("token").Return(&auth.TokenData{Id: 1, Role: testCase.role}, nil).AnyTimes()
+			testCase.mockBehavior(mockTransfer)
+
+			handler := NewTransferHandler(mockTransfer, mockAuth)
+			rtr := mux.NewRouter()
+			MapRoutes(rtr, handler)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			r.Header.Set("Authorization", "Bearer token")
+			rtr.ServeHTTP(w, r)
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedContentType, w.Header().Get("Content-Type"))
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"context"
+	"film_library/internal/cconstant"
+	"film_library/internal/service"
+	"film_library/pkg/requestid"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func (h *TransferHandler) userIdentity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(cconstant.AuthHeader)
+		if header == "" {
+			http.Error(rw, fmt.Sprintf("empty auth header"), http.StatusUnauthorized)
+			return
+		}
+
+		headerParts := strings.Split(header, " ")
+		if len(headerParts) != 2 {
+			http.Error(rw, fmt.Sprintf("invalid auth header"), http.StatusUnauthorized)
+			return
+		}
+
+		tokenData, err := h.authUC.ParseToken(headerParts[1])
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		ctx := context.WithValue(r.Context(), "tokenData", tokenData)
+		ctx = service.WithOrigin(ctx, service.Origin{UserId: tokenData.Id, RequestId: requestid.FromContext(ctx)})
+
+		next.ServeHTTP(rw, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,18 @@
+package http
+
+import (
+	"film_library/internal/auth"
+	authHttp "film_library/internal/auth/delivery/http"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+// MapRoutes registers the bulk transfer routes.
+func MapRoutes(rtr *mux.Router, h *TransferHandler) {
+	transfer := authHttp.Require(auth.PermTransfer)
+
+	api := rtr.PathPrefix("/api").Subrouter()
+	api.Use(h.userIdentity)
+	api.HandleFunc("/import", transfer(h.Import)).Methods(http.MethodPost)
+	api.HandleFunc("/export", transfer(h.Export)).Methods(http.MethodGet)
+}
@@ -0,0 +1,10 @@
+package transfer
+
+import "errors"
+
+var (
+	// ErrBadRow marks a record that cannot be read; the import goes on with the next one.
+	ErrBadRow = errors.New("bad row")
+	// ErrBadFile marks a file the import cannot read any further.
+	ErrBadFile = errors.New("bad file")
+)
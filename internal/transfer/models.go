@@ -0,0 +1,100 @@
+package transfer
+
+// Record is one row of an import file. Kind selects the fields that are used:
+//   - an actor has name, sex and bdate, a film has name, rdate, rating and desc;
+//     both may carry their id in the catalogue they come from and the
+//     external_id of the provider they were synced from;
+//   - a relation casts an actor in a film, optionally with the character,
+//     the credit type and the billing order;
+//   - a crew record gives the person actor the job in the film;
+//   - a genre record gives the film the genre called name.
+//
+// The last three name their actor and film by actor_id and film_id, the ids
+// of records earlier in the same file, or else by actor and film, which then
+// have to be the names of a single actor and film. An export is made of the
+// same records with the ids, so it can be imported again even when names are
+// shared.
+type Record struct {
+	Kind       string  `json:"kind" db:"kind"`
+	Id         int     `json:"id,omitempty" db:"id"`
+	ExternalId string  `json:"external_id,omitempty" db:"external_id"`
+	Name       string  `json:"name,omitempty" db:"name"`
+	Sex        string  `json:"sex,omitempty" db:"sex"`
+	BDate      string  `json:"bdate,omitempty" db:"bdate"`
+	RDate      string  `json:"rdate,omitempty" db:"rdate"`
+	Rating     float32 `json:"rating,omitempty" db:"rating"`
+	Desc       string  `json:"desc,omitempty" db:"desc"`
+	ActorId    int     `json:"actor_id,omitempty" db:"actor_id"`
+	FilmId     int     `json:"film_id,omitempty" db:"film_id"`
+	Actor      string  `json:"actor,omitempty" db:"actor"`
+	Film       string  `json:"film,omitempty" db:"film"`
+	Character  string  `json:"character,omitempty" db:"character"`
+	Type       string  `json:"type,omitempty" db:"type"`
+	Order      int     `json:"order,omitempty" db:"order"`
+	Job        string  `json:"job,omitempty" db:"job"`
+}
+
+type ImportParams struct {
+	// Format of the import file; a sync does not use it.
+	Format string `json:"format"`
+	// DryRun validates and writes every record, then rolls everything back.
+	DryRun bool `json:"dry_run"`
+	// BatchSize is the number of records committed in one transaction.
+	BatchSize int `json:"batch_size"`
+}
+
+// Report sums up an import. In a dry run Imported counts the records that would be imported.
+type Report struct {
+	DryRun   bool       `json:"dry_run"`
+	Total    int        `json:"total"`
+	Imported int        `json:"imported"`
+	Failed   int        `json:"failed"`
+	Errors   []RowError `json:"errors"`
+}
+
+// RowError is a record that was not imported. Row is the line of a CSV or
+// JSON Lines file and the position in a JSON array, starting from 1.
+type RowError struct {
+	Row   int    `json:"row"`
+	Kind  string `json:"kind,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error"`
+}
+
+// ExternalRecord is one entry of an outside catalogue; exactly one field is set.
+// People and films come before the credits naming them.
+type ExternalRecord struct {
+	Person *ExternalPerson `json:"person,omitempty"`
+	Film   *ExternalFilm   `json:"film,omitempty"`
+	Credit *ExternalCredit `json:"credit,omitempty"`
+}
+
+// ExternalPerson is a person of an outside catalogue. Id is unique within the
+// provider; empty Sex and BDate are unknown and keep what is stored.
+type ExternalPerson struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Sex   string `json:"sex,omitempty"`
+	BDate string `json:"bdate,omitempty"`
+}
+
+// ExternalFilm is a film of an outside catalogue; nil Rating and Desc are unknown
+// and keep what is stored. Genres are added to the ones the film has.
+type ExternalFilm struct {
+	Id     string   `json:"id"`
+	Name   string   `json:"name"`
+	RDate  string   `json:"rdate"`
+	Rating *float32 `json:"rating,omitempty"`
+	Desc   *string  `json:"desc,omitempty"`
+	Genres []string `json:"genres,omitempty"`
+}
+
+// ExternalCredit puts a person in a film: an acting part when Job is empty,
+// otherwise a crew job. Order is the billing order, 0 when unknown.
+type ExternalCredit struct {
+	FilmId    string `json:"film_id"`
+	PersonId  string `json:"person_id"`
+	Job       string `json:"job,omitempty"`
+	Character string `json:"character,omitempty"`
+	Order     int    `json:"order,omitempty"`
+}
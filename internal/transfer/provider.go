@@ -0,0 +1,11 @@
+package transfer
+
+import "context"
+
+// MetadataProvider reads films, people and credits from an outside catalogue.
+type MetadataProvider interface {
+	// Name prefixes the ids of the provider in the database, e.g. "imdb".
+	Name() string
+	// Fetch calls fn for every record, people and films before the credits naming them.
+	Fetch(ctx context.Context, fn func(rec *ExternalRecord) error) error
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"film_library/internal/transfer"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HTTPProvider reads records from a catalogue service that answers GET url
+// with JSON Lines of transfer.ExternalRecord, people and films first.
+type HTTPProvider struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+// NewHTTPProvider stores the ids of the service under name; client is
+// http.DefaultClient when nil.
+func NewHTTPProvider(name, url string, client *http.Client) *HTTPProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HTTPProvider{name: name, url: url, client: client}
+}
+
+func (p *HTTPProvider) Name() string {
+	return p.name
+}
+
+func (p *HTTPProvider) Fetch(ctx context.Context, fn func(rec *transfer.ExternalRecord) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %s", p.name, resp.Status)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	dec.DisallowUnknownFields()
+	for record := 1; ; record++ {
+		var rec transfer.ExternalRecord
+		if err = dec.Decode(&rec); err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s record %d: %w", p.name, record, err)
+		}
+
+		if err = fn(&rec); err != nil {
+			return err
+		}
+	}
+}
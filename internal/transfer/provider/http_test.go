@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"film_library/internal/transfer"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	cases := []struct {
+		name    string
+		status  int
+		body    string
+		expRecs int
+		expErr  string
+	}{
+		{
+			name:   "OK",
+			status: http.StatusOK,
+			body: `{"person":{"id":"p1","name":"Kate Winslet","sex":"f","bdate":"1975-10-05"}}` + "\n" +
+				`{"film":{"id":"f1","name":"Titanic","rdate":"1997","genres":["Drama"]}}` + "\n\n" +
+				`{"credit":{"film_id":"f1","person_id":"p1","character":"Rose","order":2}}` + "\n",
+			expRecs: 3,
+		},
+		{
+			name:   "UnknownKey",
+			status: http.StatusOK,
+			body: `{"film":{"id":"f1","name":"Titanic","rdate":"1997"}}` + "\n" +
+				`{"film":{"id":"f2","title":"Hamlet"}}` + "\n",
+			expRecs: 1,
+			expErr:  `catalogue record 2: json: unknown field "title"`,
+		},
+		{
+			name:   "Status",
+			status: http.StatusBadGateway,
+			expErr: "catalogue answered 502 Bad Gateway",
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/records", r.URL.Path)
+				w.WriteHeader(tCase.status)
+				w.Write([]byte(tCase.body))
+			}))
+			defer server.Close()
+
+			var records []*transfer.ExternalRecord
+			err := NewHTTPProvider("catalogue", server.URL+"/records", server.Client()).
+				Fetch(context.Background(), func(rec *transfer.ExternalRecord) error {
+					records = append(records, rec)
+					return nil
+				})
+			if tCase.expErr != "" {
+				require.EqualError(t, err, tCase.expErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Len(t, records, tCase.expRecs)
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package provider
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"film_library/internal/transfer"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// imdbNull is how the IMDb datasets write an unknown value.
+const imdbNull = `\N`
+
+// imdbCrewJobs maps the title.principals categories onto crew jobs; actor,
+// actress and self are acting parts and every other category is skipped.
+var imdbCrewJobs = map[string]string{
+	"director":        "director",
+	"writer":          "writer",
+	"producer":        "producer",
+	"composer":        "composer",
+	"cinematographer": "cinematographer",
+}
+
+// IMDbProvider reads the IMDb datasets (https://datasets.imdbws.com) from a
+// directory: title.basics, name.basics and title.principals, and title.ratings
+// when it is there, each as .tsv or as the downloaded .tsv.gz. The files are
+// streamed; only the ids of the synced titles and people are kept in memory.
+//
+// IMDb knows the years of birth and release only, so dates are bare years.
+// People get their sex from being credited as actor or actress.
+type IMDbProvider struct {
+	dir        string
+	titleTypes []string
+	titles     []string
+}
+
+// NewIMDbProvider syncs the titles of titleTypes ("movie" when empty), only
+// the titles listed when titles is not empty.
+func NewIMDbProvider(dir string, titleTypes, titles []string) *IMDbProvider {
+	if len(titleTypes) == 0 {
+		titleTypes = []string{"movie"}
+	}
+
+	return &IMDbProvider{dir: dir, titleTypes: titleTypes, titles: titles}
+}
+
+func (p *IMDbProvider) Name() string {
+	return "imdb"
+}
+
+func (p *IMDbProvider) Fetch(ctx context.Context, fn func(rec *transfer.ExternalRecord) error) error {
+	films := make(map[string]struct{})
+	err := p.readTSV(ctx, "title.basics", func(row tsvRow) error {
+		if p.keepTitle(row) {
+			films[row.get("tconst")] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ratings := make(map[string]float32)
+	err = p.readTSV(ctx, "title.ratings", func(row tsvRow) error {
+		if _, ok := films[row.get("tconst")]; ok {
+			rating, err := strconv.ParseFloat(row.get("averageRating"), 32)
+			if err == nil {
+				ratings[row.get("tconst")] = float32(rating)
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// The people come first, but which people are needed is only known from the credits.
+	people := make(map[string]string)
+	err = p.readTSV(ctx, "title.principals", func(row tsvRow) error {
+		if _, ok := films[row.get("tconst")]; ok {
+			if sex, ok := creditedSex(row.get("category")); ok || people[row.get("nconst")] == "" {
+				people[row.get("nconst")] = sex
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = p.readTSV(ctx, "name.basics", func(row tsvRow) error {
+		sex, ok := people[row.get("nconst")]
+		if !ok {
+			return nil
+		}
+		if sex == "" {
+			sex = professionSex(row.get("primaryProfession"))
+		}
+
+		return fn(&transfer.ExternalRecord{Person: &transfer.ExternalPerson{
+			Id:    row.get("nconst"),
+			Name:  row.get("primaryName"),
+			Sex:   sex,
+			BDate: row.get("birthYear"),
+		}})
+	})
+	if err != nil {
+		return err
+	}
+
+	err = p.readTSV(ctx, "title.basics", func(row tsvRow) error {
+		if _, ok := films[row.get("tconst")]; !ok {
+			return nil
+		}
+
+		film := &transfer.ExternalFilm{Id: row.get("tconst"), Name: row.get("primaryTitle"), RDate: row.get("startYear")}
+		if rating, ok := ratings[film.Id]; ok {
+			film.Rating = &rating
+		}
+		if genres := row.get("genres"); genres != "" {
+			film.Genres = strings.Split(genres, ",")
+		}
+
+		return fn(&transfer.ExternalRecord{Film: film})
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.readTSV(ctx, "title.principals", func(row tsvRow) error {
+		if _, ok := films[row.get("tconst")]; !ok {
+			return nil
+		}
+
+		credit := &transfer.ExternalCredit{FilmId: row.get("tconst"), PersonId: row.get("nconst")}
+		switch category := row.get("category"); category {
+		case "actor", "actress", "self":
+			credit.Character = characters(row.get("characters"))
+			credit.Order, _ = strconv.Atoi(row.get("ordering"))
+		default:
+			if credit.Job = imdbCrewJobs[category]; credit.Job == "" {
+				return nil
+			}
+		}
+
+		return fn(&transfer.ExternalRecord{Credit: credit})
+	})
+}
+
+func (p *IMDbProvider) keepTitle(row tsvRow) bool {
+	if row.get("isAdult") == "1" || !slices.Contains(p.titleTypes, row.get("titleType")) {
+		return false
+	}
+
+	return len(p.titles) == 0 || slices.Contains(p.titles, row.get("tconst"))
+}
+
+// creditedSex tells the sex of a person credited in category, if it does.
+func creditedSex(category string) (string, bool) {
+	switch category {
+	case "actor":
+		return "m", true
+	case "actress":
+		return "f", true
+	}
+
+	return "", false
+}
+
+func professionSex(professions string) string {
+	for _, profession := range strings.Split(professions, ",") {
+		if sex, ok := creditedSex(profession); ok {
+			return sex
+		}
+	}
+
+	return ""
+}
+
+// characters turns the JSON array of characters of a part into one name.
+func characters(raw string) string {
+	var names []string
+	if raw == "" || json.Unmarshal([]byte(raw), &names) != nil {
+		return ""
+	}
+
+	return strings.Join(names, " / ")
+}
+
+// ----------------------------------------------------- TSV ----------------------------------------------------------
+
+// tsvRow is a row of a dataset together with the column numbers of its header.
+type tsvRow struct {
+	columns map[string]int
+	cells   []string
+}
+
+// get returns the cell of column, "" when it is unknown.
+func (r tsvRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.cells) || r.cells[i] == imdbNull {
+		return ""
+	}
+
+	return r.cells[i]
+}
+
+// readTSV calls fn for every row of the dataset name. The datasets do not
+// quote, a tab always separates cells. The error wraps fs.ErrNotExist when
+// the file is not in the directory.
+func (p *IMDbProvider) readTSV(ctx context.Context, name string, fn func(row tsvRow) error) error {
+	r, err := p.open(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	if !scanner.Scan() {
+		return fmt.Errorf("%s: no header row", name)
+	}
+
+	row := tsvRow{columns: make(map[string]int)}
+	for i, column := range strings.Split(scanner.Text(), "\t") {
+		row.columns[column] = i
+	}
+
+	for line := 2; scanner.Scan(); line++ {
+		if line%10000 == 0 && ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		row.cells = strings.Split(scanner.Text(), "\t")
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	return nil
+}
+
+// open opens name.tsv.gz or else name.tsv.
+func (p *IMDbProvider) open(name string) (io.ReadCloser, error) {
+	file, err := os.Open(filepath.Join(p.dir, name+".tsv.gz"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return os.Open(filepath.Join(p.dir, name+".tsv"))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return &gzipFile{Reader: gz, file: file}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipFile) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"compress/gzip"
+	"context"
+	"film_library/internal/transfer"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeDataset writes the rows of a dataset to dir, gzipped like the downloads when gz is set.
+func writeDataset(t *testing.T, dir, name string, gz bool, rows ...string) {
+	data := strings.Join(rows, "\n") + "\n"
+
+	if !gz {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".tsv"), []byte(data), 0o644))
+		return
+	}
+
+	file, err := os.Create(filepath.Join(dir, name+".tsv.gz"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	w := gzip.NewWriter(file)
+	_, err = w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+}
+
+func TestIMDbProvider(t *testing.T) {
+	dir := t.TempDir()
+	writeDataset(t, dir, "title.basics", true,
+		"tconst\ttitleType\tprimaryTitle\toriginalTitle\tisAdult\tstartYear\tendYear\truntimeMinutes\tgenres",
+		"tt0116477\tmovie\tHamlet\tHamlet\t0\t1996\t\\N\t242\tDrama",
+		"tt0120338\tmovie\tTitanic\tTitanic\t0\t1997\t\\N\t194\tDrama,Romance",
+		"tt0944947\ttvSeries\tGame of Thrones\tGame of Thrones\t0\t2011\t2019\t57\tAction",
+	)
+	writeDataset(t, dir, "title.ratings", false,
+		"tconst\taverageRating\tnumVotes",
+		"tt0116477\t7.7\t40000",
+		"tt0944947\t9.2\t2000000",
+	)
+	writeDataset(t, dir, "name.basics", true,
+		"nconst\tprimaryName\tbirthYear\tdeathYear\tprimaryProfession\tknownForTitles",
+		"nm0000110\tKenneth Branagh\t1960\t\\N\tactor,director,producer\ttt0116477",
+		"nm0000701\tKate Winslet\t1975\t\\N\tactress,producer\ttt0120338",
+		"nm0000116\tJames Cameron\t1954\t\\N\tdirector,writer\ttt0120338",
+		"nm0000293\tSean Bean\t1959\t\\N\tactor\ttt0944947",
+	)
+	writeDataset(t, dir, "title.principals", false,
+		"tconst\tordering\tnconst\tcategory\tjob\tcharacters",
+		`tt0116477	1	nm0000110	actor	\N	["Hamlet"]`,
+		`tt0116477	2	nm0000110	director	\N	\N`,
+		`tt0120338	2	nm0000701	actress	\N	["Rose Dewitt Bukater","Rose Calvert"]`,
+		`tt0120338	5	nm0000116	director	\N	\N`,
+		`tt0120338	6	nm0000116	editor	\N	\N`,
+		`tt0944947	1	nm0000293	actor	\N	["Eddard Stark"]`,
+	)
+
+	var records []*transfer.ExternalRecord
+	err := NewIMDbProvider(dir, nil, nil).Fetch(context.Background(), func(rec *transfer.ExternalRecord) error {
+		records = append(records, rec)
+		return nil
+	})
+	require.NoError(t, err)
+
+	rating := float32(7.7)
+	require.Equal(t, []*transfer.ExternalRecord{
+		{Person: &transfer.ExternalPerson{Id: "nm0000110", Name: "Kenneth Branagh", Sex: "m", BDate: "1960"}},
+		{Person: &transfer.ExternalPerson{Id: "nm0000701", Name: "Kate Winslet", Sex: "f", BDate: "1975"}},
+		{Person: &transfer.ExternalPerson{Id: "nm0000116", Name: "James Cameron", BDate: "1954"}},
+		{Film: &transfer.ExternalFilm{Id: "tt0116477", Name: "Hamlet", RDate: "1996", Rating: &rating, Genres: []string{"Drama"}}},
+		{Film: &transfer.ExternalFilm{Id: "tt0120338", Name: "Titanic", RDate: "1997", Genres: []string{"Drama", "Romance"}}},
+		{Credit: &transfer.ExternalCredit{FilmId: "tt0116477", PersonId: "nm0000110", Character: "Hamlet", Order: 1}},
+		{Credit: &transfer.ExternalCredit{FilmId: "tt0116477", PersonId: "nm0000110", Job: "director"}},
+		{Credit: &transfer.ExternalCredit{FilmId: "tt0120338", PersonId: "nm0000701", Character: "Rose Dewitt Bukater / Rose Calvert", Order: 2}},
+		{Credit: &transfer.ExternalCredit{FilmId: "tt0120338", PersonId: "nm0000116", Job: "director"}},
+	}, records)
+}
+
+func TestIMDbProviderTitles(t *testing.T) {
+	dir := t.TempDir()
+	writeDataset(t, dir, "title.basics", false,
+		"tconst\ttitleType\tprimaryTitle\tisAdult\tstartYear\tgenres",
+		"tt0116477\tmovie\tHamlet\t0\t1996\tDrama",
+		"tt0120338\tmovie\tTitanic\t0\t1997\tDrama",
+		"tt0944947\ttvSeries\tGame of Thrones\t0\t2011\tAction",
+	)
+	writeDataset(t, dir, "name.basics", false, "nconst\tprimaryName\tbirthYear\tprimaryProfession")
+	writeDataset(t, dir, "title.principals", false, "tconst\tordering\tnconst\tcategory\tcharacters")
+
+	var names []string
+	err := NewIMDbProvider(dir, []string{"movie", "tvSeries"}, []string{"tt0120338", "tt0944947"}).
+		Fetch(context.Background(), func(rec *transfer.ExternalRecord) error {
+			names = append(names, rec.Film.Name)
+			return nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Titanic", "Game of Thrones"}, names)
+}
+
+func TestIMDbProviderMissingDataset(t *testing.T) {
+	dir := t.TempDir()
+	writeDataset(t, dir, "title.basics", false, "tconst\ttitleType\tprimaryTitle\tisAdult\tstartYear\tgenres")
+
+	err := NewIMDbProvider(dir, nil, nil).Fetch(context.Background(), func(*transfer.ExternalRecord) error { return nil })
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
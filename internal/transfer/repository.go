@@ -0,0 +1,23 @@
+package transfer
+
+import "context"
+
+type Repository interface {
+	// Begin starts a transaction writing on behalf of the origin in ctx, see service.OriginFrom.
+	Begin(ctx context.Context) (Tx, error)
+	// Export calls fn for every actor, then every film, then every relation,
+	// all read from one snapshot of the database.
+	Export(ctx context.Context, fn func(rec *Record) error) error
+}
+
+// Tx writes records in one transaction. Every record gets its own savepoint,
+// so a failing record is rolled back alone and the rest of the batch stays.
+// Every row a record changes is written to the audit log, and every actor or film
+// it changes gets a revision, in the same transaction.
+type Tx interface {
+	Import(rec *Record) (int, error)
+	// Upsert writes a record of the provider, updating the rows synced from it before.
+	Upsert(provider string, rec *ExternalRecord) (int, error)
+	Commit() error
+	Rollback() error
+}
@@ -0,0 +1,730 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"film_library/internal/cconstant"
+	"film_library/internal/service"
+	serviceRepository "film_library/internal/service/repository"
+	"film_library/internal/transfer"
+	"fmt"
+	"github.com/jackc/pgx"
+	"github.com/jmoiron/sqlx"
+	"reflect"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+	notNullViolation    = "23502"
+)
+
+// exportFetchSize is the number of rows fetched from the export cursor at once.
+const exportFetchSize = 500
+
+// exportQueries select the records of every kind in the order they are exported;
+// an actor and a film come before the relations, crew and genres naming them
+// by id. The trash is not exported.
+var exportQueries = []string{
+	fmt.Sprintf(`
+		SELECT 'actor' AS kind, id, COALESCE(external_id, '') AS external_id,
+		       person_name AS name, COALESCE(sex, '') AS sex, COALESCE(to_char(bdate, 'YYYY-MM-DD'), '') AS bdate
+		FROM %[1]s
+		WHERE deleted_at IS NULL
+		ORDER BY id`, cconstant.PersonDB),
+	fmt.Sprintf(`
+		SELECT 'film' AS kind, id, COALESCE(external_id, '') AS external_id,
+		       film_name AS name, to_char(release_date, 'YYYY-MM-DD') AS rdate, COALESCE(rating, 0) AS rating,
+		       COALESCE(description, '') AS "desc"
+		FROM %[1]s
+		WHERE deleted_at IS NULL
+		ORDER BY id`, cconstant.FilmDB),
+	fmt.Sprintf(`
+		SELECT 'relation' AS kind, af.actor_id, af.film_id, a.person_name AS actor, f.film_name AS film,
+		       COALESCE(af.character_name, '') AS "character", af.credit_type AS "type",
+		       COALESCE(af.billing_order, 0) AS "order"
+		FROM %[1]s af
+		JOIN %[2]s a ON a.id = af.actor_id
+		JOIN %[3]s f ON f.id = af.film_id
+		WHERE a.deleted_at IS NULL AND f.deleted_at IS NULL
+		ORDER BY af.film_id, af.billing_order NULLS LAST, af.actor_id`, cconstant.ActorFilmDB, cconstant.PersonDB, cconstant.FilmDB),
+	fmt.Sprintf(`
+		SELECT 'crew' AS kind, fc.person_id AS actor_id, fc.film_id, p.person_name AS actor, f.film_name AS film, fc.job
+		FROM %[1]s fc
+		JOIN %[2]s p ON p.id = fc.person_id
+		JOIN %[3]s f ON f.id = fc.film_id
+		WHERE p.deleted_at IS NULL AND f.deleted_at IS NULL
+		ORDER BY fc.film_id, fc.job, fc.person_id`, cconstant.FilmCrewDB, cconstant.PersonDB, cconstant.FilmDB),
+	fmt.Sprintf(`
+		SELECT 'genre' AS kind, fg.film_id, f.film_name AS film, g.genre_name AS name
+		FROM %[1]s fg
+		JOIN %[2]s g ON g.id = fg.genre_id
+		JOIN %[3]s f ON f.id = fg.film_id
+		WHERE f.deleted_at IS NULL
+		ORDER BY fg.film_id, g.genre_name`, cconstant.FilmGenreDB, cconstant.GenreDB, cconstant.FilmDB),
+}
+
+type postgresRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresRepository(db *sqlx.DB) transfer.Repository {
+	return &postgresRepository{db: db}
+}
+
+func (p *postgresRepository) Begin(ctx context.Context) (transfer.Tx, error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &postgresTx{tx: tx, repo: serviceRepository.NewTxRepository(tx), ctx: ctx}, nil
+}
+
+// ----------------------------------------------------- Export ----------------------------------------------------------
+
+// Export reads every query through a cursor in a read-only repeatable read
+// transaction, so memory does not grow with the catalogue and the relations
+// match the actors and films exported before them.
+func (p *postgresRepository) Export(ctx context.Context, fn func(rec *transfer.Record) error) error {
+	tx, err := p.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range exportQueries {
+		if err = exportQuery(ctx, tx, query, fn); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func exportQuery(ctx context.Context, tx *sqlx.Tx, query string, fn func(rec *transfer.Record) error) error {
+	if _, err := tx.ExecContext(ctx, `DECLARE export_cursor NO SCROLL CURSOR FOR `+query); err != nil {
+		return err
+	}
+
+	for fetched := exportFetchSize; fetched == exportFetchSize; {
+		rows, err := tx.QueryxContext(ctx, fmt.Sprintf(`FETCH FORWARD %d FROM export_cursor`, exportFetchSize))
+		if err != nil {
+			return err
+		}
+
+		for fetched = 0; rows.Next(); fetched++ {
+			var rec transfer.Record
+			if err = rows.StructScan(&rec); err == nil {
+				err = fn(&rec)
+			}
+			if err != nil {
+				rows.Close()
+				return err
+			}
+		}
+
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, `CLOSE export_cursor`)
+
+	return err
+}
+
+// ----------------------------------------------------- Import ----------------------------------------------------------
+
+type postgresTx struct {
+	tx *sqlx.Tx
+	// repo is the service repository bound to tx, which records the changes
+	// in the audit log and the revisions like the single-entity endpoints do.
+	repo service.Repository
+	// ctx carries the origin the changes are recorded for.
+	ctx context.Context
+}
+
+func (p *postgresTx) Commit() error {
+	return p.tx.Commit()
+}
+
+func (p *postgresTx) Rollback() error {
+	return p.tx.Rollback()
+}
+
+func (p *postgresTx) Import(rec *transfer.Record) (int, error) {
+	return p.savepoint(func() (int, error) { return p.write(rec) })
+}
+
+// savepoint runs fn under a savepoint and rolls back to it when fn fails.
+func (p *postgresTx) savepoint(fn func() (int, error)) (int, error) {
+	if _, err := p.tx.Exec(`SAVEPOINT import_row`); err != nil {
+		return 0, err
+	}
+
+	id, err := fn()
+	if err != nil {
+		if _, rbErr := p.tx.Exec(`ROLLBACK TO SAVEPOINT import_row`); rbErr != nil {
+			return 0, rbErr
+		}
+		return 0, err
+	}
+
+	if _, err = p.tx.Exec(`RELEASE SAVEPOINT import_row`); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (p *postgresTx) write(rec *transfer.Record) (int, error) {
+	switch rec.Kind {
+	case "actor":
+		return p.createActor(rec)
+	case "film":
+		return p.createFilm(rec)
+	case "relation":
+		return 0, p.createRelation(rec)
+	case "crew":
+		return 0, p.createCrew(rec)
+	case "genre":
+		return 0, p.createGenre(rec)
+	}
+
+	return 0, fmt.Errorf("unknown kind %q", rec.Kind)
+}
+
+// createActor stores an empty sex or bdate as unknown, which only a synced
+// actor may have; createFilm does so with a zero rating.
+func (p *postgresTx) createActor(rec *transfer.Record) (int, error) {
+	var (
+		query = `
+		INSERT INTO %[1]s (person_name, sex, bdate, external_id)
+		VALUES ($1, NULLIF($2, ''), CAST(NULLIF($3, '') AS date), NULLIF($4, ''))
+		RETURNING id`
+
+		values = []any{rec.Name, rec.Sex, rec.BDate, rec.ExternalId}
+	)
+
+	query = fmt.Sprintf(query, cconstant.PersonDB)
+
+	var id int
+	if err := p.tx.Get(&id, query, values...); err != nil {
+		return 0, translateError(err)
+	}
+
+	return id, p.recordRow("actor", id, nil)
+}
+
+func (p *postgresTx) createFilm(rec *transfer.Record) (int, error) {
+	var (
+		query = `
+		INSERT INTO %[1]s (film_name, release_date, rating, description, external_id)
+		VALUES ($1, $2, NULLIF($3, 0), $4, NULLIF($5, ''))
+		RETURNING id`
+
+		values = []any{rec.Name, rec.RDate, rec.Rating, rec.Desc, rec.ExternalId}
+	)
+
+	query = fmt.Sprintf(query, cconstant.FilmDB)
+
+	var id int
+	if err := p.tx.Get(&id, query, values...); err != nil {
+		return 0, translateError(err)
+	}
+
+	return id, p.recordRow("film", id, nil)
+}
+
+// createRelation casts the actor in the film. Both may come earlier in the same
+// file, see recordIds.
+func (p *postgresTx) createRelation(rec *transfer.Record) error {
+	actorId, filmId, err := p.recordIds(rec)
+	if err != nil {
+		return err
+	}
+
+	var (
+		query = `
+		INSERT INTO %[1]s (actor_id, film_id, character_name, credit_type, billing_order)
+		VALUES ($1, $2, NULLIF($3, ''), COALESCE(NULLIF($4, ''), 'supporting'), NULLIF($5, 0))
+		`
+
+		values = []any{actorId, filmId, rec.Character, rec.Type, rec.Order}
+	)
+
+	query = fmt.Sprintf(query, cconstant.ActorFilmDB)
+
+	if _, err = p.tx.Exec(query, values...); err != nil {
+		return translateError(err)
+	}
+
+	after, err := service.CreditSnapshot(p.repo, actorId, filmId)
+	if err != nil {
+		return err
+	}
+
+	return service.Audit(p.ctx, p.repo, "actor_film", filmId, "create", nil, after)
+}
+
+func (p *postgresTx) createCrew(rec *transfer.Record) error {
+	personId, filmId, err := p.recordIds(rec)
+	if err != nil {
+		return err
+	}
+
+	var (
+		query = `
+		INSERT INTO %[1]s (person_id, film_id, job)
+		VALUES ($1, $2, $3)
+		`
+
+		values = []any{personId, filmId, rec.Job}
+	)
+
+	query = fmt.Sprintf(query, cconstant.FilmCrewDB)
+
+	if _, err = p.tx.Exec(query, values...); err != nil {
+		return translateError(err)
+	}
+
+	return service.Audit(p.ctx, p.repo, "film_crew", filmId, "create", nil, map[string]any{"person_id": personId, "job": rec.Job})
+}
+
+func (p *postgresTx) createGenre(rec *transfer.Record) error {
+	_, filmId, err := p.recordIds(rec)
+	if err != nil {
+		return err
+	}
+
+	return p.addGenre(filmId, rec.Name)
+}
+
+// recordIds returns the ids of the actor and the film of a relation, crew or
+// genre record: actor_id and film_id, which the usecase has turned into the
+// ids of the rows imported for them, or else the ids of the actor and the
+// film with the names, looked up inside the transaction. A genre record has
+// no actor, its actor id is 0.
+func (p *postgresTx) recordIds(rec *transfer.Record) (int, int, error) {
+	var (
+		actorId = rec.ActorId
+		filmId  = rec.FilmId
+		err     error
+	)
+
+	if actorId == 0 && rec.Kind != "genre" {
+		if actorId, err = p.idByName(cconstant.PersonDB, "person_name", "actor", rec.Actor); err != nil {
+			return 0, 0, err
+		}
+	}
+	if filmId == 0 {
+		if filmId, err = p.idByName(cconstant.FilmDB, "film_name", "film", rec.Film); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return actorId, filmId, nil
+}
+
+func (p *postgresTx) idByName(table, column, entity, name string) (int, error) {
+	var (
+		data  []int
+		query = `
+		SELECT id
+		FROM %[1]s
+		WHERE %[2]s = $1 AND deleted_at IS NULL
+		ORDER BY id
+		LIMIT 2
+		`
+
+		values = []any{name}
+	)
+
+	query = fmt.Sprintf(query, table, column)
+
+	if err := p.tx.Select(&data, query, values...); err != nil {
+		return 0, err
+	}
+
+	switch len(data) {
+	case 0:
+		return 0, fmt.Errorf("no %s %q: %w", entity, name, service.ErrNotFound)
+	case 1:
+		return data[0], nil
+	}
+
+	return 0, fmt.Errorf("%s %q: %w", entity, name, service.ErrAmbiguousName)
+}
+
+// ----------------------------------------------------- Sync ----------------------------------------------------------
+
+func (p *postgresTx) Upsert(provider string, rec *transfer.ExternalRecord) (int, error) {
+	return p.savepoint(func() (int, error) {
+		switch {
+		case rec.Person != nil:
+			return p.upsertPerson(provider+":"+rec.Person.Id, rec.Person)
+		case rec.Film != nil:
+			return p.upsertFilm(provider+":"+rec.Film.Id, rec.Film)
+		case rec.Credit != nil:
+			return 0, p.upsertCredit(provider, rec.Credit)
+		}
+
+		return 0, fmt.Errorf("empty record")
+	})
+}
+
+// upsertPerson updates the person synced under externalId before, or else the
+// only not synced person with the same name and birth date, or adds a new one.
+func (p *postgresTx) upsertPerson(externalId string, rec *transfer.ExternalPerson) (int, error) {
+	bdate, yearOnly := splitDate(rec.BDate)
+
+	id, err := p.matchRow(cconstant.PersonDB, "person_name", "bdate", externalId, rec.Name, bdate, yearOnly)
+	if err != nil {
+		return 0, err
+	}
+	before, err := service.Locked(p.repo, "actor", id, service.ActorSnapshot)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		query = `
+		INSERT INTO %[1]s (external_id, person_name, sex, bdate)
+		VALUES ($1, $2, $3, $4::date)
+		RETURNING id`
+
+		values = []any{externalId, rec.Name, nullIfEmpty(rec.Sex), bdate}
+	)
+
+	if id != 0 {
+		query = `
+		UPDATE %[1]s
+		SET external_id = $1, person_name = $2, sex = COALESCE($3, sex), bdate = ` + keepDate("bdate", "$4", "$5") + `
+		WHERE id = $6
+		RETURNING id`
+
+		values = append(values, yearOnly, id)
+	}
+
+	query = fmt.Sprintf(query, cconstant.PersonDB)
+
+	if err = p.tx.Get(&id, query, values...); err != nil {
+		return 0, translateError(err)
+	}
+
+	return id, p.recordRow("actor", id, before)
+}
+
+// upsertFilm finds the film like upsertPerson and adds the genres it is missing.
+func (p *postgresTx) upsertFilm(externalId string, rec *transfer.ExternalFilm) (int, error) {
+	rdate, yearOnly := splitDate(rec.RDate)
+
+	id, err := p.matchRow(cconstant.FilmDB, "film_name", "release_date", externalId, rec.Name, rdate, yearOnly)
+	if err != nil {
+		return 0, err
+	}
+	before, err := service.Locked(p.repo, "film", id, service.FilmSnapshot)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		query = `
+		INSERT INTO %[1]s (external_id, film_name, release_date, rating, description)
+		VALUES ($1, $2, $3::date, $4, $5)
+		RETURNING id`
+
+		values = []any{externalId, rec.Name, rdate, rec.Rating, rec.Desc}
+	)
+
+	if id != 0 {
+		query = `
+		UPDATE %[1]s
+		SET external_id = $1, film_name = $2, release_date = ` + keepDate("release_date", "$3", "$6") + `,
+		    rating = COALESCE($4, rating), description = COALESCE($5, description)
+		WHERE id = $7
+		RETURNING id`
+
+		values = append(values, yearOnly, id)
+	}
+
+	query = fmt.Sprintf(query, cconstant.FilmDB)
+
+	if err = p.tx.Get(&id, query, values...); err != nil {
+		return 0, translateError(err)
+	}
+	if err = p.recordRow("film", id, before); err != nil {
+		return 0, err
+	}
+
+	for _, genre := range rec.Genres {
+		if err = p.addGenre(id, genre); err != nil {
+			return 0, err
+		}
+	}
+
+	return id, nil
+}
+
+func (p *postgresTx) addGenre(filmId int, genre string) error {
+	var (
+		query = `
+		WITH g AS (
+			INSERT INTO %[1]s (genre_name)
+			VALUES ($2)
+			ON CONFLICT (genre_name) DO UPDATE SET genre_name = excluded.genre_name
+			RETURNING id
+		)
+		INSERT INTO %[2]s (film_id, genre_id)
+		SELECT $1, g.id FROM g
+		ON CONFLICT (film_id, genre_id) DO NOTHING
+		RETURNING genre_id
+		`
+
+		added  []int
+		values = []any{filmId, genre}
+	)
+
+	query = fmt.Sprintf(query, cconstant.GenreDB, cconstant.FilmGenreDB)
+
+	if err := p.tx.Select(&added, query, values...); err != nil {
+		return translateError(err)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	return service.Audit(p.ctx, p.repo, "film_genre", filmId, "create", nil, map[string]any{"genre_id": added[0]})
+}
+
+// upsertCredit adds an acting part or a crew job. The character and billing
+// order of a part are updated when the provider knows them.
+func (p *postgresTx) upsertCredit(provider string, rec *transfer.ExternalCredit) error {
+	personId, err := p.idByExternalId(cconstant.PersonDB, "person", provider+":"+rec.PersonId)
+	if err != nil {
+		return err
+	}
+	filmId, err := p.idByExternalId(cconstant.FilmDB, "film", provider+":"+rec.FilmId)
+	if err != nil {
+		return err
+	}
+
+	if rec.Job != "" {
+		return p.addCrew(personId, filmId, rec.Job)
+	}
+
+	// A credit is locked with its film, under which it is filed.
+	if err = p.repo.Lock("film", filmId); err != nil {
+		return err
+	}
+	before, err := service.CreditSnapshot(p.repo, personId, filmId)
+	if err != nil {
+		return err
+	}
+
+	var (
+		query = `
+		INSERT INTO %[1]s AS af (actor_id, film_id, character_name, billing_order)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (actor_id, film_id) DO UPDATE
+		SET character_name = COALESCE(excluded.character_name, af.character_name),
+		    billing_order  = COALESCE(excluded.billing_order, af.billing_order)
+		`
+
+		values = []any{personId, filmId, nullIfEmpty(rec.Character), nullIfZero(rec.Order)}
+	)
+
+	query = fmt.Sprintf(query, cconstant.ActorFilmDB)
+
+	if _, err = p.tx.Exec(query, values...); err != nil {
+		return translateError(err)
+	}
+
+	after, err := service.CreditSnapshot(p.repo, personId, filmId)
+	if err != nil {
+		return err
+	}
+
+	return p.recordChange("actor_film", filmId, before, after, "actor_id")
+}
+
+// addCrew adds the crew job unless the person has it already.
+func (p *postgresTx) addCrew(personId, filmId int, job string) error {
+	var (
+		query = `
+		INSERT INTO %[1]s (person_id, film_id, job)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (film_id, person_id, job) DO NOTHING
+		`
+
+		values = []any{personId, filmId, job}
+	)
+
+	query = fmt.Sprintf(query, cconstant.FilmCrewDB)
+
+	res, err := p.tx.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil
+	}
+
+	return service.Audit(p.ctx, p.repo, "film_crew", filmId, "create", nil, map[string]any{"person_id": personId, "job": job})
+}
+
+// matchRow returns the id of the row synced under externalId or, failing that,
+// of the only not synced row with the same name and date (year for a yearOnly date).
+// It returns 0 when there is no such row. A deleted row synced before is
+// still matched, so it is updated but stays in the trash.
+func (p *postgresTx) matchRow(table, nameColumn, dateColumn, externalId, name string, date any, yearOnly bool) (int, error) {
+	if id, err := p.idByExternalId(table, "", externalId); !errors.Is(err, service.ErrNotFound) {
+		return id, err
+	}
+	if date == nil {
+		return 0, nil
+	}
+
+	var (
+		data  []int
+		query = `
+		SELECT id
+		FROM %[1]s
+		WHERE external_id IS NULL AND deleted_at IS NULL AND %[2]s = $1
+		  AND CASE WHEN $3 THEN date_part('year', %[3]s) = date_part('year', $2::date) ELSE %[3]s = $2::date END
+		LIMIT 2
+		`
+
+		values = []any{name, date, yearOnly}
+	)
+
+	query = fmt.Sprintf(query, table, nameColumn, dateColumn)
+
+	if err := p.tx.Select(&data, query, values...); err != nil {
+		return 0, err
+	}
+
+	// A name matching several rows is not adopted, the provider gets a row of its own.
+	if len(data) != 1 {
+		return 0, nil
+	}
+
+	return data[0], nil
+}
+
+func (p *postgresTx) idByExternalId(table, entity, externalId string) (int, error) {
+	var (
+		data  []int
+		query = `SELECT id FROM %[1]s WHERE external_id = $1`
+
+		values = []any{externalId}
+	)
+
+	query = fmt.Sprintf(query, table)
+
+	if err := p.tx.Select(&data, query, values...); err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, fmt.Errorf("no %s %s: %w", entity, externalId, service.ErrNotFound)
+	}
+
+	return data[0], nil
+}
+
+// keepDate keeps the stored date when the new one is unknown or, being a bare
+// year (yearOnly), falls in the same year as the stored one.
+func keepDate(column, date, yearOnly string) string {
+	return fmt.Sprintf(`CASE WHEN %[2]s::date IS NULL OR (%[3]s AND date_part('year', %[1]s) = date_part('year', %[2]s::date))
+		THEN %[1]s ELSE %[2]s::date END`, column, date, yearOnly)
+}
+
+// splitDate turns "2000-01-01" or a bare year "2000" into the date to store,
+// nil when it is empty.
+func splitDate(date string) (any, bool) {
+	switch len(date) {
+	case 0:
+		return nil, false
+	case 4:
+		return date + "-01-01", true
+	}
+
+	return date, false
+}
+
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullIfZero(n int) any {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+// ----------------------------------------------------- Audit ----------------------------------------------------------
+
+// recordChange records a create when there was nothing before and an update
+// otherwise. A row the record leaves as it was is not recorded, so syncing
+// the same data again leaves no trace, and neither is a row in the trash,
+// which the snapshots do not read.
+func (p *postgresTx) recordChange(entity string, entityId int, before, after map[string]any, keys ...string) error {
+	switch {
+	case after == nil || reflect.DeepEqual(before, after):
+		return nil
+	case before == nil:
+		return service.Audit(p.ctx, p.repo, entity, entityId, "create", nil, after, keys...)
+	}
+
+	return service.Audit(p.ctx, p.repo, entity, entityId, "update", before, after, keys...)
+}
+
+// recordRow records the change of an actor or a film that had the snapshot
+// before, nil for a new one, in the audit log and as its next revision.
+func (p *postgresTx) recordRow(entity string, id int, before map[string]any) error {
+	snapshot := service.ActorSnapshot
+	if entity == "film" {
+		snapshot = service.FilmSnapshot
+	}
+
+	after, err := snapshot(p.repo, id)
+	if err != nil {
+		return err
+	}
+
+	if err = p.recordChange(entity, id, before, after); err != nil {
+		return err
+	}
+
+	_, err = service.Revise(p.ctx, p.repo, entity, id, before, after)
+	return err
+}
+
+// ----------------------------------------------------- Errors ----------------------------------------------------------
+
+// translateError maps constraint violations onto the service errors.
+func translateError(err error) error {
+	var pgErr pgx.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case uniqueViolation:
+		return fmt.Errorf("%s: %w", pgErr.Detail, service.ErrAlreadyExists)
+	case foreignKeyViolation:
+		return fmt.Errorf("%s: %w", pgErr.Detail, service.ErrNotFound)
+	case notNullViolation:
+		// A synced row leaves what the provider does not know empty; a new row needs at least a release date.
+		return fmt.Errorf("%s is unknown", pgErr.ColumnName)
+	}
+
+	return err
+}
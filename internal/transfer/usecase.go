@@ -0,0 +1,12 @@
+package transfer
+
+import (
+	"context"
+	"io"
+)
+
+type Usecase interface {
+	Import(ctx context.Context, r io.Reader, params *ImportParams) (*Report, error)
+	Export(ctx context.Context, w io.Writer, format string) error
+	Sync(ctx context.Context, provider MetadataProvider, params *ImportParams) (*Report, error)
+}
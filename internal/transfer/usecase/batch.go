@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"film_library/internal/cconstant"
+	"film_library/internal/transfer"
+	"strings"
+)
+
+// batch writes records in transactions of params.BatchSize records and keeps
+// the report. A dry run writes everything in one transaction and rolls it
+// back, so records may still refer to the ones before them.
+type batch struct {
+	ctx     context.Context
+	repo    transfer.Repository
+	size    int
+	dryRun  bool
+	tx      transfer.Tx
+	pending int
+	report  *transfer.Report
+}
+
+func newBatch(ctx context.Context, repo transfer.Repository, params *transfer.ImportParams) *batch {
+	size := params.BatchSize
+	if size <= 0 {
+		size = cconstant.DefaultImportBatch
+	}
+
+	return &batch{
+		ctx:    ctx,
+		repo:   repo,
+		size:   size,
+		dryRun: params.DryRun,
+		report: &transfer.Report{DryRun: params.DryRun, Errors: []transfer.RowError{}},
+	}
+}
+
+// write runs fn for one record in the open transaction. A record fn fails on
+// is reported as failed; only an error of the transaction itself is returned.
+func (b *batch) write(failed transfer.RowError, fn func(tx transfer.Tx) error) error {
+	if b.tx == nil {
+		tx, err := b.repo.Begin(b.ctx)
+		if err != nil {
+			return err
+		}
+		b.tx = tx
+	}
+
+	if err := fn(b.tx); err != nil {
+		b.fail(failed, err)
+		return nil
+	}
+
+	b.report.Total++
+	b.report.Imported++
+	if b.pending++; b.pending == b.size && !b.dryRun {
+		return b.commit()
+	}
+
+	return nil
+}
+
+// fail reports a record that was not written.
+func (b *batch) fail(failed transfer.RowError, err error) {
+	failed.Error = strings.TrimSuffix(err.Error(), ": "+transfer.ErrBadRow.Error())
+
+	b.report.Total++
+	b.report.Failed++
+	b.report.Errors = append(b.report.Errors, failed)
+}
+
+// finish commits the last transaction, unless it is a dry run.
+func (b *batch) finish() error {
+	if b.tx == nil || b.dryRun {
+		return nil
+	}
+
+	return b.commit()
+}
+
+// close rolls back the transaction that is still open, if any.
+func (b *batch) close() {
+	if b.tx != nil {
+		b.tx.Rollback()
+		b.tx = nil
+	}
+}
+
+func (b *batch) commit() error {
+	err := b.tx.Commit()
+	b.tx, b.pending = nil, 0
+
+	return err
+}
@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"film_library/internal/cconstant"
+	"film_library/internal/transfer"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxLineSize bounds one line of a JSON Lines file.
+const maxLineSize = 1 << 20
+
+// decoder reads the records of an import file one by one. Next returns the
+// record with its row, io.EOF after the last one and an error wrapping
+// transfer.ErrBadRow for a record that cannot be read. Any other error means
+// the rest of the file cannot be read either.
+type decoder interface {
+	Next() (transfer.Record, int, error)
+}
+
+func newDecoder(r io.Reader, format string) (decoder, error) {
+	switch format {
+	case "csv":
+		return newCSVDecoder(r)
+	case "json":
+		return newJSONDecoder(r)
+	case "jsonl":
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, maxLineSize)
+		return &jsonlDecoder{scanner: scanner}, nil
+	case "zip":
+		return newZipDecoder(r)
+	}
+
+	return nil, fmt.Errorf("format should be one of %s", strings.Join(cconstant.ImportFormats, ", "))
+}
+
+// ----------------------------------------------------- CSV ----------------------------------------------------------
+
+// csvColumns sets the record field named like its JSON key from a CSV cell.
+var csvColumns = map[string]func(rec *transfer.Record, value string) error{
+	"kind":        func(rec *transfer.Record, v string) error { rec.Kind = v; return nil },
+	"id":          intColumn("id", func(rec *transfer.Record) *int { return &rec.Id }),
+	"external_id": func(rec *transfer.Record, v string) error { rec.ExternalId = v; return nil },
+	"name":        func(rec *transfer.Record, v string) error { rec.Name = v; return nil },
+	"sex":         func(rec *transfer.Record, v string) error { rec.Sex = v; return nil },
+	"bdate":       func(rec *transfer.Record, v string) error { rec.BDate = v; return nil },
+	"rdate":       func(rec *transfer.Record, v string) error { rec.RDate = v; return nil },
+	"rating": func(rec *transfer.Record, v string) error {
+		if v == "" {
+			return nil
+		}
+		rating, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return fmt.Errorf("rating should be a number")
+		}
+		rec.Rating = float32(rating)
+		return nil
+	},
+	"desc":      func(rec *transfer.Record, v string) error { rec.Desc = v; return nil },
+	"actor":     func(rec *transfer.Record, v string) error { rec.Actor = v; return nil },
+	"film":      func(rec *transfer.Record, v string) error { rec.Film = v; return nil },
+	"character": func(rec *transfer.Record, v string) error { rec.Character = v; return nil },
+	"type":      func(rec *transfer.Record, v string) error { rec.Type = v; return nil },
+	"actor_id":  intColumn("actor_id", func(rec *transfer.Record) *int { return &rec.ActorId }),
+	"film_id":   intColumn("film_id", func(rec *transfer.Record) *int { return &rec.FilmId }),
+	"order":     intColumn("order", func(rec *transfer.Record) *int { return &rec.Order }),
+	"job":       func(rec *transfer.Record, v string) error { rec.Job = v; return nil },
+}
+
+// intColumn sets the number field of a record from a cell; an empty cell leaves it 0.
+func intColumn(name string, field func(rec *transfer.Record) *int) func(rec *transfer.Record, value string) error {
+	return func(rec *transfer.Record, v string) error {
+		if v == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%s should be a whole number", name)
+		}
+		*field(rec) = n
+		return nil
+	}
+}
+
+type csvDecoder struct {
+	reader *csv.Reader
+	header []string
+}
+
+// newCSVDecoder reads the header row, which names the columns like the JSON keys of transfer.Record.
+func newCSVDecoder(r io.Reader) (*csvDecoder, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("csv should start with a header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+		if _, ok := csvColumns[header[i]]; !ok {
+			return nil, fmt.Errorf("unknown csv column %q", column)
+		}
+	}
+
+	return &csvDecoder{reader: reader, header: header}, nil
+}
+
+func (d *csvDecoder) Next() (transfer.Record, int, error) {
+	var rec transfer.Record
+
+	cells, err := d.reader.Read()
+	if err == io.EOF {
+		return rec, 0, err
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return rec, parseErr.StartLine, fmt.Errorf("%s: %w", parseErr.Err, transfer.ErrBadRow)
+	}
+	if err != nil {
+		return rec, 0, err
+	}
+
+	row, _ := d.reader.FieldPos(0)
+	for i, cell := range cells {
+		if err = csvColumns[d.header[i]](&rec, cell); err != nil {
+			return rec, row, fmt.Errorf("%s: %w", err, transfer.ErrBadRow)
+		}
+	}
+
+	return rec, row, nil
+}
+
+// ----------------------------------------------------- Zip ----------------------------------------------------------
+
+// zipDecoder reads the CSV files of a csv export, e.g. actors.csv, one after
+// another in the order of cconstant.RecordKinds, so the relations, crew and
+// genres come after the actors and films they refer to. A kind may be missing.
+type zipDecoder struct {
+	files []*zip.File
+	file  io.Closer
+	csv   *csvDecoder
+}
+
+// newZipDecoder reads the whole archive into memory, a zip cannot be read from a stream.
+func newZipDecoder(r io.Reader) (*zipDecoder, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	d := &zipDecoder{}
+	for _, kind := range cconstant.RecordKinds {
+		if f, ok := files[kind+"s.csv"]; ok {
+			d.files = append(d.files, f)
+			delete(files, f.Name)
+		}
+	}
+	for name := range files {
+		return nil, fmt.Errorf("unknown file %q in zip", name)
+	}
+
+	return d, nil
+}
+
+// Next reads the next record of the current file, or of the next file once
+// it is done. Rows are counted in every file on its own.
+func (d *zipDecoder) Next() (transfer.Record, int, error) {
+	for {
+		if d.csv == nil {
+			if len(d.files) == 0 {
+				return transfer.Record{}, 0, io.EOF
+			}
+			if err := d.open(d.files[0]); err != nil {
+				return transfer.Record{}, 0, fmt.Errorf("%s: %w", d.files[0].Name, err)
+			}
+		}
+
+		rec, row, err := d.csv.Next()
+		if err != io.EOF {
+			if err != nil && !errors.Is(err, transfer.ErrBadRow) {
+				err = fmt.Errorf("%s: %w", d.files[0].Name, err)
+			}
+			return rec, row, err
+		}
+
+		d.file.Close()
+		d.files, d.file, d.csv = d.files[1:], nil, nil
+	}
+}
+
+func (d *zipDecoder) open(f *zip.File) error {
+	file, err := f.Open()
+	if err != nil {
+		return err
+	}
+
+	dec, err := newCSVDecoder(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	d.file, d.csv = file, dec
+	return nil
+}
+
+// ----------------------------------------------------- JSON ----------------------------------------------------------
+
+type jsonDecoder struct {
+	dec *json.Decoder
+	row int
+}
+
+func newJSONDecoder(r io.Reader) (*jsonDecoder, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	if token, err := dec.Token(); err != nil || token != json.Delim('[') {
+		return nil, fmt.Errorf("json should be an array of records")
+	}
+
+	return &jsonDecoder{dec: dec}, nil
+}
+
+// Next reads the next array element. A value of the wrong type or an unknown
+// key only spoils its own record, broken JSON stops the import.
+func (d *jsonDecoder) Next() (transfer.Record, int, error) {
+	var rec transfer.Record
+
+	if !d.dec.More() {
+		if _, err := d.dec.Token(); err != nil {
+			return rec, 0, err
+		}
+		return rec, 0, io.EOF
+	}
+
+	d.row++
+	err := d.dec.Decode(&rec)
+
+	var syntaxErr *json.SyntaxError
+	if err == nil || errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return rec, d.row, err
+	}
+
+	return rec, d.row, fmt.Errorf("%s: %w", err, transfer.ErrBadRow)
+}
+
+type jsonlDecoder struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+// Next reads the next non-empty line; every line is a record on its own.
+func (d *jsonlDecoder) Next() (transfer.Record, int, error) {
+	var rec transfer.Record
+
+	for d.scanner.Scan() {
+		d.row++
+
+		line := strings.TrimSpace(d.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		dec := json.NewDecoder(strings.NewReader(line))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&rec); err != nil {
+			return rec, d.row, fmt.Errorf("%s: %w", err, transfer.ErrBadRow)
+		}
+
+		return rec, d.row, nil
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return rec, d.row + 1, err
+	}
+
+	return rec, 0, io.EOF
+}
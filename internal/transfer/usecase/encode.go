@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"film_library/internal/cconstant"
+	"film_library/internal/transfer"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// encoder writes exported records one by one; Close finishes the document.
+type encoder interface {
+	Encode(rec *transfer.Record) error
+	Close() error
+}
+
+func newEncoder(w io.Writer, format string) (encoder, error) {
+	switch format {
+	case "jsonl":
+		return &jsonlEncoder{enc: json.NewEncoder(w)}, nil
+	case "json":
+		return &jsonEncoder{w: w}, nil
+	case "csv":
+		return &csvZipEncoder{zw: zip.NewWriter(w)}, nil
+	}
+
+	return nil, fmt.Errorf("format should be one of %s", strings.Join(cconstant.ExportFormats, ", "))
+}
+
+type jsonlEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlEncoder) Encode(rec *transfer.Record) error {
+	return e.enc.Encode(rec)
+}
+
+func (e *jsonlEncoder) Close() error {
+	return nil
+}
+
+// jsonEncoder writes a JSON array, one record per line.
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) Encode(rec *transfer.Record) error {
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n"
+	if e.count == 0 {
+		sep = "[\n"
+	}
+	e.count++
+
+	_, err = io.WriteString(e.w, sep+string(raw))
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+// csvColumnsByKind are the columns of the file of every record kind. Each file
+// keeps the kind column, so it can be imported on its own.
+var csvColumnsByKind = map[string][]string{
+	"actor":    {"kind", "id", "external_id", "name", "sex", "bdate"},
+	"film":     {"kind", "id", "external_id", "name", "rdate", "rating", "desc"},
+	"relation": {"kind", "actor_id", "film_id", "actor", "film", "character", "type", "order"},
+	"crew":     {"kind", "actor_id", "film_id", "actor", "film", "job"},
+	"genre":    {"kind", "film_id", "film", "name"},
+}
+
+// csvZipEncoder writes a zip with an actors.csv, films.csv, relations.csv,
+// crews.csv and genres.csv.
+// Records come grouped by kind, so each file is written in one go.
+type csvZipEncoder struct {
+	zw   *zip.Writer
+	cw   *csv.Writer
+	kind string
+	done int
+}
+
+func (e *csvZipEncoder) Encode(rec *transfer.Record) error {
+	for rec.Kind != e.kind {
+		if err := e.next(); err != nil {
+			return err
+		}
+	}
+
+	return e.cw.Write(csvRow(rec))
+}
+
+func (e *csvZipEncoder) Close() error {
+	for e.done < len(cconstant.RecordKinds) {
+		if err := e.next(); err != nil {
+			return err
+		}
+	}
+	if err := e.flush(); err != nil {
+		return err
+	}
+
+	return e.zw.Close()
+}
+
+// next finishes the file of the current kind and starts the file of the next one.
+func (e *csvZipEncoder) next() error {
+	if e.done == len(cconstant.RecordKinds) {
+		return fmt.Errorf("records should come grouped by kind in the order %s", strings.Join(cconstant.RecordKinds, ", "))
+	}
+	if err := e.flush(); err != nil {
+		return err
+	}
+
+	e.kind = cconstant.RecordKinds[e.done]
+	e.done++
+
+	w, err := e.zw.Create(e.kind + "s.csv")
+	if err != nil {
+		return err
+	}
+	e.cw = csv.NewWriter(w)
+
+	return e.cw.Write(csvColumnsByKind[e.kind])
+}
+
+func (e *csvZipEncoder) flush() error {
+	if e.cw == nil {
+		return nil
+	}
+	e.cw.Flush()
+
+	return e.cw.Error()
+}
+
+func csvRow(rec *transfer.Record) []string {
+	switch rec.Kind {
+	case "actor":
+		return []string{rec.Kind, csvInt(rec.Id), rec.ExternalId, rec.Name, rec.Sex, rec.BDate}
+	case "film":
+		return []string{rec.Kind, csvInt(rec.Id), rec.ExternalId, rec.Name, rec.RDate,
+			strconv.FormatFloat(float64(rec.Rating), 'f', -1, 32), rec.Desc}
+	case "crew":
+		return []string{rec.Kind, csvInt(rec.ActorId), csvInt(rec.FilmId), rec.Actor, rec.Film, rec.Job}
+	case "genre":
+		return []string{rec.Kind, csvInt(rec.FilmId), rec.Film, rec.Name}
+	}
+
+	return []string{rec.Kind, csvInt(rec.ActorId), csvInt(rec.FilmId), rec.Actor, rec.Film, rec.Character, rec.Type, csvInt(rec.Order)}
+}
+
+// csvInt leaves a zero number out, as the JSON formats do.
+func csvInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,336 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"film_library/internal/cconstant"
+	"film_library/internal/service"
+	"film_library/internal/transfer"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type TransferUsecase struct {
+	repo transfer.Repository
+}
+
+func NewTransferUsecase(repo transfer.Repository) transfer.Usecase {
+	return &TransferUsecase{repo: repo}
+}
+
+// Import reads the records of r and writes them in transactions of
+// params.BatchSize records on behalf of the origin in ctx. A record that cannot be read, is invalid or is
+// refused by the database is reported and skipped. The ids of actor_id and
+// film_id are resolved against the records before them, in every file of a zip.
+//
+// An error wrapping transfer.ErrBadFile is returned when the rest of the file
+// cannot be read; the batches committed before that stay imported.
+func (u *TransferUsecase) Import(ctx context.Context, r io.Reader, params *transfer.ImportParams) (*transfer.Report, error) {
+	dec, err := newDecoder(r, params.Format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", err, transfer.ErrBadFile)
+	}
+
+	b := newBatch(ctx, u.repo, params)
+	defer b.close()
+
+	ids := newFileIds()
+	for {
+		rec, row, err := dec.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil && !errors.Is(err, transfer.ErrBadRow) {
+			return nil, fmt.Errorf("row %d: %w: %w", row, err, transfer.ErrBadFile)
+		}
+
+		failed := rowError(row, &rec)
+		if err == nil {
+			err = validateRecord(&rec)
+		}
+		if err == nil {
+			err = ids.resolve(&rec)
+		}
+		if err != nil {
+			b.fail(failed, err)
+			continue
+		}
+
+		if err = b.write(failed, func(tx transfer.Tx) error {
+			id, err := tx.Import(&rec)
+			if err == nil {
+				ids.add(&rec, id)
+			}
+			return err
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = b.finish(); err != nil {
+		return nil, err
+	}
+
+	return b.report, nil
+}
+
+// Export writes the whole catalogue to w as it is read from the database:
+// JSON Lines, a JSON array or a zip of one CSV file per record kind. Every
+// format can be imported again, the zip as format "zip".
+func (u *TransferUsecase) Export(ctx context.Context, w io.Writer, format string) error {
+	enc, err := newEncoder(w, format)
+	if err != nil {
+		return err
+	}
+
+	if err = u.repo.Export(ctx, enc.Encode); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// Sync writes the records of the provider like Import does, updating the films
+// and people synced from it before. Only an error of the provider or of the
+// database is returned; the batches committed before it stay.
+func (u *TransferUsecase) Sync(ctx context.Context, provider transfer.MetadataProvider, params *transfer.ImportParams) (*transfer.Report, error) {
+	b := newBatch(ctx, u.repo, params)
+	defer b.close()
+
+	var row int
+	err := provider.Fetch(ctx, func(rec *transfer.ExternalRecord) error {
+		row++
+
+		failed := externalRowError(row, rec)
+		if err := validateExternal(rec); err != nil {
+			b.fail(failed, err)
+			return nil
+		}
+
+		return b.write(failed, func(tx transfer.Tx) error {
+			_, err := tx.Upsert(provider.Name(), rec)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = b.finish(); err != nil {
+		return nil, err
+	}
+
+	return b.report, nil
+}
+
+// validateRecord checks a record with the rules of the single-entity endpoints.
+func validateRecord(rec *transfer.Record) error {
+	switch rec.Kind {
+	case "actor":
+		actor := service.Actor{Name: rec.Name, Sex: rec.Sex, BDate: rec.BDate}
+		// A synced actor is exported without the values its source did not know,
+		// which stay unknown; valid stand-ins let the rest be checked.
+		if rec.ExternalId != "" {
+			actor.Sex = cmp.Or(actor.Sex, "m")
+			actor.BDate = cmp.Or(actor.BDate, "2000-01-01")
+		}
+		if err := service.ValidateActor(&actor); err != nil {
+			return err
+		}
+		rec.Name = actor.Name
+	case "film":
+		film := service.Film{Name: rec.Name, RDate: rec.RDate, Rating: rec.Rating, Desc: rec.Desc}
+		if rec.ExternalId != "" { // like a synced actor
+			film.Rating = cmp.Or(film.Rating, 10)
+		}
+		if err := service.ValidateFilm(&film); err != nil {
+			return err
+		}
+		rec.Name = film.Name
+	case "relation", "crew":
+		rec.Actor, rec.Film = service.NormalizeName(rec.Actor), service.NormalizeName(rec.Film)
+		if (rec.Actor == "" && rec.ActorId == 0) || (rec.Film == "" && rec.FilmId == 0) {
+			return fmt.Errorf("actor and film should be set")
+		}
+		if rec.Kind == "crew" {
+			if !slices.Contains(cconstant.CrewJobs, rec.Job) {
+				return fmt.Errorf("job should be one of %s", strings.Join(cconstant.CrewJobs, ", "))
+			}
+			break
+		}
+		rec.Character = service.NormalizeName(rec.Character)
+		if err := service.ValidName(rec.Character, 150); rec.Character != "" && err != nil {
+			return fmt.Errorf("character: %w", err)
+		}
+		if rec.Type != "" && !slices.Contains(cconstant.CreditTypes, rec.Type) {
+			return fmt.Errorf("type should be one of %s", strings.Join(cconstant.CreditTypes, ", "))
+		}
+		if rec.Order < 0 {
+			return fmt.Errorf("order should not be negative")
+		}
+	case "genre":
+		rec.Film = service.NormalizeName(rec.Film)
+		if rec.Film == "" && rec.FilmId == 0 {
+			return fmt.Errorf("film should be set")
+		}
+		rec.Name = service.NormalizeName(rec.Name)
+		if err := service.ValidName(rec.Name, 50); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("kind should be one of %s", strings.Join(cconstant.RecordKinds, ", "))
+	}
+
+	return nil
+}
+
+// validateExternal checks a record of a provider like validateRecord, except
+// that what the provider does not know may be left empty.
+func validateExternal(rec *transfer.ExternalRecord) error {
+	switch {
+	case rec.Person != nil && rec.Film == nil && rec.Credit == nil:
+		p := rec.Person
+		if p.Id == "" {
+			return fmt.Errorf("id should be set")
+		}
+		p.Name = service.NormalizeName(p.Name)
+		if err := service.ValidName(p.Name, 100); err != nil {
+			return err
+		}
+		if p.Sex != "" && p.Sex != "f" && p.Sex != "m" {
+			return fmt.Errorf("sex should be 'm' - male or 'f' - famale")
+		}
+		if p.BDate != "" && !validExternalDate(p.BDate) {
+			return fmt.Errorf("bdate should be '2000-01-01' or '2000' format")
+		}
+	case rec.Film != nil && rec.Person == nil && rec.Credit == nil:
+		f := rec.Film
+		if f.Id == "" {
+			return fmt.Errorf("id should be set")
+		}
+		f.Name = service.NormalizeName(f.Name)
+		if err := service.ValidName(f.Name, 150); err != nil {
+			return err
+		}
+		if !validExternalDate(f.RDate) {
+			return fmt.Errorf("rdate should be '2000-01-01' or '2000' format")
+		}
+		if f.Rating != nil && (*f.Rating <= 0 || *f.Rating > 10) {
+			return fmt.Errorf("rating should be (0;10]")
+		}
+		if f.Desc != nil && utf8.RuneCountInString(*f.Desc) > 1000 {
+			return fmt.Errorf("size Name should be < 1000 symbols")
+		}
+		for i := range f.Genres {
+			f.Genres[i] = service.NormalizeName(f.Genres[i])
+			if err := service.ValidName(f.Genres[i], 50); err != nil {
+				return fmt.Errorf("genre: %w", err)
+			}
+		}
+	case rec.Credit != nil && rec.Person == nil && rec.Film == nil:
+		c := rec.Credit
+		if c.FilmId == "" || c.PersonId == "" {
+			return fmt.Errorf("film_id and person_id should be set")
+		}
+		if c.Job != "" && !slices.Contains(cconstant.CrewJobs, c.Job) {
+			return fmt.Errorf("job should be one of %s", strings.Join(cconstant.CrewJobs, ", "))
+		}
+		c.Character = service.NormalizeName(c.Character)
+		if err := service.ValidName(c.Character, 150); c.Character != "" && err != nil {
+			return fmt.Errorf("character: %w", err)
+		}
+		if c.Order < 0 {
+			return fmt.Errorf("order should not be negative")
+		}
+	default:
+		return fmt.Errorf("record should hold one of person, film, credit")
+	}
+
+	return nil
+}
+
+// validExternalDate also accepts a bare year, which providers often know only.
+func validExternalDate(date string) bool {
+	if len(date) == 4 {
+		_, err := strconv.Atoi(date)
+		return err == nil
+	}
+
+	return service.ValidDate(date)
+}
+
+func externalRowError(row int, rec *transfer.ExternalRecord) transfer.RowError {
+	switch {
+	case rec.Person != nil:
+		return transfer.RowError{Row: row, Kind: "person", Name: rec.Person.Id + " " + rec.Person.Name}
+	case rec.Film != nil:
+		return transfer.RowError{Row: row, Kind: "film", Name: rec.Film.Id + " " + rec.Film.Name}
+	case rec.Credit != nil:
+		return transfer.RowError{Row: row, Kind: "credit", Name: rec.Credit.PersonId + " / " + rec.Credit.FilmId}
+	}
+
+	return transfer.RowError{Row: row}
+}
+
+func rowError(row int, rec *transfer.Record) transfer.RowError {
+	name := rec.Name
+	switch rec.Kind {
+	case "relation", "crew":
+		name = recordRef(rec.Actor, rec.ActorId) + " / " + recordRef(rec.Film, rec.FilmId)
+	case "genre":
+		name = rec.Name + " / " + recordRef(rec.Film, rec.FilmId)
+	}
+
+	return transfer.RowError{Row: row, Kind: rec.Kind, Name: name}
+}
+
+// recordRef names the actor or film a record refers to, by its id in the file
+// when the name is not given.
+func recordRef(name string, id int) string {
+	if name == "" && id != 0 {
+		return "#" + strconv.Itoa(id)
+	}
+
+	return name
+}
+
+// fileIds maps the ids actors and films have in an import file onto the ids
+// of the rows imported for them.
+type fileIds map[string]map[int]int
+
+func newFileIds() fileIds {
+	return fileIds{"actor": {}, "film": {}}
+}
+
+// add remembers the row imported for an actor or a film with an id.
+func (f fileIds) add(rec *transfer.Record, id int) {
+	if ids, ok := f[rec.Kind]; ok && rec.Id != 0 {
+		ids[rec.Id] = id
+	}
+}
+
+// resolve turns actor_id and film_id of a record into the ids of the rows
+// imported for them. They have to come earlier in the file and be imported.
+func (f fileIds) resolve(rec *transfer.Record) error {
+	for _, ref := range []struct {
+		kind string
+		id   *int
+	}{{"actor", &rec.ActorId}, {"film", &rec.FilmId}} {
+		if *ref.id == 0 {
+			continue
+		}
+
+		id, ok := f[ref.kind][*ref.id]
+		if !ok {
+			return fmt.Errorf("no %s %d imported earlier in the file: %w", ref.kind, *ref.id, service.ErrNotFound)
+		}
+		*ref.id = id
+	}
+
+	return nil
+}
@@ -0,0 +1,430 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"film_library/internal/service"
+	"film_library/internal/transfer"
+	mock_transfer "film_library/internal/transfer/mocks"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestImportFormats(t *testing.T) {
+	var (
+		actor    = transfer.Record{Kind: "actor", Name: "Kenneth Branagh", Sex: "m", BDate: "1960-12-10"}
+		film     = transfer.Record{Kind: "film", Name: "Hamlet", RDate: "1996-12-25", Rating: 7.7, Desc: "Denmark"}
+		relation = transfer.Record{Kind: "relation", Actor: "Kenneth Branagh", Film: "Hamlet", Character: "Hamlet", Type: "lead"}
+	)
+
+	cases := []struct {
+		name   string
+		format string
+		in     string
+	}{
+		{
+			name:   "CSV",
+			format: "csv",
+			in: "kind,name,sex,bdate,rdate,rating,desc,actor,film,character,type\n" +
+				"actor, Kenneth Branagh ,m,1960-12-10,,,,,,,\n" +
+				"film,Hamlet,,,1996-12-25,7.7,Denmark,,,,\n" +
+				"relation,,,,,,,Kenneth Branagh,Hamlet,Hamlet,lead\n",
+		},
+		{
+			name:   "JSON",
+			format: "json",
+			in: `[{"kind":"actor","name":"Kenneth Branagh","sex":"m","bdate":"1960-12-10"},
+				{"kind":"film","name":"Hamlet","rdate":"1996-12-25","rating":7.7,"desc":"Denmark"},
+				{"kind":"relation","actor":"Kenneth Branagh","film":"Hamlet","character":"Hamlet","type":"lead"}]`,
+		},
+		{
+			name:   "JSONL",
+			format: "jsonl",
+			in: `{"kind":"actor","name":"Kenneth Branagh","sex":"m","bdate":"1960-12-10"}` + "\n\n" +
+				`{"kind":"film","name":"Hamlet","rdate":"1996-12-25","rating":7.7,"desc":"Denmark"}` + "\n" +
+				`{"kind":"relation","actor":"Kenneth Branagh","film":"Hamlet","character":"Hamlet","type":"lead"}` + "\n",
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+
+			repo := mock_transfer.NewMockRepository(ctr)
+			tx := mock_transfer.NewMockTx(ctr)
+			repo.EXPECT().Begin(gomock.Any()).Return(tx, nil).Times(1)
+			gomock.InOrder(
+				tx.EXPECT().Import(&actor).Return(1, nil),
+				tx.EXPECT().Import(&film).Return(2, nil),
+				tx.EXPECT().Import(&relation).Return(0, nil),
+				tx.EXPECT().Commit().Return(nil),
+			)
+
+			report, err := NewTransferUsecase(repo).Import(context.Background(), strings.NewReader(tCase.in), &transfer.ImportParams{Format: tCase.format})
+			require.NoError(t, err)
+			require.Equal(t, &transfer.Report{Total: 3, Imported: 3, Errors: []transfer.RowError{}}, report)
+		})
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	in := "kind,name,sex,bdate,rating\n" +
+		"actor,Kate Winslet,f,1975-10-05,\n" +
+		"actor,Kate Winslet,f,1975-10-05,\n" +
+		"actor,Nobody,x,1975-10-05,\n" +
+		"film,Titanic,,,high\n" +
+		"actor,Too,many,cells,here,!\n" +
+		"star,Leonardo,m,1974-11-11,\n"
+
+	repo := mock_transfer.NewMockRepository(ctr)
+	tx := mock_transfer.NewMockTx(ctr)
+	repo.EXPECT().Begin(gomock.Any()).Return(tx, nil).Times(1)
+	gomock.InOrder(
+		tx.EXPECT().Import(gomock.Any()).Return(1, nil),
+		tx.EXPECT().Import(gomock.Any()).Return(0, fmt.Errorf("Key (person_name, bdate)=(Kate Winslet, 1975-10-05) already exists.: %w", service.ErrAlreadyExists)),
+		tx.EXPECT().Commit().Return(nil),
+	)
+
+	report, err := NewTransferUsecase(repo).Import(context.Background(), strings.NewReader(in), &transfer.ImportParams{Format: "csv"})
+	require.NoError(t, err)
+	require.Equal(t, &transfer.Report{Total: 6, Imported: 1, Failed: 5, Errors: []transfer.RowError{
+		{Row: 3, Kind: "actor", Name: "Kate Winslet", Error: "Key (person_name, bdate)=(Kate Winslet, 1975-10-05) already exists.: already exists"},
+		{Row: 4, Kind: "actor", Name: "Nobody", Error: "sex should be 'm' - male or 'f' - famale"},
+		{Row: 5, Kind: "film", Name: "Titanic", Error: "rating should be a number"},
+		{Row: 6, Error: "wrong number of fields"},
+		{Row: 7, Kind: "star", Name: "Leonardo", Error: "kind should be one of actor, film, relation, crew, genre"},
+	}}, report)
+}
+
+func TestImportBatches(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	in := strings.Repeat(`{"kind":"film","name":"Hamlet","rdate":"1996-12-25","rating":7.7}`+"\n", 5)
+
+	repo := mock_transfer.NewMockRepository(ctr)
+	tx := mock_transfer.NewMockTx(ctr)
+	repo.EXPECT().Begin(gomock.Any()).Return(tx, nil).Times(3)
+	tx.EXPECT().Import(gomock.Any()).Return(1, nil).Times(5)
+	tx.EXPECT().Commit().Return(nil).Times(3)
+
+	report, err := NewTransferUsecase(repo).Import(context.Background(), strings.NewReader(in), &transfer.ImportParams{Format: "jsonl", BatchSize: 2})
+	require.NoError(t, err)
+	require.Equal(t, 5, report.Imported)
+}
+
+func TestImportDryRun(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	in := strings.Repeat(`{"kind":"film","name":"Hamlet","rdate":"1996-12-25","rating":7.7}`+"\n", 3)
+
+	repo := mock_transfer.NewMockRepository(ctr)
+	tx := mock_transfer.NewMockTx(ctr)
+	repo.EXPECT().Begin(gomock.Any()).Return(tx, nil).Times(1)
+	tx.EXPECT().Import(gomock.Any()).Return(1, nil).Times(3)
+	tx.EXPECT().Rollback().Return(nil).Times(1)
+
+	report, err := NewTransferUsecase(repo).Import(context.Background(), strings.NewReader(in), &transfer.ImportParams{Format: "jsonl", DryRun: true, BatchSize: 2})
+	require.NoError(t, err)
+	require.Equal(t, &transfer.Report{DryRun: true, Total: 3, Imported: 3, Errors: []transfer.RowError{}}, report)
+}
+
+func TestImportBadFile(t *testing.T) {
+	cases := []struct {
+		name   string
+		format string
+		in     string
+		expErr string
+	}{
+		{name: "Format", format: "xml", in: "<films/>", expErr: "format should be one of csv, json, jsonl, zip: bad file"},
+		{name: "NoHeader", format: "csv", in: "", expErr: "csv should start with a header row: bad file"},
+		{name: "UnknownColumn", format: "csv", in: "kind,title\n", expErr: `unknown csv column "title": bad file`},
+		{name: "NotArray", format: "json", in: `{"kind":"film"}`, expErr: "json should be an array of records: bad file"},
+		{name: "BrokenJSON", format: "json", in: `[{"kind":"film"`, expErr: "row 1: unexpected EOF: bad file"},
+		{name: "NotZip", format: "zip", in: "kind,name\n", expErr: "zip: not a valid zip file: bad file"},
+		{name: "ZipFile", format: "zip", in: zipped(t, map[string]string{"notes.txt": ""}), expErr: `unknown file "notes.txt" in zip: bad file`},
+		{name: "ZipCSV", format: "zip", in: zipped(t, map[string]string{"films.csv": "kind,title\n"}), expErr: `row 0: films.csv: unknown csv column "title": bad file`},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+
+			_, err := NewTransferUsecase(mock_transfer.NewMockRepository(ctr)).
+				Import(context.Background(), strings.NewReader(tCase.in), &transfer.ImportParams{Format: tCase.format})
+			require.ErrorIs(t, err, transfer.ErrBadFile)
+			require.EqualError(t, err, tCase.expErr)
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	records := []transfer.Record{
+		{Kind: "actor", Id: 3, ExternalId: "imdb:nm0000110", Name: "Kenneth Branagh", Sex: "m", BDate: "1960-12-10"},
+		{Kind: "actor", Id: 4, ExternalId: "imdb:nm0000116", Name: "James Cameron"},
+		{Kind: "film", Id: 7, Name: "Hamlet", RDate: "1996-12-25", Rating: 7.7, Desc: "Denmark, \"rotten\""},
+		{Kind: "relation", ActorId: 3, FilmId: 7, Actor: "Kenneth Branagh", Film: "Hamlet", Character: "Hamlet", Type: "lead", Order: 1},
+		{Kind: "crew", ActorId: 3, FilmId: 7, Actor: "Kenneth Branagh", Film: "Hamlet", Job: "director"},
+		{Kind: "genre", FilmId: 7, Film: "Hamlet", Name: "Drama"},
+	}
+	export := func(ctx context.Context, fn func(rec *transfer.Record) error) error {
+		for i := range records {
+			rec := records[i]
+			if err := fn(&rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	lines := []string{
+		`{"kind":"actor","id":3,"external_id":"imdb:nm0000110","name":"Kenneth Branagh","sex":"m","bdate":"1960-12-10"}`,
+		`{"kind":"actor","id":4,"external_id":"imdb:nm0000116","name":"James Cameron"}`,
+		`{"kind":"film","id":7,"name":"Hamlet","rdate":"1996-12-25","rating":7.7,"desc":"Denmark, \"rotten\""}`,
+		`{"kind":"relation","actor_id":3,"film_id":7,"actor":"Kenneth Branagh","film":"Hamlet","character":"Hamlet","type":"lead","order":1}`,
+		`{"kind":"crew","actor_id":3,"film_id":7,"actor":"Kenneth Branagh","film":"Hamlet","job":"director"}`,
+		`{"kind":"genre","name":"Drama","film_id":7,"film":"Hamlet"}`,
+	}
+
+	cases := []struct {
+		format string
+		out    string
+	}{
+		{format: "jsonl", out: strings.Join(lines, "\n") + "\n"},
+		{format: "json", out: "[\n" + strings.Join(lines, ",\n") + "\n]\n"},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.format, func(t *testing.T) {
+			ctr := gomock.NewController(t)
+			defer ctr.Finish()
+
+			repo := mock_transfer.NewMockRepository(ctr)
+			repo.EXPECT().Export(gomock.Any(), gomock.Any()).DoAndReturn(export).Times(1)
+
+			var out bytes.Buffer
+			require.NoError(t, NewTransferUsecase(repo).Export(context.Background(), &out, tCase.format))
+			require.Equal(t, tCase.out, out.String())
+
+			reimport(t, ctr, repo, &out, tCase.format, records)
+		})
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		defer ctr.Finish()
+
+		repo := mock_transfer.NewMockRepository(ctr)
+		repo.EXPECT().Export(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(rec *transfer.Record) error) error {
+			// No crew: its file is still there, with the header alone.
+			for i := range records {
+				if rec := records[i]; rec.Kind != "crew" {
+					if err := fn(&rec); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}).Times(1)
+
+		var out bytes.Buffer
+		require.NoError(t, NewTransferUsecase(repo).Export(context.Background(), &out, "csv"))
+
+		zr, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+		require.NoError(t, err)
+		files := map[string]string{}
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			rc.Close()
+			files[f.Name] = string(content)
+		}
+		require.Equal(t, map[string]string{
+			"actors.csv": "kind,id,external_id,name,sex,bdate\n" +
+				"actor,3,imdb:nm0000110,Kenneth Branagh,m,1960-12-10\nactor,4,imdb:nm0000116,James Cameron,,\n",
+			"films.csv":     "kind,id,external_id,name,rdate,rating,desc\nfilm,7,,Hamlet,1996-12-25,7.7,\"Denmark, \"\"rotten\"\"\"\n",
+			"relations.csv": "kind,actor_id,film_id,actor,film,character,type,order\nrelation,3,7,Kenneth Branagh,Hamlet,Hamlet,lead,1\n",
+			"crews.csv":     "kind,actor_id,film_id,actor,film,job\n",
+			"genres.csv":    "kind,film_id,film,name\ngenre,7,Hamlet,Drama\n",
+		}, files)
+
+		noCrew := slices.DeleteFunc(slices.Clone(records), func(rec transfer.Record) bool { return rec.Kind == "crew" })
+		reimport(t, ctr, repo, &out, "zip", noCrew)
+	})
+
+	t.Run("BadFormat", func(t *testing.T) {
+		ctr := gomock.NewController(t)
+		defer ctr.Finish()
+
+		err := NewTransferUsecase(mock_transfer.NewMockRepository(ctr)).Export(context.Background(), io.Discard, "xml")
+		require.EqualError(t, err, "format should be one of jsonl, csv, json")
+	})
+}
+
+// reimport imports an export of the records back and checks that every one
+// comes back as it was, in the same order, with the relations, crew and genres
+// referring to the rows imported for their actor and film.
+func reimport(t *testing.T, ctr *gomock.Controller, repo *mock_transfer.MockRepository, exported io.Reader, format string, records []transfer.Record) {
+	imported := []transfer.Record{}
+	repo.EXPECT().Begin(gomock.Any()).DoAndReturn(func(context.Context) (transfer.Tx, error) {
+		tx := mock_transfer.NewMockTx(ctr)
+		tx.EXPECT().Import(gomock.Any()).DoAndReturn(func(rec *transfer.Record) (int, error) {
+			imported = append(imported, *rec)
+			return 100 + rec.Id, nil
+		}).AnyTimes()
+		tx.EXPECT().Commit().Return(nil)
+		return tx, nil
+	}).Times(1)
+
+	report, err := NewTransferUsecase(repo).Import(context.Background(), exported, &transfer.ImportParams{Format: format})
+	require.NoError(t, err)
+	require.Empty(t, report.Errors)
+
+	var expected []transfer.Record
+	for _, rec := range records {
+		if rec.ActorId != 0 {
+			rec.ActorId += 100
+		}
+		if rec.FilmId != 0 {
+			rec.FilmId += 100
+		}
+		expected = append(expected, rec)
+	}
+	require.Equal(t, expected, imported)
+}
+
+// zipped makes a zip of the files.
+func zipped(t *testing.T, files map[string]string) string {
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	return out.String()
+}
+
+// fakeProvider hands out its records in order.
+type fakeProvider []*transfer.ExternalRecord
+
+func (f fakeProvider) Name() string {
+	return "fake"
+}
+
+func (f fakeProvider) Fetch(_ context.Context, fn func(rec *transfer.ExternalRecord) error) error {
+	for _, rec := range f {
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestSync(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	var (
+		person = &transfer.ExternalRecord{Person: &transfer.ExternalPerson{Id: "nm1", Name: " Kenneth Branagh ", Sex: "m", BDate: "1960"}}
+		film   = &transfer.ExternalRecord{Film: &transfer.ExternalFilm{Id: "tt1", Name: "Hamlet", RDate: "1996", Genres: []string{"Drama"}}}
+		credit = &transfer.ExternalRecord{Credit: &transfer.ExternalCredit{FilmId: "tt1", PersonId: "nm1", Character: "Hamlet", Order: 1}}
+		noDate = &transfer.ExternalRecord{Film: &transfer.ExternalFilm{Id: "tt2", Name: "Untitled"}}
+		gone   = &transfer.ExternalRecord{Credit: &transfer.ExternalCredit{FilmId: "tt3", PersonId: "nm1", Job: "director"}}
+		both   = &transfer.ExternalRecord{Person: person.Person, Film: film.Film}
+	)
+
+	repo := mock_transfer.NewMockRepository(ctr)
+	tx := mock_transfer.NewMockTx(ctr)
+	repo.EXPECT().Begin(gomock.Any()).Return(tx, nil).Times(1)
+	gomock.InOrder(
+		tx.EXPECT().Upsert("fake", person).Return(1, nil),
+		tx.EXPECT().Upsert("fake", film).Return(2, nil),
+		tx.EXPECT().Upsert("fake", credit).Return(0, nil),
+		tx.EXPECT().Upsert("fake", gone).Return(0, fmt.Errorf("film is not synced: %w", service.ErrNotFound)),
+		tx.EXPECT().Commit().Return(nil),
+	)
+
+	provider := fakeProvider{person, film, credit, noDate, gone, both}
+	report, err := NewTransferUsecase(repo).Sync(context.Background(), provider, &transfer.ImportParams{})
+	require.NoError(t, err)
+	require.Equal(t, "Kenneth Branagh", person.Person.Name)
+	require.Equal(t, &transfer.Report{Total: 6, Imported: 3, Failed: 3, Errors: []transfer.RowError{
+		{Row: 4, Kind: "film", Name: "tt2 Untitled", Error: "rdate should be '2000-01-01' or '2000' format"},
+		{Row: 5, Kind: "credit", Name: "nm1 / tt3", Error: "film is not synced: not found"},
+		{Row: 6, Kind: "person", Name: "nm1 Kenneth Branagh", Error: "record should hold one of person, film, credit"},
+	}}, report)
+}
+
+// A crew member with no sex or birth date and a film with no rating are synced
+// with those left unknown, and so are their credits.
+func TestSyncUnknownValues(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	var (
+		person = &transfer.ExternalRecord{Person: &transfer.ExternalPerson{Id: "nm2", Name: "James Cameron"}}
+		film   = &transfer.ExternalRecord{Film: &transfer.ExternalFilm{Id: "tt4", Name: "Titanic", RDate: "1997"}}
+		credit = &transfer.ExternalRecord{Credit: &transfer.ExternalCredit{FilmId: "tt4", PersonId: "nm2", Job: "director"}}
+	)
+
+	repo := mock_transfer.NewMockRepository(ctr)
+	tx := mock_transfer.NewMockTx(ctr)
+	repo.EXPECT().Begin(gomock.Any()).Return(tx, nil).Times(1)
+	gomock.InOrder(
+		tx.EXPECT().Upsert("fake", &transfer.ExternalRecord{Person: &transfer.ExternalPerson{Id: "nm2", Name: "James Cameron"}}).Return(1, nil),
+		tx.EXPECT().Upsert("fake", &transfer.ExternalRecord{Film: &transfer.ExternalFilm{Id: "tt4", Name: "Titanic", RDate: "1997"}}).Return(2, nil),
+		tx.EXPECT().Upsert("fake", credit).Return(0, nil),
+		tx.EXPECT().Commit().Return(nil),
+	)
+
+	report, err := NewTransferUsecase(repo).Sync(context.Background(), fakeProvider{person, film, credit}, &transfer.ImportParams{})
+	require.NoError(t, err)
+	require.Equal(t, &transfer.Report{Total: 3, Imported: 3, Errors: []transfer.RowError{}}, report)
+}
+
+func TestSyncProviderError(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_transfer.NewMockRepository(ctr)
+	tx := mock_transfer.NewMockTx(ctr)
+	repo.EXPECT().Begin(gomock.Any()).Return(tx, nil).Times(1)
+	tx.EXPECT().Upsert("fake", gomock.Any()).Return(1, nil).Times(1)
+	tx.EXPECT().Rollback().Return(nil).Times(1)
+
+	provider := fakeProvider{{Person: &transfer.ExternalPerson{Id: "nm1", Name: "Kenneth Branagh"}}}
+	_, err := NewTransferUsecase(repo).Sync(context.Background(), errProvider{provider}, &transfer.ImportParams{})
+	require.EqualError(t, err, "connection reset")
+}
+
+// errProvider fails after its records.
+type errProvider struct {
+	fakeProvider
+}
+
+func (e errProvider) Fetch(ctx context.Context, fn func(rec *transfer.ExternalRecord) error) error {
+	if err := e.fakeProvider.Fetch(ctx, fn); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("connection reset")
+}
@@ -0,0 +1,526 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"film_library/internal/auth"
+	"film_library/internal/cconstant"
+	"film_library/internal/watchlist"
+	"fmt"
+	"github.com/gorilla/mux"
+	"golang.org/x/text/unicode/norm"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var errBadRequest = errors.New("bad request")
+
+type WatchlistHandler struct {
+	watchlistUC watchlist.Usecase
+	authUC      auth.Usecase
+}
+
+func NewWatchlistHandler(watchlistUC watchlist.Usecase, authUC auth.Usecase) *WatchlistHandler {
+	return &WatchlistHandler{
+		watchlistUC: watchlistUC,
+		authUC:      authUC,
+	}
+}
+
+// @Summary      CreateWatchlist
+// @Description  Create a named film list
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        input	body	watchlist.Watchlist  true  "watchlist data, only name is used"
+// @Success      200  {object}	watchlist.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      409  {object}	error
+// @Failure      500  {object}  error
+// @Router       /watchlist/add [post]
+func (h *WatchlistHandler) CreateWatchlist(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data watchlist.Watchlist
+		resp *watchlist.ResponseModel = &watchlist.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: CreateWatchlist. User with ID:%d", tokenData.Id)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: CreateWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validateWatchlist(&data); err != nil {
+		log.Printf("Request: CreateWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.watchlistUC.CreateWatchlist(tokenData.Id, &data)
+	if err != nil {
+		log.Printf("Request: CreateWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+	resp.Id = id
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetWatchlists
+// @Description  Get your watchlists with the number of films in each
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Success      200  {array}	watchlist.Watchlist
+// @Failure      500  {object}  error
+// @Router       /watchlist/get_all [get]
+func (h *WatchlistHandler) GetWatchlists(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetWatchlists. User with ID:%d", tokenData.Id)
+
+	resp, err := h.watchlistUC.GetWatchlists(tokenData.Id)
+	if err != nil {
+		log.Printf("Request: GetWatchlists. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetWatchlist
+// @Description  Get your watchlist with its films, the latest added first
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "watchlist id"
+// @Success      200  {object}	watchlist.Watchlist
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /watchlist/{id} [get]
+func (h *WatchlistHandler) GetWatchlist(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetWatchlist. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: GetWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	resp, err := h.watchlistUC.GetWatchlist(id, tokenData.Id)
+	if err != nil {
+		log.Printf("Request: GetWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetSharedWatchlist
+// @Description  Get a watchlist shared by link. No authorization is needed, the token is the access
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param        token	path  string     true  "share token"
+// @Success      200  {object}	watchlist.Watchlist
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /shared/watchlist/{token} [get]
+func (h *WatchlistHandler) GetSharedWatchlist(rw http.ResponseWriter, r *http.Request) {
+
+	log.Printf("Request: GetSharedWatchlist")
+
+	resp, err := h.watchlistUC.GetSharedWatchlist(mux.Vars(r)["token"])
+	if err != nil {
+		log.Printf("Request: GetSharedWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      UpdateWatchlist
+// @Description  Rename your watchlist
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "watchlist id"
+// @Param        input	body	watchlist.Watchlist  true  "watchlist data, only name is used"
+// @Success      200  {object}	watchlist.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      409  {object}	error
+// @Failure      500  {object}  error
+// @Router       /watchlist/{id} [patch]
+func (h *WatchlistHandler) UpdateWatchlist(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data watchlist.Watchlist
+		resp *watchlist.ResponseModel = &watchlist.ResponseModel{Status: "OK"}
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: UpdateWatchlist. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: UpdateWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: UpdateWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validateWatchlist(&data); err != nil {
+		log.Printf("Request: UpdateWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.watchlistUC.UpdateWatchlist(id, tokenData.Id, &data)
+	if err != nil {
+		log.Printf("Request: UpdateWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      DeleteWatchlist
+// @Description  Delete your watchlist with its films
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "watchlist id"
+// @Success      200  {object}	watchlist.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /watchlist/{id} [delete]
+func (h *WatchlistHandler) DeleteWatchlist(rw http.ResponseWriter, r *http.Request) {
+	var resp *watchlist.ResponseModel = &watchlist.ResponseModel{Status: "OK"}
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: DeleteWatchlist. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: DeleteWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	err = h.watchlistUC.DeleteWatchlist(id, tokenData.Id)
+	if err != nil {
+		log.Printf("Request: DeleteWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      ShareWatchlist
+// @Description  Share your watchlist read-only by link. Sharing again issues a new token and the old link stops working
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "watchlist id"
+// @Success      200  {object}	watchlist.ShareResponse
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /watchlist/{id}/share [post]
+func (h *WatchlistHandler) ShareWatchlist(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: ShareWatchlist. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: ShareWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	token, err := h.watchlistUC.Share(id, tokenData.Id)
+	if err != nil {
+		log.Printf("Request: ShareWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(&watchlist.ShareResponse{Token: token})
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      UnshareWatchlist
+// @Description  Stop sharing your watchlist, the link stops working
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "watchlist id"
+// @Success      200  {object}	watchlist.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /watchlist/{id}/share [delete]
+func (h *WatchlistHandler) UnshareWatchlist(rw http.ResponseWriter, r *http.Request) {
+	var resp *watchlist.ResponseModel = &watchlist.ResponseModel{Status: "OK"}
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: UnshareWatchlist. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Request: UnshareWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	err = h.watchlistUC.Unshare(id, tokenData.Id)
+	if err != nil {
+		log.Printf("Request: UnshareWatchlist. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      PutItem
+// @Description  Add a film to your watchlist or change its status. A watched film gets today as watched_at unless it is set
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "watchlist id"
+// @Param        film_id	path  int     true  "film id"
+// @Param        input	body	watchlist.ItemParams  true  "status and watched date"
+// @Success      200  {object}	watchlist.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /watchlist/{id}/films/{film_id} [put]
+func (h *WatchlistHandler) PutItem(rw http.ResponseWriter, r *http.Request) {
+	var (
+		data watchlist.ItemParams
+		resp *watchlist.ResponseModel = &watchlist.ResponseModel{Status: "OK"}
+		err  error
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: PutItem. User with ID:%d", tokenData.Id)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		log.Printf("Request: PutItem. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if data.WatchlistId, err = parseId(mux.Vars(r)["id"]); err == nil {
+		data.FilmId, err = parseId(mux.Vars(r)["film_id"])
+	}
+	if err != nil {
+		log.Printf("Request: PutItem. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	if err := h.validateItem(&data); err != nil {
+		log.Printf("Request: PutItem. Error: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.watchlistUC.PutItem(tokenData.Id, &data)
+	if err != nil {
+		log.Printf("Request: PutItem. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      DeleteItem
+// @Description  Remove a film from your watchlist
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Param        id	path  int     true  "watchlist id"
+// @Param        film_id	path  int     true  "film id"
+// @Success      200  {object}	watchlist.ResponseModel
+// @Failure      400  {object}	error
+// @Failure      404  {object}	error
+// @Failure      500  {object}  error
+// @Router       /watchlist/{id}/films/{film_id} [delete]
+func (h *WatchlistHandler) DeleteItem(rw http.ResponseWriter, r *http.Request) {
+	var (
+		resp   *watchlist.ResponseModel = &watchlist.ResponseModel{Status: "OK"}
+		filmId int
+	)
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: DeleteItem. User with ID:%d", tokenData.Id)
+
+	id, err := parseId(mux.Vars(r)["id"])
+	if err == nil {
+		filmId, err = parseId(mux.Vars(r)["film_id"])
+	}
+	if err != nil {
+		log.Printf("Request: DeleteItem. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	err = h.watchlistUC.DeleteItem(tokenData.Id, id, filmId)
+	if err != nil {
+		log.Printf("Request: DeleteItem. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+// @Summary      GetHistory
+// @Description  Get the films you watched in any of your watchlists, the latest first
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param 		 Authorization 	header 	string true  "Authorization"
+// @Success      200  {array}	watchlist.Item
+// @Failure      500  {object}  error
+// @Router       /watchlist/history [get]
+func (h *WatchlistHandler) GetHistory(rw http.ResponseWriter, r *http.Request) {
+
+	tokenData := r.Context().Value(cconstant.ContextValue).(*auth.TokenData)
+	log.Printf("Request: GetHistory. User with ID:%d", tokenData.Id)
+
+	resp, err := h.watchlistUC.GetHistory(tokenData.Id)
+	if err != nil {
+		log.Printf("Request: GetHistory. Error: %s", err.Error())
+		http.Error(rw, err.Error(), errorStatus(err))
+		return
+	}
+
+	rawResponse, _ := json.Marshal(resp)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(rawResponse)
+}
+
+//---------------------------------------------------------------------------------------------------------------------
+
+// validateWatchlist checks a list name; like film names it is trimmed and brought to NFC form.
+func (h *WatchlistHandler) validateWatchlist(data *watchlist.Watchlist) error {
+	data.Name = norm.NFC.String(strings.TrimSpace(data.Name))
+	if !utf8.ValidString(data.Name) || strings.ContainsFunc(data.Name, unicode.IsControl) {
+		return fmt.Errorf("name should be printable UTF-8 text")
+	}
+	if n := utf8.RuneCountInString(data.Name); n == 0 || n > 100 {
+		return fmt.Errorf("size Name should be [1;100]")
+	}
+
+	return nil
+}
+
+// validateItem checks the status of a film in a list. Only watched films have a watched date.
+func (h *WatchlistHandler) validateItem(data *watchlist.ItemParams) error {
+	if !slices.Contains(cconstant.WatchStatuses, data.Status) {
+		return fmt.Errorf("status should be one of %s", strings.Join(cconstant.WatchStatuses, ", "))
+	}
+	if data.WatchedAt == "" {
+		return nil
+	}
+	if data.Status != "watched" {
+		return fmt.Errorf("watched_at is only set for watched films")
+	}
+	if _, err := time.Parse(time.DateOnly, data.WatchedAt); err != nil {
+		return fmt.Errorf("watched_at should be '2000-01-01' format")
+	}
+
+	return nil
+}
+
+func parseId(raw string) (int, error) {
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("id should be a positive number: %w", errBadRequest)
+	}
+
+	return id, nil
+}
+
+// errorStatus picks the response code for an error returned by the usecase.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, errBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, watchlist.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, watchlist.ErrAlreadyExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
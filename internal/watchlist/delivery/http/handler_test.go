@@ -0,0 +1,205 @@
+package http
+
+import (
+	"bytes"
+	"film_library/internal/auth"
+	mock_auth "film_library/internal/auth/mocks"
+	"film_library/internal/watchlist"
+	mock_watchlist "film_library/internal/watchlist/mocks"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWatchlist(t *testing.T) {
+	type mockBehavior func(s *mock_watchlist.MockUsecase)
+	var (
+		created = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		watched = "2024-05-02"
+	)
+
+	testTable := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			path:   "/api/watchlist/add",
+			body:   `{"name":" Weekend "}`,
+			mockBehavior: func(s *mock_watchlist.MockUsecase) {
+				s.EXPECT().CreateWatchlist(1, &watchlist.Watchlist{Name: "Weekend"}).Return(5, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":"","id":5}`,
+		},
+		{
+			name:   "CreateTwice",
+			method: http.MethodPost,
+			path:   "/api/watchlist/add",
+			body:   `{"name":"Weekend"}`,
+			mockBehavior: func(s *mock_watchlist.MockUsecase) {
+				s.EXPECT().CreateWatchlist(1, gomock.Any()).Return(0, fmt.Errorf("Key (user_id, list_name)=(1, Weekend) already exists.: %w", watchlist.ErrAlreadyExists)).Times(1)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       "Key (user_id, list_name)=(1, Weekend) already exists.: already exists\n",
+		},
+		{
+			name:               "CreateNoName",
+			method:             http.MethodPost,
+			path:               "/api/watchlist/add",
+			body:               `{"name":"  "}`,
+			mockBehavior:       func(s *mock_watchlist.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "size Name should be [1;100]\n",
+		},
+		{
+			name:   "Get",
+			method: http.MethodGet,
+			path:   "/api/watchlist/5",
+			mockBehavior: func(s *mock_watchlist.MockUsecase) {
+				s.EXPECT().GetWatchlist(5, 1).Return(&watchlist.Watchlist{Id: 5, Name: "Weekend", Films: 1, CreatedAt: created, Items: []watchlist.Item{
+					{FilmId: 7, Film: "Hamlet", Status: "watched", WatchedAt: &watched, AddedAt: created},
+				}}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"id":5,"name":"Weekend","shared":false,"films":1,"created_at":"2024-05-01T12:00:00Z","items":[{"film_id":7,"film":"Hamlet","status":"watched","watched_at":"2024-05-02","added_at":"2024-05-01T12:00:00Z"}]}`,
+		},
+		{
+			name:   "GetOthers",
+			method: http.MethodGet,
+			path:   "/api/watchlist/6",
+			mockBehavior: func(s *mock_watchlist.MockUsecase) {
+				s.EXPECT().GetWatchlist(6, 1).Return(nil, fmt.Errorf("no watchlist: %w", watchlist.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "no watchlist: not found\n",
+		},
+		{
+			name:   "PutWatched",
+			method: http.MethodPut,
+			path:   "/api/watchlist/5/films/7",
+			body:   `{"status":"watched","watched_at":"2024-05-02"}`,
+			mockBehavior: func(s *mock_watchlist.MockUsecase) {
+				s.EXPECT().PutItem(1, &watchlist.ItemParams{WatchlistId: 5, FilmId: 7, Status: "watched", WatchedAt: "2024-05-02"}).Return(nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"status":"OK","error":""}`,
+		},
+		{
+			name:               "PutBadStatus",
+			method:             http.MethodPut,
+			path:               "/api/watchlist/5/films/7",
+			body:               `{"status":"seen"}`,
+			mockBehavior:       func(s *mock_watchlist.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "status should be one of want_to_watch, watched\n",
+		},
+		{
+			name:               "PutDateNotWatched",
+			method:             http.MethodPut,
+			path:               "/api/watchlist/5/films/7",
+			body:               `{"status":"want_to_watch","watched_at":"2024-05-02"}`,
+			mockBehavior:       func(s *mock_watchlist.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "watched_at is only set for watched films\n",
+		},
+		{
+			name:               "PutBadDate",
+			method:             http.MethodPut,
+			path:               "/api/watchlist/5/films/7",
+			body:               `{"status":"watched","watched_at":"02.05.2024"}`,
+			mockBehavior:       func(s *mock_watchlist.MockUsecase) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "watched_at should be '2000-01-01' format\n",
+		},
+		{
+			name:   "PutNoFilm",
+			method: http.MethodPut,
+			path:   "/api/watchlist/5/films/700",
+			body:   `{"status":"want_to_watch"}`,
+			mockBehavior: func(s *mock_watchlist.MockUsecase) {
+				s.EXPECT().PutItem(1, gomock.Any()).Return(fmt.Errorf(`Key (film_id)=(700) is not present in table "film".: %w`, watchlist.ErrNotFound)).Times(1)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "Key (film_id)=(700) is not present in table \"film\".: not found\n",
+		},
+		{
+			name:   "Share",
+			method: http.MethodPost,
+			path:   "/api/watchlist/5/share",
+			mockBehavior: func(s *mock_watchlist.MockUsecase) {
+				s.EXPECT().Share(5, 1).Return("0a1b", nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"token":"0a1b"}`,
+		},
+		{
+			name:   "Shared",
+			method: http.MethodGet,
+			path:   "/shared/watchlist/0a1b",
+			mockBehavior: func(s *mock_watchlist.MockUsecase) {
+				s.EXPECT().GetSharedWatchlist("0a1b").Return(&watchlist.Watchlist{Id: 5, Name: "Weekend", Shared: true, CreatedAt: created}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"id":5,"name":"Weekend","shared":true,"films":0,"created_at":"2024-05-01T12:00:00Z"}`,
+		},
+		{
+			name:   "History",
+			method: http.MethodGet,
+			path:   "/api/watchlist/history",
+			mockBehavior: func(s *mock_watchlist.MockUsecase) {
+				s.EXPECT().GetHistory(1).Return([]watchlist.Item{}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `[]`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockWatchlist := mock_watchlist.NewMockUsecase(c)
+			mockAuth := mock_auth.NewMockUsecase(c)
+			mockAuth.EXPECT().ParseToken("token").Return(&auth.TokenData{Id: 1}, nil).AnyTimes()
+			testCase.mockBehavior(mockWatchlist)
+
+			handler := NewWatchlistHandler(mockWatchlist, mockAuth)
+			rtr := mux.NewRouter()
+			MapRoutes(rtr, handler)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(testCase.method, testCase.path, bytes.NewBufferString(testCase.body))
+			r.Header.Set("Authorization", "Bearer token")
+			rtr.ServeHTTP(w, r)
+
+			require.Equal(t, testCase.expectedStatusCode, w.Code)
+			require.Equal(t, testCase.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestUserIdentity(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	handler := NewWatchlistHandler(mock_watchlist.NewMockUsecase(c), mock_auth.NewMockUsecase(c))
+	rtr := mux.NewRouter()
+	MapRoutes(rtr, handler)
+
+	w := httptest.NewRecorder()
+	rtr.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/watchlist/get_all", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
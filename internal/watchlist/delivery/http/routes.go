@@ -0,0 +1,25 @@
+package http
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+// MapRoutes registers the watchlist routes. Everything under /api/watchlist
+// belongs to the user of the token; a shared list is read by its token alone.
+func MapRoutes(rtr *mux.Router, h *WatchlistHandler) {
+	api := rtr.PathPrefix("/api/watchlist").Subrouter()
+	api.Use(h.userIdentity)
+	api.HandleFunc("/add", h.CreateWatchlist).Methods(http.MethodPost)
+	api.HandleFunc("/get_all", h.GetWatchlists).Methods(http.MethodGet)
+	api.HandleFunc("/history", h.GetHistory).Methods(http.MethodGet)
+	api.HandleFunc("/{id:[0-9]+}", h.GetWatchlist).Methods(http.MethodGet)
+	api.HandleFunc("/{id:[0-9]+}", h.UpdateWatchlist).Methods(http.MethodPatch)
+	api.HandleFunc("/{id:[0-9]+}", h.DeleteWatchlist).Methods(http.MethodDelete)
+	api.HandleFunc("/{id:[0-9]+}/share", h.ShareWatchlist).Methods(http.MethodPost)
+	api.HandleFunc("/{id:[0-9]+}/share", h.UnshareWatchlist).Methods(http.MethodDelete)
+	api.HandleFunc("/{id:[0-9]+}/films/{film_id:[0-9]+}", h.PutItem).Methods(http.MethodPut)
+	api.HandleFunc("/{id:[0-9]+}/films/{film_id:[0-9]+}", h.DeleteItem).Methods(http.MethodDelete)
+
+	rtr.HandleFunc("/shared/watchlist/{token:[0-9a-f]+}", h.GetSharedWatchlist).Methods(http.MethodGet)
+}
@@ -0,0 +1,180 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/watchlist/repository.go
+
+// Package mock_watchlist is a generated GoMock package.
+package mock_watchlist
+
+import (
+	watchlist "film_library/internal/watchlist"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateWatchlist mocks base method.
+func (m *MockRepository) CreateWatchlist(userId int, params *watchlist.Watchlist) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWatchlist", userId, params)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWatchlist indicates an expected call of CreateWatchlist.
+func (mr *MockRepositoryMockRecorder) CreateWatchlist(userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWatchlist", reflect.TypeOf((*MockRepository)(nil).CreateWatchlist), userId, params)
+}
+
+// DeleteItem mocks base method.
+func (m *MockRepository) DeleteItem(userId, watchlistId, filmId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItem", userId, watchlistId, filmId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteItem indicates an expected call of DeleteItem.
+func (mr *MockRepositoryMockRecorder) DeleteItem(userId, watchlistId, filmId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockRepository)(nil).DeleteItem), userId, watchlistId, filmId)
+}
+
+// DeleteWatchlist mocks base method.
+func (m *MockRepository) DeleteWatchlist(id, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWatchlist", id, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWatchlist indicates an expected call of DeleteWatchlist.
+func (mr *MockRepositoryMockRecorder) DeleteWatchlist(id, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWatchlist", reflect.TypeOf((*MockRepository)(nil).DeleteWatchlist), id, userId)
+}
+
+// GetHistory mocks base method.
+func (m *MockRepository) GetHistory(userId int) ([]watchlist.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", userId)
+	ret0, _ := ret[0].([]watchlist.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockRepositoryMockRecorder) GetHistory(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockRepository)(nil).GetHistory), userId)
+}
+
+// GetSharedWatchlist mocks base method.
+func (m *MockRepository) GetSharedWatchlist(token string) (*watchlist.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedWatchlist", token)
+	ret0, _ := ret[0].(*watchlist.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedWatchlist indicates an expected call of GetSharedWatchlist.
+func (mr *MockRepositoryMockRecorder) GetSharedWatchlist(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedWatchlist", reflect.TypeOf((*MockRepository)(nil).GetSharedWatchlist), token)
+}
+
+// GetWatchlist mocks base method.
+func (m *MockRepository) GetWatchlist(id, userId int) (*watchlist.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchlist", id, userId)
+	ret0, _ := ret[0].(*watchlist.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchlist indicates an expected call of GetWatchlist.
+func (mr *MockRepositoryMockRecorder) GetWatchlist(id, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchlist", reflect.TypeOf((*MockRepository)(nil).GetWatchlist), id, userId)
+}
+
+// GetWatchlists mocks base method.
+func (m *MockRepository) GetWatchlists(userId int) ([]watchlist.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchlists", userId)
+	ret0, _ := ret[0].([]watchlist.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchlists indicates an expected call of GetWatchlists.
+func (mr *MockRepositoryMockRecorder) GetWatchlists(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchlists", reflect.TypeOf((*MockRepository)(nil).GetWatchlists), userId)
+}
+
+// PutItem mocks base method.
+func (m *MockRepository) PutItem(userId int, params *watchlist.ItemParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutItem", userId, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutItem indicates an expected call of PutItem.
+func (mr *MockRepositoryMockRecorder) PutItem(userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutItem", reflect.TypeOf((*MockRepository)(nil).PutItem), userId, params)
+}
+
+// SetShareToken mocks base method.
+func (m *MockRepository) SetShareToken(id, userId int, token *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetShareToken", id, userId, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetShareToken indicates an expected call of SetShareToken.
+func (mr *MockRepositoryMockRecorder) SetShareToken(id, userId, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetShareToken", reflect.TypeOf((*MockRepository)(nil).SetShareToken), id, userId, token)
+}
+
+// UpdateWatchlist mocks base method.
+func (m *MockRepository) UpdateWatchlist(id, userId int, params *watchlist.Watchlist) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWatchlist", id, userId, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWatchlist indicates an expected call of UpdateWatchlist.
+func (mr *MockRepositoryMockRecorder) UpdateWatchlist(id, userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWatchlist", reflect.TypeOf((*MockRepository)(nil).UpdateWatchlist), id, userId, params)
+}
@@ -0,0 +1,195 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/watchlist/usecase.go
+
+// Package mock_watchlist is a generated GoMock package.
+package mock_watchlist
+
+import (
+	watchlist "film_library/internal/watchlist"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUsecase is a mock of Usecase interface.
+type MockUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseMockRecorder
+}
+
+// MockUsecaseMockRecorder is the mock recorder for MockUsecase.
+type MockUsecaseMockRecorder struct {
+	mock *MockUsecase
+}
+
+// NewMockUsecase creates a new mock instance.
+func NewMockUsecase(ctrl *gomock.Controller) *MockUsecase {
+	mock := &MockUsecase{ctrl: ctrl}
+	mock.recorder = &MockUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
+	return m.recorder
+}
+
+// CreateWatchlist mocks base method.
+func (m *MockUsecase) CreateWatchlist(userId int, params *watchlist.Watchlist) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWatchlist", userId, params)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWatchlist indicates an expected call of CreateWatchlist.
+func (mr *MockUsecaseMockRecorder) CreateWatchlist(userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWatchlist", reflect.TypeOf((*MockUsecase)(nil).CreateWatchlist), userId, params)
+}
+
+// DeleteItem mocks base method.
+func (m *MockUsecase) DeleteItem(userId, watchlistId, filmId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItem", userId, watchlistId, filmId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteItem indicates an expected call of DeleteItem.
+func (mr *MockUsecaseMockRecorder) DeleteItem(userId, watchlistId, filmId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockUsecase)(nil).DeleteItem), userId, watchlistId, filmId)
+}
+
+// DeleteWatchlist mocks base method.
+func (m *MockUsecase) DeleteWatchlist(id, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWatchlist", id, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWatchlist indicates an expected call of DeleteWatchlist.
+func (mr *MockUsecaseMockRecorder) DeleteWatchlist(id, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWatchlist", reflect.TypeOf((*MockUsecase)(nil).DeleteWatchlist), id, userId)
+}
+
+// GetHistory mocks base method.
+func (m *MockUsecase) GetHistory(userId int) ([]watchlist.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", userId)
+	ret0, _ := ret[0].([]watchlist.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockUsecaseMockRecorder) GetHistory(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockUsecase)(nil).GetHistory), userId)
+}
+
+// GetSharedWatchlist mocks base method.
+func (m *MockUsecase) GetSharedWatchlist(token string) (*watchlist.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedWatchlist", token)
+	ret0, _ := ret[0].(*watchlist.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedWatchlist indicates an expected call of GetSharedWatchlist.
+func (mr *MockUsecaseMockRecorder) GetSharedWatchlist(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedWatchlist", reflect.TypeOf((*MockUsecase)(nil).GetSharedWatchlist), token)
+}
+
+// GetWatchlist mocks base method.
+func (m *MockUsecase) GetWatchlist(id, userId int) (*watchlist.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchlist", id, userId)
+	ret0, _ := ret[0].(*watchlist.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchlist indicates an expected call of GetWatchlist.
+func (mr *MockUsecaseMockRecorder) GetWatchlist(id, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchlist", reflect.TypeOf((*MockUsecase)(nil).GetWatchlist), id, userId)
+}
+
+// GetWatchlists mocks base method.
+func (m *MockUsecase) GetWatchlists(userId int) ([]watchlist.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchlists", userId)
+	ret0, _ := ret[0].([]watchlist.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchlists indicates an expected call of GetWatchlists.
+func (mr *MockUsecaseMockRecorder) GetWatchlists(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchlists", reflect.TypeOf((*MockUsecase)(nil).GetWatchlists), userId)
+}
+
+// PutItem mocks base method.
+func (m *MockUsecase) PutItem(userId int, params *watchlist.ItemParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutItem", userId, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutItem indicates an expected call of PutItem.
+func (mr *MockUsecaseMockRecorder) PutItem(userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutItem", reflect.TypeOf((*MockUsecase)(nil).PutItem), userId, params)
+}
+
+// Share mocks base method.
+func (m *MockUsecase) Share(id, userId int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Share", id, userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Share indicates an expected call of Share.
+func (mr *MockUsecaseMockRecorder) Share(id, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Share", reflect.TypeOf((*MockUsecase)(nil).Share), id, userId)
+}
+
+// Unshare mocks base method.
+func (m *MockUsecase) Unshare(id, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unshare", id, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unshare indicates an expected call of Unshare.
+func (mr *MockUsecaseMockRecorder) Unshare(id, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unshare", reflect.TypeOf((*MockUsecase)(nil).Unshare), id, userId)
+}
+
+// UpdateWatchlist mocks base method.
+func (m *MockUsecase) UpdateWatchlist(id, userId int, params *watchlist.Watchlist) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWatchlist", id, userId, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWatchlist indicates an expected call of UpdateWatchlist.
+func (mr *MockUsecaseMockRecorder) UpdateWatchlist(id, userId, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWatchlist", reflect.TypeOf((*MockUsecase)(nil).UpdateWatchlist), id, userId, params)
+}
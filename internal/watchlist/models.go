@@ -0,0 +1,42 @@
+package watchlist
+
+import "time"
+
+// Watchlist is a named list of films of one user. Films is the number of
+// films in the list, Items the films themselves when the list is requested by id.
+type Watchlist struct {
+	Id        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"list_name"`
+	Shared    bool      `json:"shared" db:"shared"`
+	Films     int       `json:"films" db:"films"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	Items     []Item    `json:"items,omitempty" db:"-"`
+}
+
+// Item is a film in a watchlist. WatchedAt is set for watched films only.
+type Item struct {
+	FilmId    int       `json:"film_id" db:"film_id"`
+	Film      string    `json:"film" db:"film_name"`
+	Status    string    `json:"status" db:"status"`
+	WatchedAt *string   `json:"watched_at" db:"watched_at"`
+	AddedAt   time.Time `json:"added_at" db:"added_at"`
+}
+
+// ItemParams put a film into a watchlist or change its status.
+// WatchedAt defaults to today for watched films.
+type ItemParams struct {
+	WatchlistId int    `json:"-"`
+	FilmId      int    `json:"-"`
+	Status      string `json:"status"`
+	WatchedAt   string `json:"watched_at"`
+}
+
+type ShareResponse struct {
+	Token string `json:"token"`
+}
+
+type ResponseModel struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Id     int    `json:"id,omitempty"`
+}
@@ -0,0 +1,15 @@
+package watchlist
+
+type Repository interface {
+	CreateWatchlist(userId int, params *Watchlist) (int, error)
+	GetWatchlist(id, userId int) (*Watchlist, error)
+	GetWatchlists(userId int) ([]Watchlist, error)
+	GetSharedWatchlist(token string) (*Watchlist, error)
+	UpdateWatchlist(id, userId int, params *Watchlist) error
+	DeleteWatchlist(id, userId int) error
+	SetShareToken(id, userId int, token *string) error
+
+	PutItem(userId int, params *ItemParams) error
+	DeleteItem(userId, watchlistId, filmId int) error
+	GetHistory(userId int) ([]Item, error)
+}
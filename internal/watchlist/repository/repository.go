@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"errors"
+	"film_library/internal/cconstant"
+	"film_library/internal/watchlist"
+	"fmt"
+	"github.com/jackc/pgx"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// itemColumns selects a watchlist item i together with the name of its film f.
+const itemColumns = `i.film_id, f.film_name, i.status, to_char(i.watched_at, 'YYYY-MM-DD') AS watched_at, i.added_at`
+
+type postgresRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresRepository(db *sqlx.DB) watchlist.Repository {
+	return &postgresRepository{db: db}
+}
+
+// ----------------------------------------------------- Watchlist ----------------------------------------------------------
+
+func (p *postgresRepository) CreateWatchlist(userId int, params *watchlist.Watchlist) (int, error) {
+	var (
+		query = `
+		INSERT INTO %[1]s (user_id, list_name)
+		VALUES ($1, $2)
+		RETURNING id`
+
+		values = []any{userId, params.Name}
+	)
+
+	query = fmt.Sprintf(query, cconstant.WatchlistDB)
+
+	var id int
+	if err := p.db.Get(&id, query, values...); err != nil {
+		return 0, translateError(err)
+	}
+
+	return id, nil
+}
+
+// GetWatchlist returns a list of the user with its films, the latest added first.
+func (p *postgresRepository) GetWatchlist(id, userId int) (*watchlist.Watchlist, error) {
+	return p.getWatchlist("w.id = $1 AND w.user_id = $2", id, userId)
+}
+
+// GetSharedWatchlist returns the list shared with token, whoever owns it.
+func (p *postgresRepository) GetSharedWatchlist(token string) (*watchlist.Watchlist, error) {
+	return p.getWatchlist("w.share_token = $1", token)
+}
+
+func (p *postgresRepository) getWatchlist(cond string, values ...any) (*watchlist.Watchlist, error) {
+	var (
+		data  []watchlist.Watchlist
+		query = `
+		SELECT w.id, w.list_name, w.share_token IS NOT NULL AS shared, w.created_at,
+		       (SELECT count(*)
+		        FROM %[2]s i
+		        JOIN %[4]s f ON f.id = i.film_id
+		        WHERE i.watchlist_id = w.id AND f.deleted_at IS NULL) AS films
+		FROM %[1]s w
+		WHERE %[3]s
+		`
+		itemsQuery = `
+		SELECT %[3]s
+		FROM %[1]s i
+		JOIN %[2]s f ON f.id = i.film_id
+		WHERE i.watchlist_id = $1 AND f.deleted_at IS NULL
+		ORDER BY i.added_at DESC, i.film_id
+		`
+	)
+
+	query = fmt.Sprintf(query, cconstant.WatchlistDB, cconstant.WatchlistItemDB, cond, cconstant.FilmDB)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return &watchlist.Watchlist{}, err
+	}
+
+	if len(data) == 0 {
+		return &watchlist.Watchlist{}, fmt.Errorf("no watchlist: %w", watchlist.ErrNotFound)
+	}
+
+	itemsQuery = fmt.Sprintf(itemsQuery, cconstant.WatchlistItemDB, cconstant.FilmDB, itemColumns)
+
+	data[0].Items = make([]watchlist.Item, 0)
+	if err := p.db.Select(&data[0].Items, itemsQuery, data[0].Id); err != nil {
+		return &watchlist.Watchlist{}, err
+	}
+
+	return &data[0], nil
+}
+
+// GetWatchlists lists the watchlists of the user without their films.
+func (p *postgresRepository) GetWatchlists(userId int) ([]watchlist.Watchlist, error) {
+	var (
+		data  = make([]watchlist.Watchlist, 0)
+		query = `
+		SELECT w.id, w.list_name, w.share_token IS NOT NULL AS shared, w.created_at, count(i.film_id) AS films
+		FROM %[1]s w
+		LEFT JOIN (%[2]s i JOIN %[3]s f ON f.id = i.film_id AND f.deleted_at IS NULL) ON i.watchlist_id = w.id
+		WHERE w.user_id = $1
+		GROUP BY w.id
+		ORDER BY w.list_name, w.id
+		`
+
+		values = []any{userId}
+	)
+
+	query = fmt.Sprintf(query, cconstant.WatchlistDB, cconstant.WatchlistItemDB, cconstant.FilmDB)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (p *postgresRepository) UpdateWatchlist(id, userId int, params *watchlist.Watchlist) error {
+	var (
+		query = `
+		UPDATE %[1]s SET list_name = $1
+		WHERE id = $2 AND user_id = $3
+		`
+
+		values = []any{params.Name, id, userId}
+	)
+
+	return p.execOwned(query, values...)
+}
+
+func (p *postgresRepository) DeleteWatchlist(id, userId int) error {
+	var (
+		query = `
+		DELETE FROM %[1]s
+		WHERE id = $1 AND user_id = $2
+		`
+
+		values = []any{id, userId}
+	)
+
+	return p.execOwned(query, values...)
+}
+
+// SetShareToken shares the list by token, a nil token stops sharing it.
+func (p *postgresRepository) SetShareToken(id, userId int, token *string) error {
+	var (
+		query = `
+		UPDATE %[1]s SET share_token = $1
+		WHERE id = $2 AND user_id = $3
+		`
+
+		values = []any{token, id, userId}
+	)
+
+	return p.execOwned(query, values...)
+}
+
+// execOwned runs a statement on a watchlist table row that belongs to the user.
+// Lists of other users are reported as missing.
+func (p *postgresRepository) execOwned(query string, values ...any) error {
+	query = fmt.Sprintf(query, cconstant.WatchlistDB)
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("no watchlist: %w", watchlist.ErrNotFound)
+	}
+
+	return nil
+}
+
+// ----------------------------------------------------- Items ----------------------------------------------------------
+
+// PutItem adds a film to a list of the user or replaces its status.
+func (p *postgresRepository) PutItem(userId int, params *watchlist.ItemParams) error {
+	var (
+		query = `
+		INSERT INTO %[1]s (watchlist_id, film_id, status, watched_at)
+		SELECT w.id, $2, $3, CAST(NULLIF($4, '') AS date)
+		FROM %[2]s w
+		WHERE w.id = $1 AND w.user_id = $5
+		ON CONFLICT (watchlist_id, film_id) DO UPDATE SET status = excluded.status, watched_at = excluded.watched_at
+		`
+
+		values = []any{params.WatchlistId, params.FilmId, params.Status, params.WatchedAt, userId}
+
+		live      bool
+		liveQuery = `SELECT EXISTS (SELECT 1 FROM %[1]s WHERE id = $1 AND deleted_at IS NULL)`
+	)
+
+	// A deleted film is still in its table, the foreign key would accept it.
+	if err := p.db.Get(&live, fmt.Sprintf(liveQuery, cconstant.FilmDB), params.FilmId); err != nil {
+		return err
+	}
+	if !live {
+		return fmt.Errorf("no film: %w", watchlist.ErrNotFound)
+	}
+
+	query = fmt.Sprintf(query, cconstant.WatchlistItemDB, cconstant.WatchlistDB)
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("no watchlist: %w", watchlist.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (p *postgresRepository) DeleteItem(userId, watchlistId, filmId int) error {
+	var (
+		query = `
+		DELETE FROM %[1]s i
+		USING %[2]s w
+		WHERE w.id = i.watchlist_id AND i.watchlist_id = $1 AND i.film_id = $2 AND w.user_id = $3
+		`
+
+		values = []any{watchlistId, filmId, userId}
+	)
+
+	query = fmt.Sprintf(query, cconstant.WatchlistItemDB, cconstant.WatchlistDB)
+
+	res, err := p.db.Exec(query, values...)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("no film in watchlist: %w", watchlist.ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetHistory returns the films the user watched, in any of their lists,
+// the latest first. A film watched in several lists is listed once.
+func (p *postgresRepository) GetHistory(userId int) ([]watchlist.Item, error) {
+	var (
+		data  = make([]watchlist.Item, 0)
+		query = `
+		SELECT *
+		FROM (SELECT DISTINCT ON (i.film_id) %[4]s
+		      FROM %[1]s i
+		      JOIN %[2]s w ON w.id = i.watchlist_id
+		      JOIN %[3]s f ON f.id = i.film_id
+		      WHERE w.user_id = $1 AND i.status = 'watched' AND f.deleted_at IS NULL
+		      ORDER BY i.film_id, i.watched_at DESC) h
+		ORDER BY h.watched_at DESC, h.film_name
+		`
+
+		values = []any{userId}
+	)
+
+	query = fmt.Sprintf(query, cconstant.WatchlistItemDB, cconstant.WatchlistDB, cconstant.FilmDB, itemColumns)
+
+	if err := p.db.Select(&data, query, values...); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ----------------------------------------------------- Errors ----------------------------------------------------------
+
+// translateError maps constraint violations onto the watchlist errors the handlers understand.
+func translateError(err error) error {
+	var pgErr pgx.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case uniqueViolation:
+		return fmt.Errorf("%s: %w", pgErr.Detail, watchlist.ErrAlreadyExists)
+	case foreignKeyViolation:
+		return fmt.Errorf("%s: %w", pgErr.Detail, watchlist.ErrNotFound)
+	}
+
+	return err
+}
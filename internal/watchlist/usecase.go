@@ -0,0 +1,16 @@
+package watchlist
+
+type Usecase interface {
+	CreateWatchlist(userId int, params *Watchlist) (int, error)
+	GetWatchlist(id, userId int) (*Watchlist, error)
+	GetWatchlists(userId int) ([]Watchlist, error)
+	GetSharedWatchlist(token string) (*Watchlist, error)
+	UpdateWatchlist(id, userId int, params *Watchlist) error
+	DeleteWatchlist(id, userId int) error
+	Share(id, userId int) (string, error)
+	Unshare(id, userId int) error
+
+	PutItem(userId int, params *ItemParams) error
+	DeleteItem(userId, watchlistId, filmId int) error
+	GetHistory(userId int) ([]Item, error)
+}
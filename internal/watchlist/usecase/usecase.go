@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"film_library/internal/watchlist"
+	"time"
+)
+
+// shareTokenBytes is the amount of randomness in a share token,
+// which is twice as long in hex.
+const shareTokenBytes = 16
+
+type WatchlistUsecase struct {
+	repo watchlist.Repository
+	now  func() time.Time
+}
+
+func NewWatchlistUsecase(repo watchlist.Repository) watchlist.Usecase {
+	return &WatchlistUsecase{repo: repo, now: time.Now}
+}
+
+func (u *WatchlistUsecase) CreateWatchlist(userId int, params *watchlist.Watchlist) (int, error) {
+	return u.repo.CreateWatchlist(userId, params)
+}
+
+func (u *WatchlistUsecase) GetWatchlist(id, userId int) (*watchlist.Watchlist, error) {
+	return u.repo.GetWatchlist(id, userId)
+}
+
+func (u *WatchlistUsecase) GetWatchlists(userId int) ([]watchlist.Watchlist, error) {
+	return u.repo.GetWatchlists(userId)
+}
+
+func (u *WatchlistUsecase) GetSharedWatchlist(token string) (*watchlist.Watchlist, error) {
+	return u.repo.GetSharedWatchlist(token)
+}
+
+func (u *WatchlistUsecase) UpdateWatchlist(id, userId int, params *watchlist.Watchlist) error {
+	return u.repo.UpdateWatchlist(id, userId, params)
+}
+
+func (u *WatchlistUsecase) DeleteWatchlist(id, userId int) error {
+	return u.repo.DeleteWatchlist(id, userId)
+}
+
+// Share gives the list a new share token and returns it. A link with the
+// previous token, if there was one, stops working.
+func (u *WatchlistUsecase) Share(id, userId int) (string, error) {
+	raw := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := u.repo.SetShareToken(id, userId, &token); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (u *WatchlistUsecase) Unshare(id, userId int) error {
+	return u.repo.SetShareToken(id, userId, nil)
+}
+
+func (u *WatchlistUsecase) PutItem(userId int, params *watchlist.ItemParams) error {
+	if params.Status == "watched" && params.WatchedAt == "" {
+		params.WatchedAt = u.now().Format(time.DateOnly)
+	}
+
+	return u.repo.PutItem(userId, params)
+}
+
+func (u *WatchlistUsecase) DeleteItem(userId, watchlistId, filmId int) error {
+	return u.repo.DeleteItem(userId, watchlistId, filmId)
+}
+
+func (u *WatchlistUsecase) GetHistory(userId int) ([]watchlist.Item, error) {
+	return u.repo.GetHistory(userId)
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"film_library/internal/watchlist"
+	mock_watchlist "film_library/internal/watchlist/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPutItem(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	repo := mock_watchlist.NewMockRepository(ctr)
+	repo.EXPECT().PutItem(2, &watchlist.ItemParams{WatchlistId: 1, FilmId: 7, Status: "watched", WatchedAt: "2024-05-01"}).Return(nil).Times(1)
+	repo.EXPECT().PutItem(2, &watchlist.ItemParams{WatchlistId: 1, FilmId: 8, Status: "watched", WatchedAt: "2023-12-31"}).Return(nil).Times(1)
+	repo.EXPECT().PutItem(2, &watchlist.ItemParams{WatchlistId: 1, FilmId: 9, Status: "want_to_watch"}).Return(nil).Times(1)
+
+	u := &WatchlistUsecase{repo: repo, now: func() time.Time { return time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC) }}
+	require.NoError(t, u.PutItem(2, &watchlist.ItemParams{WatchlistId: 1, FilmId: 7, Status: "watched"}))
+	require.NoError(t, u.PutItem(2, &watchlist.ItemParams{WatchlistId: 1, FilmId: 8, Status: "watched", WatchedAt: "2023-12-31"}))
+	require.NoError(t, u.PutItem(2, &watchlist.ItemParams{WatchlistId: 1, FilmId: 9, Status: "want_to_watch"}))
+}
+
+func TestShare(t *testing.T) {
+	ctr := gomock.NewController(t)
+	defer ctr.Finish()
+
+	var tokens []string
+	repo := mock_watchlist.NewMockRepository(ctr)
+	repo.EXPECT().SetShareToken(1, 2, gomock.Not(gomock.Nil())).DoAndReturn(func(id, userId int, token *string) error {
+		tokens = append(tokens, *token)
+		return nil
+	}).Times(2)
+	repo.EXPECT().SetShareToken(1, 2, gomock.Nil()).Return(nil).Times(1)
+
+	u := NewWatchlistUsecase(repo)
+	first, err := u.Share(1, 2)
+	require.NoError(t, err)
+	require.Len(t, first, 2*shareTokenBytes)
+	second, err := u.Share(1, 2)
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+	require.Equal(t, []string{first, second}, tokens)
+
+	require.NoError(t, u.Unshare(1, 2))
+}
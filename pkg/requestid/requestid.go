@@ -0,0 +1,58 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"film_library/internal/cconstant"
+	"net/http"
+)
+
+// maxLen bounds an id sent by the client; longer ones are replaced.
+const maxLen = 64
+
+type contextKey struct{}
+
+// Middleware tags every request with an id: the X-Request-Id the client sent
+// or a new random one. The id is echoed in the response and kept in the
+// request context for FromContext.
+func Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(cconstant.RequestIdHeader)
+		if !valid(id) {
+			id = generate()
+		}
+
+		rw.Header().Set(cconstant.RequestIdHeader, id)
+		ctx := context.WithValue(r.Context(), contextKey{}, id)
+
+		h.ServeHTTP(rw, r.WithContext(ctx))
+	})
+}
+
+// FromContext returns the id of the request, "" outside of Middleware.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// valid accepts printable ASCII ids of up to maxLen characters.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func generate() string {
+	raw := make([]byte, 16)
+	_, _ = rand.Read(raw)
+	return hex.EncodeToString(raw)
+}
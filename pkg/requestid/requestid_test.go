@@ -0,0 +1,45 @@
+package requestid
+
+import (
+	"film_library/internal/cconstant"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	testTable := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{name: "Sent", header: "3f2c-9a1b", keep: true},
+		{name: "Missing", header: ""},
+		{name: "TooLong", header: strings.Repeat("a", maxLen+1)},
+		{name: "NotPrintable", header: "bad id\n"},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			var seen string
+			h := Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				seen = FromContext(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/api/film/get_all", nil)
+			r.Header.Set(cconstant.RequestIdHeader, testCase.header)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			require.NotEmpty(t, seen)
+			require.Equal(t, seen, w.Header().Get(cconstant.RequestIdHeader))
+			if testCase.keep {
+				require.Equal(t, testCase.header, seen)
+			} else {
+				require.Len(t, seen, 32)
+			}
+		})
+	}
+}
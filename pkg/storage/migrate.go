@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationLockKey is the pg_advisory_lock key held while migrations run,
+// so replicas starting at the same time apply them one after another.
+const migrationLockKey = 7245190341
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int        `db:"version"`
+	Name      string     `db:"name"`
+	AppliedAt *time.Time `db:"applied_at"`
+}
+
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sqlx.DB) (*Migrator, error) {
+	sub, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := parseMigrations(sub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies every pending migration and returns how many were applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	var applied int
+
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mg := range m.migrations {
+			if _, ok := done[mg.Version]; ok {
+				continue
+			}
+
+			if err = m.apply(ctx, conn, mg.Up,
+				`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, mg.Version, mg.Name); err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", mg.Version, mg.Name, err)
+			}
+			applied++
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the last steps applied migrations and returns how many were rolled back.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	var reverted int
+
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			mg := m.migrations[i]
+			if _, ok := done[mg.Version]; !ok {
+				continue
+			}
+
+			if err = m.apply(ctx, conn, mg.Down,
+				`DELETE FROM schema_migrations WHERE version = $1`, mg.Version); err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", mg.Version, mg.Name, err)
+			}
+			reverted++
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status lists every known migration together with the time it was applied, if it was.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var data []MigrationStatus
+
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		done, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mg := range m.migrations {
+			status := MigrationStatus{Version: mg.Version, Name: mg.Name}
+			if appliedAt, ok := done[mg.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			data = append(data, status)
+		}
+
+		return nil
+	})
+
+	return data, err
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sqlx.Conn) error) error {
+	var (
+		query = `
+		CREATE TABLE IF NOT EXISTS schema_migrations
+		(
+			version    integer     not null primary key,
+			name       text        not null,
+			applied_at timestamptz not null default now()
+		)`
+	)
+
+	// Advisory locks belong to a session, so everything runs on one dedicated connection.
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+
+	if _, err = conn.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sqlx.Conn) (map[int]time.Time, error) {
+	var (
+		data  []MigrationStatus
+		query = `SELECT version, name, applied_at FROM schema_migrations`
+	)
+
+	if err := conn.SelectContext(ctx, &data, query); err != nil {
+		return nil, err
+	}
+
+	done := make(map[int]time.Time, len(data))
+	for _, d := range data {
+		done[d.Version] = *d.AppliedAt
+	}
+
+	return done, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sqlx.Conn, script, bookkeeping string, args ...any) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// parseMigrations reads "<version>_<name>.up.sql" / "<version>_<name>.down.sql" pairs
+// and returns them ordered by version.
+func parseMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		direction := path.Ext(base)
+		base = strings.TrimSuffix(base, direction)
+		if direction != ".up" && direction != ".down" {
+			return nil, fmt.Errorf("migration %s: expected .up.sql or .down.sql suffix", entry.Name())
+		}
+
+		rawVersion, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: expected <version>_<name> format", entry.Name())
+		}
+		version, err := strconv.Atoi(rawVersion)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: version should be a positive number", entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: name}
+			byVersion[version] = mg
+		}
+		if mg.Name != name {
+			return nil, fmt.Errorf("migration %d: name mismatch %q and %q", version, mg.Name, name)
+		}
+
+		if direction == ".up" {
+			mg.Up = string(content)
+		} else {
+			mg.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		if mg.Up == "" || mg.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s: both up and down scripts are required", mg.Version, mg.Name)
+		}
+		migrations = append(migrations, *mg)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"github.com/stretchr/testify/require"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_second.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+		"0002_second.down.sql": {Data: []byte("DROP TABLE b;")},
+		"0001_first.up.sql":    {Data: []byte("CREATE TABLE a ();")},
+		"0001_first.down.sql":  {Data: []byte("DROP TABLE a;")},
+		"README.md":            {Data: []byte("ignored")},
+	}
+
+	migrations, err := parseMigrations(fsys)
+	require.NoError(t, err)
+	require.Equal(t, []Migration{
+		{Version: 1, Name: "first", Up: "CREATE TABLE a ();", Down: "DROP TABLE a;"},
+		{Version: 2, Name: "second", Up: "CREATE TABLE b ();", Down: "DROP TABLE b;"},
+	}, migrations)
+}
+
+func TestParseMigrationsErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		in   fstest.MapFS
+	}{
+		{
+			name: "NoDown",
+			in:   fstest.MapFS{"0001_first.up.sql": {Data: []byte("SELECT 1;")}},
+		},
+		{
+			name: "NoDirection",
+			in:   fstest.MapFS{"0001_first.sql": {Data: []byte("SELECT 1;")}},
+		},
+		{
+			name: "BadVersion",
+			in:   fstest.MapFS{"first.up.sql": {Data: []byte("SELECT 1;")}},
+		},
+		{
+			name: "NameMismatch",
+			in: fstest.MapFS{
+				"0001_first.up.sql":   {Data: []byte("SELECT 1;")},
+				"0001_other.down.sql": {Data: []byte("SELECT 1;")},
+			},
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			_, err := parseMigrations(tCase.in)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	sub, err := fs.Sub(migrationFiles, "migrations")
+	require.NoError(t, err)
+
+	migrations, err := parseMigrations(sub)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, mg := range migrations {
+		require.Equal(t, i+1, mg.Version)
+	}
+}
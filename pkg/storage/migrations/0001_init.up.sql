@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS "actor"
+(
+    id         serial       not null unique,
+    actor_name varchar(100) not null,
+    sex        varchar(1)   not null,
+    bdate      date         not null,
+    list_film  text[]
+);
+
+CREATE TABLE IF NOT EXISTS "film"
+(
+    id           serial       not null unique,
+    film_name    varchar(150) not null,
+    release_date date         not null,
+    rating       real         not null,
+    description  varchar(1000),
+    list_actor   text[]
+);
+
+CREATE TABLE IF NOT EXISTS "auth"
+(
+    id       serial       not null unique,
+    login    varchar(255) not null unique,
+    password text         not null,
+    role     smallint default 0
+);
@@ -0,0 +1,16 @@
+ALTER TABLE "actor" ADD COLUMN IF NOT EXISTS list_film text[];
+ALTER TABLE "film" ADD COLUMN IF NOT EXISTS list_actor text[];
+
+UPDATE "actor" a
+SET list_film = (SELECT array_agg(f.film_name)
+                 FROM "actor_film" af
+                 JOIN "film" f ON f.id = af.film_id
+                 WHERE af.actor_id = a.id);
+
+UPDATE "film" f
+SET list_actor = (SELECT array_agg(a.actor_name)
+                  FROM "actor_film" af
+                  JOIN "actor" a ON a.id = af.actor_id
+                  WHERE af.film_id = f.id);
+
+DROP TABLE IF EXISTS "actor_film";
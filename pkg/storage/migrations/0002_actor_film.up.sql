@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS "actor_film"
+(
+    actor_id integer not null references "actor" (id) on delete cascade,
+    film_id  integer not null references "film" (id) on delete cascade,
+    primary key (actor_id, film_id)
+);
+
+CREATE INDEX IF NOT EXISTS actor_film_film_id_idx ON "actor_film" (film_id);
+
+-- Relations used to live in the list_film/list_actor arrays: move them into
+-- the join table before dropping the arrays.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'actor' AND column_name = 'list_film') THEN
+        INSERT INTO "actor_film" (actor_id, film_id)
+        SELECT a.id, f.id
+        FROM "actor" a
+        CROSS JOIN LATERAL unnest(a.list_film) AS lf(film_name)
+        JOIN "film" f ON f.film_name = lf.film_name
+        ON CONFLICT DO NOTHING;
+        ALTER TABLE "actor" DROP COLUMN list_film;
+    END IF;
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'film' AND column_name = 'list_actor') THEN
+        INSERT INTO "actor_film" (actor_id, film_id)
+        SELECT a.id, f.id
+        FROM "film" f
+        CROSS JOIN LATERAL unnest(f.list_actor) AS la(actor_name)
+        JOIN "actor" a ON a.actor_name = la.actor_name
+        ON CONFLICT DO NOTHING;
+        ALTER TABLE "film" DROP COLUMN list_actor;
+    END IF;
+END $$;
@@ -0,0 +1,2 @@
+ALTER TABLE "film" DROP CONSTRAINT IF EXISTS film_name_release_date_key;
+ALTER TABLE "actor" DROP CONSTRAINT IF EXISTS actor_name_bdate_key;
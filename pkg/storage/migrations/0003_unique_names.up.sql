@@ -0,0 +1,3 @@
+-- The same name is allowed for different people and films, but not twice on the same date.
+ALTER TABLE "actor" ADD CONSTRAINT actor_name_bdate_key UNIQUE (actor_name, bdate);
+ALTER TABLE "film" ADD CONSTRAINT film_name_release_date_key UNIQUE (film_name, release_date);
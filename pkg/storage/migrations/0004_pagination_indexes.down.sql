@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS film_description_id_idx;
+DROP INDEX IF EXISTS film_rating_id_idx;
+DROP INDEX IF EXISTS film_release_date_id_idx;
+DROP INDEX IF EXISTS film_film_name_id_idx;
+
+DROP INDEX IF EXISTS actor_bdate_id_idx;
+DROP INDEX IF EXISTS actor_sex_id_idx;
+DROP INDEX IF EXISTS actor_actor_name_id_idx;
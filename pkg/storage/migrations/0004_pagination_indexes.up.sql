@@ -0,0 +1,9 @@
+-- Keyset pagination orders every list by (sort column, id).
+CREATE INDEX IF NOT EXISTS actor_actor_name_id_idx ON "actor" (actor_name, id);
+CREATE INDEX IF NOT EXISTS actor_sex_id_idx ON "actor" (sex, id);
+CREATE INDEX IF NOT EXISTS actor_bdate_id_idx ON "actor" (bdate, id);
+
+CREATE INDEX IF NOT EXISTS film_film_name_id_idx ON "film" (film_name, id);
+CREATE INDEX IF NOT EXISTS film_release_date_id_idx ON "film" (release_date, id);
+CREATE INDEX IF NOT EXISTS film_rating_id_idx ON "film" (rating, id);
+CREATE INDEX IF NOT EXISTS film_description_id_idx ON "film" ((COALESCE(description, '')), id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS actor_fts_idx;
+
+DROP INDEX IF EXISTS film_fts_simple_idx;
+DROP INDEX IF EXISTS film_fts_russian_idx;
+DROP INDEX IF EXISTS film_fts_english_idx;
@@ -0,0 +1,14 @@
+-- Full-text search. The expressions must stay identical to the ones built in
+-- repository/search.go, otherwise the planner will not use the indexes.
+CREATE INDEX IF NOT EXISTS film_fts_english_idx ON "film" USING GIN (
+    (setweight(to_tsvector('english'::regconfig, film_name), 'A') ||
+     setweight(to_tsvector('english'::regconfig, COALESCE(description, '')), 'B')));
+CREATE INDEX IF NOT EXISTS film_fts_russian_idx ON "film" USING GIN (
+    (setweight(to_tsvector('russian'::regconfig, film_name), 'A') ||
+     setweight(to_tsvector('russian'::regconfig, COALESCE(description, '')), 'B')));
+CREATE INDEX IF NOT EXISTS film_fts_simple_idx ON "film" USING GIN (
+    (setweight(to_tsvector('simple'::regconfig, film_name), 'A') ||
+     setweight(to_tsvector('simple'::regconfig, COALESCE(description, '')), 'B')));
+
+-- Names are not stemmed, so actors are always searched with the "simple" configuration.
+CREATE INDEX IF NOT EXISTS actor_fts_idx ON "actor" USING GIN (to_tsvector('simple'::regconfig, actor_name));
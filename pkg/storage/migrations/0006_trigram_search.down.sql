@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS film_name_trgm_idx;
+DROP INDEX IF EXISTS actor_name_trgm_idx;
+
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,5 @@
+-- Typo tolerant search by name ("<%" operator and word_similarity).
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS actor_name_trgm_idx ON "actor" USING GIN (actor_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS film_name_trgm_idx ON "film" USING GIN (film_name gin_trgm_ops);
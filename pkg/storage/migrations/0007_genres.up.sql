@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "genre"
+(
+    id         serial      not null unique,
+    genre_name varchar(50) not null unique
+);
+
+CREATE TABLE IF NOT EXISTS "film_genre"
+(
+    film_id  integer not null references "film" (id) on delete cascade,
+    genre_id integer not null references "genre" (id) on delete cascade,
+    primary key (film_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS film_genre_genre_id_idx ON "film_genre" (genre_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS actor_film_film_id_billing_order_idx;
+
+ALTER TABLE "actor_film"
+    DROP COLUMN IF EXISTS billing_order,
+    DROP COLUMN IF EXISTS credit_type,
+    DROP COLUMN IF EXISTS character_name;
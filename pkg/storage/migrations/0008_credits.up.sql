@@ -0,0 +1,9 @@
+-- Every actor_film row is a credit: who the actor plays, how big the part is
+-- and where the actor is billed. Credits without billing_order go last.
+ALTER TABLE "actor_film"
+    ADD COLUMN IF NOT EXISTS character_name varchar(150),
+    ADD COLUMN IF NOT EXISTS credit_type    varchar(20) not null default 'supporting'
+        CHECK (credit_type IN ('lead', 'supporting', 'cameo', 'voice')),
+    ADD COLUMN IF NOT EXISTS billing_order  integer CHECK (billing_order > 0);
+
+CREATE INDEX IF NOT EXISTS actor_film_film_id_billing_order_idx ON "actor_film" (film_id, billing_order);
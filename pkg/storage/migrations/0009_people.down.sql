@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "film_crew";
+
+ALTER TABLE "person" RENAME COLUMN person_name TO actor_name;
+ALTER TABLE "person" RENAME TO "actor";
@@ -0,0 +1,15 @@
+-- Actors become people: the same person can act (actor_film) and work on
+-- the crew (film_crew). Index and constraint names keep their old prefixes.
+ALTER TABLE "actor" RENAME TO "person";
+ALTER TABLE "person" RENAME COLUMN actor_name TO person_name;
+
+CREATE TABLE IF NOT EXISTS "film_crew"
+(
+    film_id   integer     not null references "film" (id) on delete cascade,
+    person_id integer     not null references "person" (id) on delete cascade,
+    job       varchar(20) not null
+        CHECK (job IN ('director', 'writer', 'producer', 'composer', 'cinematographer')),
+    primary key (film_id, person_id, job)
+);
+
+CREATE INDEX IF NOT EXISTS film_crew_person_id_job_idx ON "film_crew" (person_id, job);
@@ -0,0 +1,15 @@
+-- One rating per user and film, optionally with a text review.
+CREATE TABLE IF NOT EXISTS "review"
+(
+    id          serial        not null unique,
+    film_id     integer       not null references "film" (id) on delete cascade,
+    user_id     integer       not null references "auth" (id) on delete cascade,
+    rating      smallint      not null CHECK (rating BETWEEN 1 AND 10),
+    review_text varchar(5000),
+    created_at  timestamptz   not null default now(),
+    updated_at  timestamptz   not null default now(),
+    unique (film_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS review_film_id_created_at_idx ON "review" (film_id, created_at, id);
+CREATE INDEX IF NOT EXISTS review_film_id_rating_idx ON "review" (film_id, rating, id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "watchlist_item";
+DROP TABLE IF EXISTS "watchlist";
@@ -0,0 +1,24 @@
+-- Named film lists of a user. A list shared by link has a share_token,
+-- anyone knowing it can read the list.
+CREATE TABLE IF NOT EXISTS "watchlist"
+(
+    id          serial       not null unique,
+    user_id     integer      not null references "auth" (id) on delete cascade,
+    list_name   varchar(100) not null,
+    share_token varchar(64) unique,
+    created_at  timestamptz  not null default now(),
+    unique (user_id, list_name)
+);
+
+CREATE TABLE IF NOT EXISTS "watchlist_item"
+(
+    watchlist_id integer     not null references "watchlist" (id) on delete cascade,
+    film_id      integer     not null references "film" (id) on delete cascade,
+    status       varchar(20) not null CHECK (status IN ('want_to_watch', 'watched')),
+    watched_at   date,
+    added_at     timestamptz not null default now(),
+    primary key (watchlist_id, film_id),
+    CHECK ((status = 'watched') = (watched_at IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS watchlist_item_film_id_idx ON "watchlist_item" (film_id);
@@ -0,0 +1,2 @@
+ALTER TABLE "film" DROP COLUMN IF EXISTS external_id;
+ALTER TABLE "person" DROP COLUMN IF EXISTS external_id;
@@ -0,0 +1,7 @@
+-- Rows synced from an outside catalogue remember where they came from, e.g.
+-- "imdb:tt0116477", so the next sync updates them instead of adding copies.
+ALTER TABLE "person" ADD COLUMN IF NOT EXISTS external_id varchar(64);
+ALTER TABLE "film" ADD COLUMN IF NOT EXISTS external_id varchar(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS person_external_id_key ON "person" (external_id);
+CREATE UNIQUE INDEX IF NOT EXISTS film_external_id_key ON "film" (external_id);
@@ -0,0 +1,16 @@
+-- The trash is emptied, the names it holds could break the constraints again.
+DELETE FROM "film" WHERE deleted_at IS NOT NULL;
+DELETE FROM "person" WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS film_deleted_at_idx;
+DROP INDEX IF EXISTS person_deleted_at_idx;
+
+DROP INDEX IF EXISTS film_name_release_date_key;
+ALTER TABLE "film" ADD CONSTRAINT film_name_release_date_key UNIQUE (film_name, release_date);
+DROP INDEX IF EXISTS actor_name_bdate_key;
+ALTER TABLE "person" ADD CONSTRAINT actor_name_bdate_key UNIQUE (person_name, bdate);
+
+ALTER TABLE "film" DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE "film" DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE "person" DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE "person" DROP COLUMN IF EXISTS deleted_at;
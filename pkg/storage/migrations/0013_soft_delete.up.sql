@@ -0,0 +1,15 @@
+-- Deleted people and films stay in the trash with their relations until they
+-- are restored or purged. A name only has to be unique among the rows that are
+-- not deleted, so a deleted entry does not block adding it again.
+ALTER TABLE "person" ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+ALTER TABLE "person" ADD COLUMN IF NOT EXISTS deleted_by integer references "auth" (id) on delete set null;
+ALTER TABLE "film" ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+ALTER TABLE "film" ADD COLUMN IF NOT EXISTS deleted_by integer references "auth" (id) on delete set null;
+
+ALTER TABLE "person" DROP CONSTRAINT IF EXISTS actor_name_bdate_key;
+CREATE UNIQUE INDEX IF NOT EXISTS actor_name_bdate_key ON "person" (person_name, bdate) WHERE deleted_at IS NULL;
+ALTER TABLE "film" DROP CONSTRAINT IF EXISTS film_name_release_date_key;
+CREATE UNIQUE INDEX IF NOT EXISTS film_name_release_date_key ON "film" (film_name, release_date) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS person_deleted_at_idx ON "person" (deleted_at, id) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS film_deleted_at_idx ON "film" (deleted_at, id) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,31 @@
+-- Every change of the catalogue with who made it, in which request, and the
+-- fields it changed. Rows outlive the users and entries they name, so there
+-- are no foreign keys, and the trigger keeps the table append-only.
+CREATE TABLE IF NOT EXISTS "audit_log"
+(
+    id         bigserial   not null unique,
+    user_id    integer,
+    entity     varchar(20) not null,
+    entity_id  integer,
+    operation  varchar(20) not null,
+    before     jsonb,
+    after      jsonb,
+    request_id varchar(64),
+    created_at timestamptz not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON "audit_log" (created_at, id);
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON "audit_log" (entity, entity_id, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_user_id_idx ON "audit_log" (user_id, created_at);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON "audit_log";
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON "audit_log"
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
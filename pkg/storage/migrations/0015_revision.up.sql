@@ -0,0 +1,13 @@
+-- Numbered snapshots of the editable fields of actors and films, one per
+-- change. Entities changed before revisions were kept get their state before
+-- the first change as revision 1.
+CREATE TABLE IF NOT EXISTS "revision"
+(
+    entity     varchar(20) not null,
+    entity_id  integer     not null,
+    number     integer     not null,
+    user_id    integer,
+    data       jsonb       not null,
+    created_at timestamptz not null default now(),
+    PRIMARY KEY (entity, entity_id, number)
+);
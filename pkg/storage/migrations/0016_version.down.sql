@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS person_version ON "person";
+DROP TRIGGER IF EXISTS film_version ON "film";
+DROP FUNCTION IF EXISTS bump_version();
+
+ALTER TABLE "person" DROP COLUMN IF EXISTS version;
+ALTER TABLE "film" DROP COLUMN IF EXISTS version;
@@ -0,0 +1,22 @@
+-- Every update of an actor or a film row makes a new version, whoever makes
+-- it; the API hands it out in ETags and checks it against If-Match.
+ALTER TABLE "person" ADD COLUMN IF NOT EXISTS version integer not null default 1;
+ALTER TABLE "film" ADD COLUMN IF NOT EXISTS version integer not null default 1;
+
+CREATE OR REPLACE FUNCTION bump_version() RETURNS trigger AS
+$$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS person_version ON "person";
+CREATE TRIGGER person_version
+    BEFORE UPDATE ON "person"
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
+
+DROP TRIGGER IF EXISTS film_version ON "film";
+CREATE TRIGGER film_version
+    BEFORE UPDATE ON "film"
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
@@ -0,0 +1,4 @@
+ALTER TABLE "auth" DROP CONSTRAINT IF EXISTS auth_role_check;
+ALTER TABLE "auth" ALTER COLUMN role DROP NOT NULL;
+
+UPDATE "auth" SET role = 1 WHERE role <> 0;
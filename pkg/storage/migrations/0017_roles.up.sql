@@ -0,0 +1,8 @@
+-- Roles get names: 0 viewer, 1 editor, 2 moderator, 3 admin. Every user who
+-- could change the catalogue so far could also read the audit log, so they
+-- become admins and keep all of their rights.
+UPDATE "auth" SET role = 0 WHERE role IS NULL;
+UPDATE "auth" SET role = 3 WHERE role <> 0;
+
+ALTER TABLE "auth" ALTER COLUMN role SET NOT NULL;
+ALTER TABLE "auth" ADD CONSTRAINT auth_role_check CHECK (role BETWEEN 0 AND 3);
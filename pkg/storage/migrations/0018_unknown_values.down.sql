@@ -0,0 +1,8 @@
+-- A birth date cannot be made up, so the people without one are removed.
+DELETE FROM "person" WHERE bdate IS NULL;
+UPDATE "person" SET sex = '' WHERE sex IS NULL;
+UPDATE "film" SET rating = 0 WHERE rating IS NULL;
+
+ALTER TABLE "person" ALTER COLUMN sex SET NOT NULL;
+ALTER TABLE "person" ALTER COLUMN bdate SET NOT NULL;
+ALTER TABLE "film" ALTER COLUMN rating SET NOT NULL;
@@ -0,0 +1,5 @@
+-- A synced person or film keeps what the outside catalogue does not know empty:
+-- crew members often have no sex or birth date and many titles have no rating.
+ALTER TABLE "person" ALTER COLUMN sex DROP NOT NULL;
+ALTER TABLE "person" ALTER COLUMN bdate DROP NOT NULL;
+ALTER TABLE "film" ALTER COLUMN rating DROP NOT NULL;